@@ -0,0 +1,73 @@
+// Command htmlxlint check the selectors of htmlx tagged types against a directory of saved pages.
+//
+// Usage:
+//
+//	htmlxlint -types ./internal/models,./internal/scrapers -pages ./testdata/pages [-config lint.json] [-format text|json]
+//
+// The config is a JSON document telling which pages each type is linted against and the elements it is parsed from:
+//
+//	{"targets": [{"type": "PlayerOverviewStatSchema", "pages": "match_*.html", "scope": "table.mod-overview tbody tr"}]}
+//
+// The exit code is 1 if any error is found
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
+)
+
+func main() {
+	typeDirs := flag.String("types", ".", "Comma separated directories to load the htmlx tagged types and the parsers from")
+	pagesDir := flag.String("pages", "", "Directory of the saved html pages")
+	configPath := flag.String("config", "", "Path of the JSON lint config, every type is linted against every page if empty")
+	format := flag.String("format", "text", "Output format, text or json")
+	dateFormat := flag.String("date-format", "", "Date format of the time.Time fields")
+	flag.Parse()
+
+	if *pagesDir == "" {
+		fmt.Fprintln(os.Stderr, "-pages is required")
+		os.Exit(2)
+	}
+
+	var lintConfig htmlx.LintConfig
+	if *configPath != "" {
+		var err error
+		if lintConfig, err = htmlx.LoadLintConfig(*configPath); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(2)
+		}
+	}
+
+	var opts []htmlx.Option
+	if *dateFormat != "" {
+		opts = append(opts, htmlx.SetDateFormat(*dateFormat))
+	}
+
+	report, err := htmlx.LintDirs(strings.Split(*typeDirs, ","), *pagesDir, lintConfig, opts...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+
+	switch *format {
+	case "json":
+		err = report.WriteJSON(os.Stdout)
+	case "text":
+		err = report.WriteText(os.Stdout)
+	default:
+		err = fmt.Errorf("unknown format %s", *format)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+
+	if report.Errors() > 0 {
+		os.Exit(1)
+	}
+}
@@ -3,7 +3,8 @@ module github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx
 go 1.24.2
 
 require (
-	github.com/PuerkitoBio/goquery v1.10.3 // indirect
-	github.com/andybalholm/cascadia v1.3.3 // indirect
-	golang.org/x/net v0.39.0 // indirect
+	github.com/PuerkitoBio/goquery v1.10.3
+	golang.org/x/net v0.39.0
 )
+
+require github.com/andybalholm/cascadia v1.3.3
@@ -2,14 +2,18 @@ package htmlx
 
 import (
 	"context"
+	"encoding"
+	"errors"
 	"fmt"
 	"maps"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
 )
 
 type Config struct {
@@ -19,12 +23,18 @@ type Config struct {
 	parseAllFields      bool
 	noMissingAttributes bool
 	noPassThroughStruct bool
+	selectorReport      *SelectorReport
+	formatters          map[string]Formatter
+	tableHeader         *TableHeader
+	matrixCell          *matrixCell
+	trace               *Trace
 }
 
 func NewDefaultConfig() *Config {
 	return &Config{
 		dateFormat: "2006-01-02T15:04:05Z07:00",
 		parsers:    map[string]Parser{},
+		formatters: map[string]Formatter{},
 	}
 }
 
@@ -37,7 +47,8 @@ func SetDateFormat(format string) Option {
 	}
 }
 
-// Set custom parsers for values, name of the parser is corresponded o the name in parser field of the struct tags
+// Set custom parsers for values, name of the parser is corresponded o the name in parser field of the struct tags.
+// These parsers take precedence over the ones registered with [RegisterParser] and the built-in ones
 func SetParsers(parsers map[string]Parser) Option {
 	return func(c *Config) {
 		maps.Copy(c.parsers, parsers)
@@ -72,18 +83,93 @@ func SetNoPassThroughStruct(forbid bool) Option {
 	}
 }
 
+// Record the matched selectors into the report, see [SelectorReport]
+func SetSelectorReport(report *SelectorReport) Option {
+	return func(c *Config) {
+		c.selectorReport = report
+	}
+}
+
+// Record how every field is parsed into the trace, see [Trace]
+func SetTrace(trace *Trace) Option {
+	return func(c *Config) {
+		c.trace = trace
+	}
+}
+
+// Set formatters for [Marshal], the key is the parser tag the formatter invert.
+// These formatters take precedence over the ones registered with [RegisterFormatter]
+func SetFormatters(formatters map[string]Formatter) Option {
+	return func(c *Config) {
+		maps.Copy(c.formatters, formatters)
+	}
+}
+
+// Set the header used to locate the cells of the fields with a column tag.
+// It is only needed when the row is detached from its table, e.g a cloned row
+func SetTableHeader(header TableHeader) Option {
+	return func(c *Config) {
+		c.tableHeader = &header
+	}
+}
+
 type HtmlxTags struct {
-	selector string
-	source   string
-	parser   string
+	selectors  []string
+	source     string
+	parser     string
+	regex      *regexp.Regexp
+	required   *bool
+	defaultVal *string
+	columns    []string
+	matrix     string
+	// Steps of the json path, nil if the field has no jsonpath tag
+	jsonPath    []jsonPathStep
+	jsonPathTag string
+}
+
+// Split the selector tag into the ordered list of fallback selectors, separated by "||"
+func splitSelectors(selectorTag string) []string {
+	var selectors []string
+
+	for _, selector := range strings.Split(selectorTag, "||") {
+		if selector = strings.TrimSpace(selector); selector != "" {
+			selectors = append(selectors, selector)
+		}
+	}
+
+	return selectors
 }
 
+// Returned by [initializeHtmlxTags] for a field without selector, the field is not parsed unless every field must be.
+// The other tag errors are malformed tags and are always returned
+var errMissingSelector = errors.New("Missing selector")
+
 func initializeHtmlxTags(fieldType reflect.StructField) (HtmlxTags, error) {
 	var htmlxTags HtmlxTags
 
-	htmlxTags.selector = fieldType.Tag.Get("selector")
-	if htmlxTags.selector == "" {
-		return htmlxTags, fmt.Errorf("Missing selector for field '%s'", fieldType.Name)
+	htmlxTags.selectors = splitSelectors(fieldType.Tag.Get("selector"))
+	htmlxTags.columns = splitSelectors(fieldType.Tag.Get("column"))
+	htmlxTags.matrix = fieldType.Tag.Get("matrix")
+
+	switch htmlxTags.matrix {
+	case "", "rowHeader", "columnHeader", "cell":
+	default:
+		return htmlxTags, fmt.Errorf("Invalid matrix tag for field '%s': %s", fieldType.Name, htmlxTags.matrix)
+	}
+
+	if jsonPathTag, ok := fieldType.Tag.Lookup("jsonpath"); ok {
+		jsonPath, err := parseJSONPath(jsonPathTag)
+		if err != nil {
+			return htmlxTags, fmt.Errorf("Invalid jsonpath tag for field '%s': %s", fieldType.Name, err.Error())
+		}
+
+		htmlxTags.jsonPath = jsonPath
+		htmlxTags.jsonPathTag = jsonPathTag
+	}
+
+	// Table and json fields without a selector are parsed from the cell or the element itself
+	if len(htmlxTags.selectors) == 0 && len(htmlxTags.columns) == 0 && htmlxTags.matrix == "" && htmlxTags.jsonPath == nil {
+		return htmlxTags, fmt.Errorf("%w for field '%s'", errMissingSelector, fieldType.Name)
 	}
 
 	htmlxTags.source = fieldType.Tag.Get("source")
@@ -93,6 +179,28 @@ func initializeHtmlxTags(fieldType reflect.StructField) (HtmlxTags, error) {
 
 	htmlxTags.parser = fieldType.Tag.Get("parser")
 
+	if regexStr := fieldType.Tag.Get("regex"); regexStr != "" {
+		regex, err := regexp.Compile(regexStr)
+		if err != nil {
+			return htmlxTags, fmt.Errorf("Invalid regex for field '%s': %s", fieldType.Name, err.Error())
+		}
+
+		htmlxTags.regex = regex
+	}
+
+	if requiredStr, ok := fieldType.Tag.Lookup("required"); ok {
+		required, err := strconv.ParseBool(requiredStr)
+		if err != nil {
+			return htmlxTags, fmt.Errorf("Invalid required tag for field '%s': %s", fieldType.Name, requiredStr)
+		}
+
+		htmlxTags.required = &required
+	}
+
+	if defaultVal, ok := fieldType.Tag.Lookup("default"); ok {
+		htmlxTags.defaultVal = &defaultVal
+	}
+
 	return htmlxTags, nil
 }
 
@@ -118,73 +226,45 @@ func ParseFromDocument(
 	return ParseFromSelection(s, doc.Selection, opts...)
 }
 
-// Parse the content from HTML string to struct with context
+// Parse the content from HTML string to struct with context.
+// The context is checked before the document is created and between every field,
+// cancellation is returned as ctx.Err() wrapped with the path of the field reached
 func ParseFromStringWithContext(
 	ctx context.Context,
 	s any,
 	content string,
 	opts ...Option,
 ) error {
-	errChan := make(chan error)
-
-	go func() {
-		doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
-		if err != nil {
-			errChan <- err
-		}
-
-		errChan <- ParseFromSelection(s, doc.Selection, opts...)
-	}()
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("Parsing cancelled before creating document: %w", err)
+	}
 
-	select {
-	case err := <-errChan:
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
 		return err
 	}
+
+	return ParseFromSelectionWithContext(ctx, s, doc.Selection, opts...)
 }
 
-// Parse the content from goquery Document to struct with context
+// Parse the content from goquery Document to struct with context.
+// The context is checked between every field, see [ParseFromSelectionWithContext]
 func ParseFromDocumentWithContext(
 	ctx context.Context,
 	s any,
 	doc *goquery.Document,
 	opts ...Option,
 ) error {
-	errChan := make(chan error)
-
-	go func() {
-		errChan <- ParseFromSelection(s, doc.Selection, opts...)
-	}()
-
-	select {
-	case err := <-errChan:
-		return err
-	}
+	return ParseFromSelectionWithContext(ctx, s, doc.Selection, opts...)
 }
 
-// Parse the content from goquery Selection to struct with context
+// Parse the content from goquery Selection to struct with context.
+// The context is checked between every field, cancellation is returned as ctx.Err() wrapped with the path of the field reached
 func ParseFromSelectionWithContext(
 	ctx context.Context,
 	s any,
 	sel *goquery.Selection,
 	opts ...Option,
-) error {
-	errChan := make(chan error)
-
-	go func() {
-		errChan <- ParseFromSelection(s, sel, opts...)
-	}()
-
-	select {
-	case err := <-errChan:
-		return err
-	}
-}
-
-// Parse the content from goquery Document to struct
-func ParseFromSelection(
-	s any,
-	sel *goquery.Selection,
-	opts ...Option,
 ) error {
 	v := reflect.ValueOf(s)
 
@@ -200,13 +280,22 @@ func ParseFromSelection(
 		opt(config)
 	}
 
-	if err := parseFromReflectValue(v, sel, config); err != nil {
+	if err := parseFromReflectValue(ctx, v, sel, config, ""); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// Parse the content from goquery Document to struct
+func ParseFromSelection(
+	s any,
+	sel *goquery.Selection,
+	opts ...Option,
+) error {
+	return ParseFromSelectionWithContext(context.Background(), s, sel, opts...)
+}
+
 // Check if the struct is of supported struct types
 func isStructToParse(v reflect.Value) bool {
 	switch v.Type() {
@@ -215,13 +304,25 @@ func isStructToParse(v reflect.Value) bool {
 		// NOTE: Add supported types here
 	}
 
-	return false
+	// Structs those unmarshal themselves from text are parsed as values
+	return reflect.PointerTo(v.Type()).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem())
+}
+
+// Join the parent path and the field name to a dot separated field path
+func fieldPath(parentPath, fieldName string) string {
+	if parentPath == "" {
+		return fieldName
+	}
+
+	return parentPath + "." + fieldName
 }
 
 func parseFromReflectValue(
+	ctx context.Context,
 	v reflect.Value,
 	sel *goquery.Selection,
 	config *Config,
+	parentPath string,
 ) error {
 	if v.Kind() != reflect.Struct {
 		return fmt.Errorf("value is not a struct")
@@ -233,10 +334,22 @@ func parseFromReflectValue(
 
 		fieldType := t.Field(i)
 		fieldVal := v.Field(i)
+		path := fieldPath(parentPath, fieldType.Name)
+
+		if err = ctx.Err(); err != nil {
+			return fmt.Errorf("Parsing cancelled at field '%s': %w", path, err)
+		}
+
+		_, hasJSONPath := fieldType.Tag.Lookup("jsonpath")
 
-		if fieldVal.Kind() == reflect.Struct && !isStructToParse(fieldVal) {
+		if fieldVal.Kind() == reflect.Struct && !isStructToParse(fieldVal) && !hasJSONPath {
 			if !config.noPassThroughStruct {
-				if err = parseFromReflectValue(fieldVal, sel, config); err != nil {
+				if err = parseFromReflectValue(ctx, fieldVal, sel, config, path); err != nil {
+					if ctx.Err() != nil {
+						// Cancellation errors already carry the full field path
+						return err
+					}
+
 					return fmt.Errorf("Error parsing value to field '%s' : %s", fieldType.Name, err.Error())
 				}
 			}
@@ -245,32 +358,216 @@ func parseFromReflectValue(
 		}
 		htmlxTags, err := initializeHtmlxTags(fieldType)
 		if err != nil {
-			if config.parseAllFields {
+			if config.parseAllFields || !errors.Is(err, errMissingSelector) {
 				return fmt.Errorf("Error extracting tags from field '%s': %s", fieldType.Name, err.Error())
 			}
 
+			if config.trace != nil {
+				config.trace.add(FieldTrace{Field: path, Skipped: true, SkipReason: err.Error()})
+			}
+
 			continue
 		}
 
-		htmlElement := sel.Find(htmlxTags.selector)
+		var fieldTrace *FieldTrace
+		if config.trace != nil {
+			fieldTrace = &FieldTrace{
+				Field:     path,
+				Selectors: htmlxTags.selectors,
+				Source:    htmlxTags.source,
+				Parser:    htmlxTags.parser,
+			}
+		}
+
+		err = parseField(fieldVal, fieldType, sel, htmlxTags, config, path, fieldTrace)
+
+		if fieldTrace != nil {
+			if err != nil {
+				fieldTrace.Error = err.Error()
+			} else {
+				fieldTrace.setResult(fieldVal)
+			}
 
-		if config.noEmptySelection && htmlElement.Length() == 0 {
-			return fmt.Errorf("Error locating html element for field '%s'", fieldType.Name)
+			config.trace.add(*fieldTrace)
 		}
 
-		rawVal, err := getRawValue(fieldType, htmlElement, htmlxTags.source, config)
 		if err != nil {
-			return fmt.Errorf("Error getting raw value from field '%s': %s", fieldType.Name, err.Error())
+			return err
 		}
+	}
 
-		if err = parseValue(fieldVal, rawVal, config, htmlxTags); err != nil {
-			return fmt.Errorf("Error parsing value to field '%s': %s", fieldType.Name, err.Error())
-		}
+	return nil
+}
+
+// Parse the value of a single field, the steps are recorded into the field trace if it isn't nil
+func parseField(
+	fieldVal reflect.Value,
+	fieldType reflect.StructField,
+	sel *goquery.Selection,
+	htmlxTags HtmlxTags,
+	config *Config,
+	path string,
+	fieldTrace *FieldTrace,
+) error {
+	root, err := fieldRoot(sel, htmlxTags, config)
+	if err != nil {
+		return fmt.Errorf("Error locating table cell for field '%s': %s", fieldType.Name, err.Error())
+	}
+
+	htmlElement, selectorIdx := findHtmlElement(root, htmlxTags.selectors)
+
+	if config.selectorReport != nil {
+		config.selectorReport.add(path, htmlxTags.selectors, selectorIdx)
+	}
+
+	fieldTrace.setMatch(htmlElement, htmlxTags.selectors, selectorIdx)
+
+	// Per field tags override the global option, a field with a default value is optional unless stated otherwise
+	required := config.noEmptySelection && htmlxTags.defaultVal == nil
+	if htmlxTags.required != nil {
+		required = *htmlxTags.required
+	}
+
+	if required && htmlElement.Length() == 0 {
+		return fmt.Errorf("Error locating html element for field '%s'", fieldType.Name)
 	}
 
+	if fieldVal.Type() == selectionType {
+		fieldVal.Set(reflect.ValueOf(htmlElement))
+		return nil
+	}
+
+	rawVal, missingReason, err := fieldRawValue(fieldType, htmlElement, htmlxTags, config)
+	if err != nil {
+		return fmt.Errorf("Error getting raw value from field '%s': %s", fieldType.Name, err.Error())
+	}
+
+	defaultUsed := htmlxTags.defaultVal != nil && strings.TrimSpace(rawVal) == ""
+	if defaultUsed {
+		rawVal = *htmlxTags.defaultVal
+	}
+
+	fieldTrace.setRaw(rawVal, defaultUsed)
+
+	skipReason, err := parseValue(fieldVal, rawVal, config, htmlxTags)
+	if err != nil {
+		return fmt.Errorf("Error parsing value to field '%s': %s", fieldType.Name, err.Error())
+	}
+
+	if skipReason != "" && !defaultUsed && missingReason != "" {
+		skipReason = missingReason
+	}
+
+	fieldTrace.skip(skipReason)
+
 	return nil
 }
 
+// Return the raw value of the field before the default value is applied, extracted from the source of the element
+// then narrowed by the regex and the json path. The reason tell why the value is missing, it is empty otherwise
+func fieldRawValue(
+	fieldType reflect.StructField,
+	htmlElement *goquery.Selection,
+	htmlxTags HtmlxTags,
+	config *Config,
+) (string, string, error) {
+	// The source is checked even without element so that invalid sources and missing attributes are reported
+	rawVal, err := getRawValue(fieldType, htmlElement, htmlxTags.source, config)
+	if err != nil {
+		return "", "", err
+	}
+
+	if htmlElement.Length() == 0 {
+		return "", "no element matched", nil
+	}
+
+	if attrName, ok := strings.CutPrefix(htmlxTags.source, "attr="); ok {
+		if _, exists := htmlElement.Attr(attrName); !exists {
+			return "", fmt.Sprintf("attribute %s missing", attrName), nil
+		}
+	}
+
+	if htmlxTags.jsonPath != nil {
+		jsonVal, found, err := extractJSONValue(fieldType, htmlElement, htmlxTags, config)
+		if err != nil || found {
+			return jsonVal, "", err
+		}
+
+		return "", fmt.Sprintf("json path %s not found", htmlxTags.jsonPathTag), nil
+	}
+
+	if htmlxTags.regex != nil {
+		regexVal := extractRegexValue(htmlxTags.regex, rawVal)
+		if strings.TrimSpace(rawVal) != "" && regexVal == "" {
+			return "", "regex didn't match", nil
+		}
+
+		rawVal = regexVal
+	}
+
+	return rawVal, "", nil
+}
+
+// Return the selection of the first selector matching any element and its index, -1 is returned if none matches.
+// Without selectors the selection itself is returned
+func findHtmlElement(sel *goquery.Selection, selectors []string) (*goquery.Selection, int) {
+	if len(selectors) == 0 {
+		if sel.Length() == 0 {
+			return sel, -1
+		}
+
+		return sel, 0
+	}
+
+	for i, selector := range selectors {
+		if htmlElement := sel.Find(selector); htmlElement.Length() > 0 {
+			return htmlElement, i
+		}
+	}
+
+	return sel.Find(selectors[0]), -1
+}
+
+// Return the text of the n-th (0-indexed) non blank text node which is a direct child of the first element
+func getChildTextNode(htmlElement *goquery.Selection, n int) string {
+	var textNodeCount int
+
+	for _, node := range htmlElement.First().Contents().Nodes {
+		if node.Type != html.TextNode || strings.TrimSpace(node.Data) == "" {
+			continue
+		}
+
+		if textNodeCount == n {
+			return node.Data
+		}
+
+		textNodeCount++
+	}
+
+	return ""
+}
+
+// Extract the value matched by the regex, the first capture group is used if the regex has any
+func extractRegexValue(regex *regexp.Regexp, rawVal string) string {
+	matches := regex.FindStringSubmatch(rawVal)
+	if matches == nil {
+		return ""
+	}
+
+	if len(matches) > 1 {
+		return matches[1]
+	}
+
+	return matches[0]
+}
+
+// Get the raw string from the html element based on the source:
+//   - content: text of the element with its children removed (default)
+//   - text: text of the element including its descendants
+//   - html: inner HTML of the element
+//   - outerHtml: outer HTML of the element
+//   - text=N: the N-th (0-indexed) non blank text node which is a direct child of the element
+//   - attr=NAME: value of the attribute NAME
 func getRawValue(
 	fieldType reflect.StructField,
 	htmlElement *goquery.Selection,
@@ -279,6 +576,19 @@ func getRawValue(
 ) (string, error) {
 	if source == "content" {
 		return htmlElement.Clone().Children().Remove().End().Text(), nil
+	} else if source == "text" {
+		return htmlElement.Text(), nil
+	} else if source == "html" {
+		return htmlElement.Html()
+	} else if source == "outerHtml" {
+		if htmlElement.Length() == 0 {
+			return "", nil
+		}
+
+		return goquery.OuterHtml(htmlElement.First())
+	} else if regexp.MustCompile(`^text=[0-9]+$`).MatchString(source) {
+		n, _ := strconv.Atoi(source[5:])
+		return getChildTextNode(htmlElement, n), nil
 	} else if regexp.MustCompile(`^attr=[a-zA-Z-0-9]+$`).MatchString(source) {
 		var exists bool
 		attrName := source[5:]
@@ -297,98 +607,149 @@ func getRawValue(
 	}
 }
 
+// Parse the value using the pipeline in the parser tag, e.g `parser:"trim|stripSuffix(%)|float"`
 func parseValueWithCustomParser(
 	fieldVal reflect.Value,
 	rawVal string,
 	config *Config,
 	parserName string,
-) error {
-	parser, ok := config.parsers[parserName]
-	if !ok {
-		return fmt.Errorf("parser %s is not recognizable", parserName)
+) (string, error) {
+	pipeline, err := buildPipeline(parserName, config)
+	if err != nil {
+		return "", err
 	}
 
-	val, err := parser(rawVal)
+	val, err := runPipeline(pipeline, rawVal)
 	if err != nil {
-		return fmt.Errorf("parser '%s' error: %s", parserName, err.Error())
+		return "", err
 	}
 
+	skipReason := ""
 	if val == nil {
-		fieldVal.Set(reflect.Zero(fieldVal.Type()))
-		return nil
+		skipReason = "parser returned nil"
+	} else if strVal, ok := val.(string); ok && strings.TrimSpace(strVal) == "" && fieldVal.Kind() != reflect.String {
+		skipReason = "parser returned empty string"
 	}
 
-	processedVal := reflect.ValueOf(val)
-	if !processedVal.IsValid() {
-		return fmt.Errorf("processed value using parser %s is invalid", parserName)
+	return skipReason, assignParsedValue(fieldVal, val, config, parserName)
+}
+
+// Set the integer value to the field if it doesn't overflow the field type
+func setIntValue(fieldVal reflect.Value, rawVal string) error {
+	intVal, err := IntParser(rawVal)
+	if err != nil {
+		return fmt.Errorf("Int parser error: %s", err.Error())
 	}
 
-	if !processedVal.Type().AssignableTo(fieldVal.Type()) {
-		return fmt.Errorf(
-			"Incompatible type when using parser %s, want %s, get %s",
-			parserName,
-			fieldVal.Type().String(),
-			processedVal.Type().String(),
-		)
+	i := int64(intVal.(int))
+
+	if fieldVal.Kind() >= reflect.Uint && fieldVal.Kind() <= reflect.Uintptr {
+		if i < 0 || fieldVal.OverflowUint(uint64(i)) {
+			return fmt.Errorf("%d overflows %s", i, fieldVal.Type().String())
+		}
+
+		fieldVal.SetUint(uint64(i))
+		return nil
 	}
 
-	fieldVal.Set(processedVal)
+	if fieldVal.OverflowInt(i) {
+		return fmt.Errorf("%d overflows %s", i, fieldVal.Type().String())
+	}
 
+	fieldVal.SetInt(i)
 	return nil
 }
 
+// Parse the raw value based on the field type, named types are parsed based on their underlying kind
 func parseSupportedValues(fieldVal reflect.Value, rawVal string, config *Config, htmlxTags HtmlxTags) error {
 	switch fieldVal.Type() {
-	case reflect.TypeOf(""):
+	case reflect.TypeOf(time.Time{}):
+		dateVal, err := DateParser(config.dateFormat)(rawVal)
+		if err != nil {
+			return fmt.Errorf("Date parser error: %s", err.Error())
+		}
+
+		fieldVal.Set(reflect.ValueOf(dateVal))
+		return nil
+	case reflect.TypeOf(time.Duration(0)):
+		durationVal, err := DurationParser(rawVal)
+		if err != nil {
+			return fmt.Errorf("Duration parser error: %s", err.Error())
+		}
+
+		fieldVal.Set(reflect.ValueOf(durationVal))
+		return nil
+	}
+
+	if fieldVal.CanAddr() {
+		if unmarshaler, ok := fieldVal.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := unmarshaler.UnmarshalText([]byte(strings.TrimSpace(rawVal))); err != nil {
+				return fmt.Errorf("Text unmarshaler error: %s", err.Error())
+			}
+
+			return nil
+		}
+	}
+
+	switch fieldVal.Kind() {
+	case reflect.String:
 		strVal, _ := StringParserClean(rawVal)
-		fieldVal.Set(reflect.ValueOf(strVal))
-	case reflect.TypeOf(0):
-		intVal, err := IntParser(rawVal)
+		fieldVal.SetString(strVal.(string))
+	case reflect.Bool:
+		boolVal, err := BoolParser(rawVal)
 		if err != nil {
-			return fmt.Errorf("Int parser error: %s", err.Error())
+			return fmt.Errorf("Bool parser error: %s", err.Error())
 		}
-		fieldVal.Set(reflect.ValueOf(intVal))
-	case reflect.TypeOf(0.5):
+
+		fieldVal.SetBool(boolVal.(bool))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return setIntValue(fieldVal, rawVal)
+	case reflect.Float32, reflect.Float64:
 		floatVal, err := FloatParser(rawVal)
 		if err != nil {
 			return fmt.Errorf("Float parser error: %s", err.Error())
 		}
-		fieldVal.Set(reflect.ValueOf(floatVal))
-	case reflect.TypeOf(time.Time{}):
-		dateVal, err := DateParser(config.dateFormat)(rawVal)
-		if err != nil {
-			return fmt.Errorf("Date parser error: %s", err.Error())
-		}
 
-		fieldVal.Set(reflect.ValueOf(dateVal))
-	default:
-		if fieldVal.Kind() == reflect.Ptr {
-			if fieldVal.IsNil() {
-				ptr := reflect.New(fieldVal.Type().Elem())
-				if err := parseValue(ptr.Elem(), rawVal, config, htmlxTags); err != nil {
-					return err
-				}
+		if fieldVal.OverflowFloat(floatVal.(float64)) {
+			return fmt.Errorf("%v overflows %s", floatVal, fieldVal.Type().String())
+		}
 
-				fieldVal.Set(ptr)
-				return nil
-			} else {
-				return parseValue(fieldVal.Elem(), rawVal, config, htmlxTags)
+		fieldVal.SetFloat(floatVal.(float64))
+	case reflect.Ptr:
+		if fieldVal.IsNil() {
+			ptr := reflect.New(fieldVal.Type().Elem())
+			if _, err := parseValue(ptr.Elem(), rawVal, config, htmlxTags); err != nil {
+				return err
 			}
+
+			fieldVal.Set(ptr)
+			return nil
 		}
 
+		_, err := parseValue(fieldVal.Elem(), rawVal, config, htmlxTags)
+		return err
+	case reflect.Slice, reflect.Map, reflect.Struct:
+		if htmlxTags.jsonPath == nil {
+			return fmt.Errorf("Value of type %s is not supported", fieldVal.Type().String())
+		}
+
+		return setJSONValue(fieldVal, rawVal)
+	default:
 		return fmt.Errorf("Value of type %s is not supported", fieldVal.Type().String())
 	}
 
 	return nil
 }
 
-func parseValue(fieldVal reflect.Value, rawVal string, config *Config, htmlxTags HtmlxTags) error {
+// Parse the raw value into the field, the returned reason is non empty if the field was left untouched
+func parseValue(fieldVal reflect.Value, rawVal string, config *Config, htmlxTags HtmlxTags) (string, error) {
 	if !fieldVal.IsValid() {
-		return fmt.Errorf("Field doesn't represent a value")
+		return "", fmt.Errorf("Field doesn't represent a value")
 	}
 
 	if !fieldVal.CanSet() {
-		return fmt.Errorf("Field can't be set")
+		return "", fmt.Errorf("Field can't be set")
 	}
 
 	if htmlxTags.parser != "" {
@@ -397,8 +758,8 @@ func parseValue(fieldVal reflect.Value, rawVal string, config *Config, htmlxTags
 
 	if strings.TrimSpace(rawVal) == "" {
 		// Skip the  field if the raw value is empty
-		return nil
+		return "empty raw value", nil
 	}
 
-	return parseSupportedValues(fieldVal, rawVal, config, htmlxTags)
+	return "", parseSupportedValues(fieldVal, rawVal, config, htmlxTags)
 }
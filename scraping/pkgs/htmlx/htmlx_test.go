@@ -1,6 +1,8 @@
 package htmlx
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -143,3 +145,226 @@ func TestHtmlxHighLevelTypes(t *testing.T) {
 	fmt.Println(resultPageInfo.TopDate.Format("Mon, January 2, 2006"))
 	fmt.Println(resultPageInfo.SecondDate.Format("Mon, January 2, 2006"))
 }
+
+const contextTestContent = `
+<div class="header">
+	<span class="name">Paper Rex</span>
+	<span class="score">3</span>
+	<div class="note">PRX ban Haven; FNC ban Split; Lotus remains</div>
+</div>`
+
+type ContextTestNote struct {
+	Value string `selector:"div.note"`
+}
+
+type ContextTestInfo struct {
+	Name  string `selector:"span.name"  parser:"nameParser"`
+	Score int    `selector:"span.score"`
+	Note  ContextTestNote
+}
+
+func TestHtmlxContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	info := ContextTestInfo{}
+
+	err := ParseFromStringWithContext(ctx, &info, contextTestContent)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Error should wrap context.Canceled, get %v", err)
+	}
+
+	if info.Name != "" {
+		t.Errorf("Name should not be parsed after cancellation, get '%s'", info.Name)
+	}
+}
+
+func TestHtmlxContextCancelledBetweenFields(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(contextTestContent))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	parsers := map[string]Parser{
+		"nameParser": func(rawVal string) (any, error) {
+			cancel()
+			return strings.TrimSpace(rawVal), nil
+		},
+	}
+
+	info := ContextTestInfo{}
+
+	err = ParseFromDocumentWithContext(ctx, &info, doc, SetParsers(parsers))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Error should wrap context.Canceled, get %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "'Score'") {
+		t.Errorf("Error should contain the field path 'Score', get '%s'", err.Error())
+	}
+
+	if info.Name != "Paper Rex" {
+		t.Errorf("Wrong name, want 'Paper Rex', get '%s'", info.Name)
+	}
+
+	if info.Score != 0 {
+		t.Errorf("Score should not be parsed after cancellation, get %d", info.Score)
+	}
+}
+
+func TestHtmlxContextDeadlineNestedStruct(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(contextTestContent))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	note := struct {
+		Score int `selector:"span.score" parser:"slowParser"`
+		Note  ContextTestNote
+	}{}
+
+	parsers := map[string]Parser{
+		"slowParser": func(rawVal string) (any, error) {
+			<-ctx.Done()
+			return IntParser(rawVal)
+		},
+	}
+
+	err = ParseFromSelectionWithContext(ctx, &note, doc.Selection, SetParsers(parsers))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Error should wrap context.DeadlineExceeded, get %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "'Note'") {
+		t.Errorf("Error should contain the field path 'Note', get '%s'", err.Error())
+	}
+}
+
+func TestHtmlxContextBackground(t *testing.T) {
+	info := ContextTestInfo{}
+
+	parsers := map[string]Parser{"nameParser": StringParserClean}
+
+	if err := ParseFromStringWithContext(context.Background(), &info, contextTestContent, SetParsers(parsers)); err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Name != "Paper Rex" || info.Score != 3 || info.Note.Value != "PRX ban Haven; FNC ban Split; Lotus remains" {
+		t.Errorf("Wrong parsed value: %+v", info)
+	}
+}
+
+const sourceTestContent = `
+<div class="team-header">
+	<div class="team-header-country"><i class="flag mod-vn"></i> Vietnam</div>
+	<div class="team-header-rating"><span>Rating</span> [1532] <span>#4</span> last updated</div>
+	<a class="team-link" href="/team/624/paper-rex"><b>Paper</b> Rex</a>
+</div>`
+
+type SourceTestInfo struct {
+	Location   string `selector:"div.team-header-country"`
+	Rating     int    `selector:"div.team-header-rating" source:"text"      regex:"\\[([0-9]+)\\]"`
+	Rank       string `selector:"div.team-header-rating" source:"text"      regex:"#[0-9]+"`
+	Updated    string `selector:"div.team-header-rating" source:"text=1"`
+	LinkText   string `selector:"a.team-link"            source:"text"`
+	LinkHtml   string `selector:"a.team-link"            source:"html"`
+	LinkOuter  string `selector:"a.team-link"            source:"outerHtml"`
+	TeamId     int    `selector:"a.team-link"            source:"attr=href" regex:"/team/([0-9]+)/"`
+	MissingTxt string `selector:"a.team-link"            source:"text=5"`
+}
+
+func TestHtmlxSources(t *testing.T) {
+	info := SourceTestInfo{}
+
+	if err := ParseFromString(&info, sourceTestContent); err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Location != "Vietnam" {
+		t.Errorf("Wrong location, want 'Vietnam', get '%s'", info.Location)
+	}
+
+	if info.Rating != 1532 {
+		t.Errorf("Wrong rating, want 1532, get %d", info.Rating)
+	}
+
+	if info.Rank != "#4" {
+		t.Errorf("Wrong rank, want '#4', get '%s'", info.Rank)
+	}
+
+	if info.Updated != "last updated" {
+		t.Errorf("Wrong updated text, want 'last updated', get '%s'", info.Updated)
+	}
+
+	if info.LinkText != "Paper Rex" {
+		t.Errorf("Wrong link text, want 'Paper Rex', get '%s'", info.LinkText)
+	}
+
+	if info.LinkHtml != "<b>Paper</b> Rex" {
+		t.Errorf("Wrong link html, want '<b>Paper</b> Rex', get '%s'", info.LinkHtml)
+	}
+
+	if info.LinkOuter != `<a class="team-link" href="/team/624/paper-rex"><b>Paper</b> Rex</a>` {
+		t.Errorf("Wrong link outer html, get '%s'", info.LinkOuter)
+	}
+
+	if info.TeamId != 624 {
+		t.Errorf("Wrong team id, want 624, get %d", info.TeamId)
+	}
+
+	if info.MissingTxt != "" {
+		t.Errorf("Missing text node should be empty, get '%s'", info.MissingTxt)
+	}
+}
+
+func TestHtmlxInvalidRegex(t *testing.T) {
+	info := struct {
+		Value string `selector:"a" regex:"(["`
+	}{}
+
+	if err := ParseFromString(&info, sourceTestContent); err == nil {
+		t.Errorf("Invalid regex should return error")
+	}
+
+	if err := ParseFromString(&info, sourceTestContent, SetParseAllFields(true)); err == nil {
+		t.Errorf("Invalid regex should return error when parsing all fields")
+	}
+
+	if _, err := Marshal(&info); err == nil {
+		t.Errorf("Invalid regex should return error when marshaling")
+	}
+}
+
+func TestHtmlxMalformedTags(t *testing.T) {
+	required := struct {
+		Value string `selector:"a" required:"ture"`
+	}{}
+
+	if err := ParseFromString(&required, sourceTestContent); err == nil {
+		t.Errorf("Invalid required tag should return error without parsing all fields")
+	}
+
+	matrix := struct {
+		Value string `matrix:"rows"`
+	}{}
+
+	if err := ParseFromString(&matrix, sourceTestContent); err == nil {
+		t.Errorf("Invalid matrix tag should return error without parsing all fields")
+	}
+
+	// A field without selector is still skipped
+	missing := struct {
+		Value string
+		Link  string `selector:"a"`
+	}{}
+
+	if err := ParseFromString(&missing, sourceTestContent); err != nil {
+		t.Errorf("Field without selector should be skipped, get %s", err.Error())
+	}
+}
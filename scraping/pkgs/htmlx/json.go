@@ -0,0 +1,196 @@
+package htmlx
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// A step of a JSON path, either an object key, an array index or a wildcard over the array elements
+type jsonPathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// Parse a JSONPath-like expression, e.g `$.game.maps[0].name`, `teams[*].id` or `["data-id"]`.
+// The leading "$" is optional and an empty path or "$" select the whole value
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	steps := []jsonPathStep{}
+
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, "$")
+
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+			fallthrough
+		default:
+			end := i
+			for end < len(path) && path[end] != '.' && path[end] != '[' {
+				end++
+			}
+
+			if end == i {
+				return nil, fmt.Errorf("empty key at position %d of json path '%s'", i, path)
+			}
+
+			steps = append(steps, jsonPathStep{key: path[i:end]})
+			i = end
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed bracket in json path '%s'", path)
+			}
+
+			inner := strings.TrimSpace(path[i+1 : i+end])
+			i += end + 1
+
+			switch {
+			case inner == "*":
+				steps = append(steps, jsonPathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, jsonPathStep{key: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid index '%s' in json path '%s'", inner, path)
+				}
+
+				steps = append(steps, jsonPathStep{index: index, isIndex: true})
+			}
+		}
+	}
+
+	return steps, nil
+}
+
+// Return the value at the path, a wildcard step collect the values of every array element having the rest of the path
+func lookupJSONPath(val any, steps []jsonPathStep) (any, bool) {
+	for i, step := range steps {
+		switch {
+		case step.wildcard:
+			arr, ok := val.([]any)
+			if !ok {
+				return nil, false
+			}
+
+			values := []any{}
+			for _, elem := range arr {
+				if elemVal, found := lookupJSONPath(elem, steps[i+1:]); found {
+					values = append(values, elemVal)
+				}
+			}
+
+			return values, true
+		case step.isIndex:
+			arr, ok := val.([]any)
+			if !ok || step.index >= len(arr) {
+				return nil, false
+			}
+
+			val = arr[step.index]
+		default:
+			obj, ok := val.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+
+			if val, ok = obj[step.key]; !ok {
+				return nil, false
+			}
+		}
+	}
+
+	return val, true
+}
+
+// Convert the JSON value to the raw string passed to the parsers, objects and arrays are kept as compact JSON
+func jsonRawValue(val any) (string, error) {
+	switch v := val.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+
+		return string(b), nil
+	}
+}
+
+// Decode the JSON held by every matched element, a script content or an attribute depending on the source,
+// and return the raw string of the value at the path of the first element having it.
+// Elements which don't hold JSON are ignored as long as one of them does
+func extractJSONValue(
+	fieldType reflect.StructField,
+	htmlElement *goquery.Selection,
+	htmlxTags HtmlxTags,
+	config *Config,
+) (string, bool, error) {
+	var decodeErr error
+	decoded := false
+
+	for i := range htmlElement.Length() {
+		rawVal, err := getRawValue(fieldType, htmlElement.Eq(i), htmlxTags.source, config)
+		if err != nil {
+			return "", false, err
+		}
+
+		if htmlxTags.regex != nil {
+			rawVal = extractRegexValue(htmlxTags.regex, rawVal)
+		}
+
+		if strings.TrimSpace(rawVal) == "" {
+			continue
+		}
+
+		decoder := json.NewDecoder(strings.NewReader(rawVal))
+		decoder.UseNumber()
+
+		var val any
+		if err = decoder.Decode(&val); err != nil {
+			decodeErr = err
+			continue
+		}
+
+		decoded = true
+
+		if val, found := lookupJSONPath(val, htmlxTags.jsonPath); found {
+			rawVal, err = jsonRawValue(val)
+			return rawVal, true, err
+		}
+	}
+
+	if !decoded && decodeErr != nil {
+		return "", false, fmt.Errorf("invalid JSON for field '%s': %s", fieldType.Name, decodeErr.Error())
+	}
+
+	return "", false, nil
+}
+
+// Decode a JSON object or array into a slice, map or struct field
+func setJSONValue(fieldVal reflect.Value, rawVal string) error {
+	if !fieldVal.CanAddr() {
+		return fmt.Errorf("Value of type %s is not supported", fieldVal.Type().String())
+	}
+
+	if err := json.Unmarshal([]byte(rawVal), fieldVal.Addr().Interface()); err != nil {
+		return fmt.Errorf("JSON decode error: %s", err.Error())
+	}
+
+	return nil
+}
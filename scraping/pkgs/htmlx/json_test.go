@@ -0,0 +1,149 @@
+package htmlx
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const jsonTestContent = `
+<html>
+<head>
+	<script>var ga = function() {};</script>
+	<script type="application/ld+json">
+		{
+			"@type": "SportsEvent",
+			"name": "Paper Rex vs. Sentinels",
+			"startDate": "2025-04-12T16:30:00+00:00",
+			"competitor": [
+				{"name": "Paper Rex", "url": "/team/624/paper-rex"},
+				{"name": "Sentinels", "url": "/team/2/sentinels"}
+			],
+			"location": {"name": "Riot Games Arena", "address.city": "Shanghai"}
+		}
+	</script>
+	<script>
+		window.__MATCH__ = {"id": 449010, "maps": [{"id": 225042, "name": "Ascent", "rounds": 24}], "vod": null, "bo3": true};
+	</script>
+</head>
+<body>
+	<div class="vm-stats-game" data-game-id="225042" data-picked='{"team": 1, "note": "pick"}'></div>
+	<div class="vm-stats-game" data-game-id="all"></div>
+</body>
+</html>`
+
+type JSONTestLocation struct {
+	Name string `json:"name"`
+}
+
+type JSONTestMatch struct {
+	Name        string           `selector:"script[type='application/ld+json']" jsonpath:"name"`
+	StartDate   time.Time        `selector:"script[type='application/ld+json']" jsonpath:"$.startDate"`
+	Team2Name   string           `selector:"script[type='application/ld+json']" jsonpath:"competitor[1].name"`
+	Teams       []string         `selector:"script[type='application/ld+json']" jsonpath:"competitor[*].name"`
+	Location    JSONTestLocation `selector:"script[type='application/ld+json']" jsonpath:"location"`
+	City        string           `selector:"script[type='application/ld+json']" jsonpath:"location['address.city']"`
+	MatchId     int              `selector:"script" jsonpath:"id"       regex:"(?s)window.__MATCH__ = (\\{.*\\});"`
+	MapName     string           `selector:"script" jsonpath:"maps[0].name" regex:"(?s)window.__MATCH__ = (\\{.*\\});"`
+	Rounds      *int             `selector:"script" jsonpath:"maps[0].rounds" regex:"(?s)window.__MATCH__ = (\\{.*\\});"`
+	Vod         *string          `selector:"script" jsonpath:"vod"      regex:"(?s)window.__MATCH__ = (\\{.*\\});"`
+	Bo3         bool             `selector:"script" jsonpath:"bo3"      regex:"(?s)window.__MATCH__ = (\\{.*\\});"`
+	GameId      int              `selector:"div.vm-stats-game" source:"attr=data-game-id" jsonpath:"$"`
+	PickedTeam  int              `selector:"div.vm-stats-game" source:"attr=data-picked"  jsonpath:"team"`
+	PickedNote  string           `selector:"div.vm-stats-game" source:"attr=data-picked"  jsonpath:"note" parser:"upper"`
+	MissingPath *string          `selector:"script[type='application/ld+json']" jsonpath:"organizer.name"`
+}
+
+func TestParseJSON(t *testing.T) {
+	var match JSONTestMatch
+	if err := ParseFromString(&match, jsonTestContent); err != nil {
+		t.Fatal(err)
+	}
+
+	rounds := 24
+	want := JSONTestMatch{
+		Name:       "Paper Rex vs. Sentinels",
+		StartDate:  time.Date(2025, 4, 12, 16, 30, 0, 0, time.UTC),
+		Team2Name:  "Sentinels",
+		Teams:      []string{"Paper Rex", "Sentinels"},
+		Location:   JSONTestLocation{Name: "Riot Games Arena"},
+		City:       "Shanghai",
+		MatchId:    449010,
+		MapName:    "Ascent",
+		Rounds:     &rounds,
+		Bo3:        true,
+		GameId:     225042,
+		PickedTeam: 1,
+		PickedNote: "PICK",
+	}
+
+	if !match.StartDate.Equal(want.StartDate) {
+		t.Errorf("Wrong start date, want %v, get %v", want.StartDate, match.StartDate)
+	}
+	match.StartDate = want.StartDate
+
+	if !reflect.DeepEqual(match, want) {
+		t.Errorf("Wrong match\nwant %+v\nget  %+v", want, match)
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	var invalidJSON struct {
+		Id int `selector:"script:not([type])" jsonpath:"id"`
+	}
+
+	if err := ParseFromString(&invalidJSON, jsonTestContent); err == nil {
+		t.Errorf("Scripts without any JSON should return error")
+	}
+
+	var invalidPath struct {
+		Id int `selector:"script" jsonpath:"maps[x]"`
+	}
+
+	if err := ParseFromString(&invalidPath, jsonTestContent, SetParseAllFields(true)); err == nil {
+		t.Errorf("Invalid json path should return error")
+	}
+
+	trace := NewTrace()
+
+	var missing struct {
+		Organizer string `selector:"script[type='application/ld+json']" jsonpath:"organizer.name"`
+	}
+
+	if err := ParseFromString(&missing, jsonTestContent, SetTrace(trace)); err != nil {
+		t.Fatal(err)
+	}
+
+	if fields := trace.Fields(); len(fields) != 1 || fields[0].SkipReason != "json path organizer.name not found" {
+		t.Errorf("Missing json path should be traced, get %+v", fields)
+	}
+}
+
+func TestParseJSONPath(t *testing.T) {
+	tests := map[string][]jsonPathStep{
+		"":                    {},
+		"$":                   {},
+		"$.a.b":               {{key: "a"}, {key: "b"}},
+		"a[2].b":              {{key: "a"}, {index: 2, isIndex: true}, {key: "b"}},
+		`teams[*]["data-id"]`: {{key: "teams"}, {wildcard: true}, {key: "data-id"}},
+		"['a.b']":             {{key: "a.b"}},
+	}
+
+	for path, want := range tests {
+		steps, err := parseJSONPath(path)
+		if err != nil {
+			t.Errorf("'%s': %s", path, err.Error())
+			continue
+		}
+
+		if !reflect.DeepEqual(steps, want) {
+			t.Errorf("Wrong steps for '%s', want %v, get %v", path, want, steps)
+		}
+	}
+
+	for _, path := range []string{"a..b", "a[", "a[-1]", "a[b]"} {
+		if _, err := parseJSONPath(path); err == nil {
+			t.Errorf("'%s' should return error", path)
+		}
+	}
+}
@@ -0,0 +1,827 @@
+package htmlx
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// LintField is a htmlx tagged field found in the source code
+type LintField struct {
+	// Dot separated path of the field inside its type, e.g "DuelKills.Team1PlayerKillsVsTeam2Player"
+	Name string
+	// Type of the field as written in the source code, e.g "*float64"
+	Type string
+	Tag  reflect.StructTag
+	// Position of the field in the source code, as file:line
+	Pos string
+
+	// Resolved type of the field, nil if it can't be resolved statically
+	resolved reflect.Type
+}
+
+// LintType is a struct type having htmlx tagged fields, nested structs are flattened into it
+type LintType struct {
+	Package string
+	Name    string
+	Fields  []LintField
+}
+
+// Return the name of the type qualified by its package, e.g "models.MatchSchema"
+func (t LintType) QualifiedName() string {
+	return t.Package + "." + t.Name
+}
+
+func isHtmlxTag(tag reflect.StructTag) bool {
+	for _, key := range []string{"selector", "column", "matrix", "jsonpath"} {
+		if _, ok := tag.Lookup(key); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+var lintBasicTypes = map[string]reflect.Type{
+	"string":  reflect.TypeOf(""),
+	"bool":    reflect.TypeOf(false),
+	"int":     reflect.TypeOf(int(0)),
+	"int8":    reflect.TypeOf(int8(0)),
+	"int16":   reflect.TypeOf(int16(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint8":   reflect.TypeOf(uint8(0)),
+	"uint16":  reflect.TypeOf(uint16(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+}
+
+// The type declarations of a single package directory
+type lintPackage struct {
+	name  string
+	fset  *token.FileSet
+	types map[string]ast.Expr
+	order []string
+}
+
+// Resolve the type expression to a reflect type, named types of the package are resolved to their underlying type
+func (p *lintPackage) resolve(expr ast.Expr, depth int) reflect.Type {
+	if depth > 10 {
+		return nil
+	}
+
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		if elem := p.resolve(e.X, depth+1); elem != nil {
+			return reflect.PointerTo(elem)
+		}
+	case *ast.ArrayType:
+		if elem := p.resolve(e.Elt, depth+1); elem != nil && e.Len == nil {
+			return reflect.SliceOf(elem)
+		}
+	case *ast.Ident:
+		if t, ok := lintBasicTypes[e.Name]; ok {
+			return t
+		}
+
+		if underlying, ok := p.types[e.Name]; ok {
+			return p.resolve(underlying, depth+1)
+		}
+	case *ast.SelectorExpr:
+		switch types.ExprString(e) {
+		case "time.Time":
+			return reflect.TypeOf(time.Time{})
+		case "time.Duration":
+			return reflect.TypeOf(time.Duration(0))
+		case "goquery.Selection":
+			return selectionType.Elem()
+		}
+	}
+
+	return nil
+}
+
+// Flatten the tagged fields of the struct, nested structs of the same package are walked through
+func (p *lintPackage) fields(structType *ast.StructType, prefix string, depth int) []LintField {
+	var fields []LintField
+
+	if depth > 10 {
+		return nil
+	}
+
+	for _, field := range structType.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			tagStr, _ := strconv.Unquote(field.Tag.Value)
+			tag = reflect.StructTag(tagStr)
+		}
+
+		names := []string{}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+
+		// Embedded fields are named after their type
+		if len(names) == 0 {
+			names = append(names, strings.TrimPrefix(types.ExprString(field.Type), "*"))
+		}
+
+		for _, name := range names {
+			if !ast.IsExported(name) {
+				continue
+			}
+
+			path := fieldPath(prefix, name)
+
+			if isHtmlxTag(tag) {
+				fields = append(fields, LintField{
+					Name:     path,
+					Type:     types.ExprString(field.Type),
+					Tag:      tag,
+					Pos:      p.fset.Position(field.Pos()).String(),
+					resolved: p.resolve(field.Type, 0),
+				})
+
+				continue
+			}
+
+			if ident, ok := field.Type.(*ast.Ident); ok {
+				if nested, ok := p.types[ident.Name].(*ast.StructType); ok {
+					fields = append(fields, p.fields(nested, path, depth+1)...)
+				}
+			}
+		}
+	}
+
+	return fields
+}
+
+func walkGoFiles(dir string, walk func(path string, file *ast.File, fset *token.FileSet) error) error {
+	fset := token.NewFileSet()
+
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		return walk(path, file, fset)
+	})
+}
+
+// Load the htmlx tagged struct types declared in the directories and their sub directories
+func LoadLintTypes(dirs ...string) ([]LintType, error) {
+	packages := map[string]*lintPackage{}
+	var packageDirs []string
+
+	for _, dir := range dirs {
+		if err := walkGoFiles(dir, func(path string, file *ast.File, fset *token.FileSet) error {
+			pkgDir := filepath.Dir(path)
+
+			pkg, ok := packages[pkgDir]
+			if !ok {
+				pkg = &lintPackage{name: file.Name.Name, fset: fset, types: map[string]ast.Expr{}}
+				packages[pkgDir] = pkg
+				packageDirs = append(packageDirs, pkgDir)
+			}
+
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range genDecl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					pkg.types[typeSpec.Name.Name] = typeSpec.Type
+					pkg.order = append(pkg.order, typeSpec.Name.Name)
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("Error loading types from %s: %s", dir, err.Error())
+		}
+	}
+
+	var lintTypes []LintType
+
+	for _, pkgDir := range packageDirs {
+		pkg := packages[pkgDir]
+
+		for _, name := range pkg.order {
+			structType, ok := pkg.types[name].(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			if fields := pkg.fields(structType, "", 0); len(fields) > 0 {
+				lintTypes = append(lintTypes, LintType{Package: pkg.name, Name: name, Fields: fields})
+			}
+		}
+	}
+
+	return lintTypes, nil
+}
+
+// Load the names of the parsers defined in the directories and their sub directories, with their position.
+// A parser is defined by a call to RegisterParser or RegisterParserFactory, or as a key of a map of Parser
+func LoadDefinedParsers(dirs ...string) (map[string]string, error) {
+	parsers := map[string]string{}
+
+	addName := func(expr ast.Expr, fset *token.FileSet) {
+		lit, ok := expr.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return
+		}
+
+		if name, err := strconv.Unquote(lit.Value); err == nil {
+			if _, exists := parsers[name]; !exists {
+				parsers[name] = fset.Position(lit.Pos()).String()
+			}
+		}
+	}
+
+	for _, dir := range dirs {
+		if err := walkGoFiles(dir, func(path string, file *ast.File, fset *token.FileSet) error {
+			ast.Inspect(file, func(n ast.Node) bool {
+				switch node := n.(type) {
+				case *ast.CallExpr:
+					var funcName string
+
+					switch fun := node.Fun.(type) {
+					case *ast.SelectorExpr:
+						funcName = fun.Sel.Name
+					case *ast.Ident:
+						funcName = fun.Name
+					}
+
+					if (funcName == "RegisterParser" || funcName == "RegisterParserFactory") && len(node.Args) > 0 {
+						addName(node.Args[0], fset)
+					}
+				case *ast.CompositeLit:
+					mapType, ok := node.Type.(*ast.MapType)
+					if !ok || !strings.HasSuffix(types.ExprString(mapType.Value), "Parser") {
+						return true
+					}
+
+					for _, elt := range node.Elts {
+						if kv, ok := elt.(*ast.KeyValueExpr); ok {
+							addName(kv.Key, fset)
+						}
+					}
+				}
+
+				return true
+			})
+
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("Error loading parsers from %s: %s", dir, err.Error())
+		}
+	}
+
+	return parsers, nil
+}
+
+// LintPage is a saved page the selectors are run against
+type LintPage struct {
+	Name string
+	Doc  *goquery.Document
+}
+
+// Load the html files of the directory as pages, named after their file name
+func LoadLintPages(dir string) ([]LintPage, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []LintPage
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err := goquery.NewDocumentFromReader(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing page %s: %s", path, err.Error())
+		}
+
+		pages = append(pages, LintPage{Name: filepath.Base(path), Doc: doc})
+	}
+
+	return pages, nil
+}
+
+// LintTarget tell which pages a type is linted against and which elements of the page it is parsed from
+type LintTarget struct {
+	// Name of the type, qualified with the package or not, e.g "MatchSchema" or "models.MatchSchema"
+	Type string `json:"type"`
+	// Glob of the page names, all pages if empty
+	Pages string `json:"pages,omitempty"`
+	// Selector of the elements the type is parsed from, the whole page if empty
+	Scope string `json:"scope,omitempty"`
+	// The scope elements are tables parsed with ParseMatrix
+	Matrix bool `json:"matrix,omitempty"`
+}
+
+// LintConfig list the types to lint, every loaded type is linted against every page if there is no target
+type LintConfig struct {
+	Targets []LintTarget `json:"targets"`
+}
+
+// Load the lint config from a JSON file
+func LoadLintConfig(path string) (LintConfig, error) {
+	var config LintConfig
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+
+	if err = json.Unmarshal(content, &config); err != nil {
+		return config, fmt.Errorf("Error parsing lint config %s: %s", path, err.Error())
+	}
+
+	return config, nil
+}
+
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// LintIssue is a problem found by [Lint], identical issues found in many scopes of a page are counted once
+type LintIssue struct {
+	Severity string `json:"severity"`
+	// One of: invalid_tag, scope_not_found, zero_matches, multiple_matches, fallback_selector,
+	// unparseable_value, unknown_parser, unused_parser
+	Kind     string `json:"kind"`
+	Page     string `json:"page,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Selector string `json:"selector,omitempty"`
+	Message  string `json:"message"`
+	Count    int    `json:"count"`
+	Pos      string `json:"pos,omitempty"`
+}
+
+// LintReport hold the issues found by [Lint]
+type LintReport struct {
+	Issues []LintIssue `json:"issues"`
+	// Number of field checks run
+	Checked int `json:"checked"`
+
+	index map[string]int
+}
+
+func (r *LintReport) add(issue LintIssue) {
+	if r.index == nil {
+		r.index = map[string]int{}
+	}
+
+	key := strings.Join([]string{issue.Kind, issue.Page, issue.Type, issue.Field, issue.Selector}, "\x00")
+	if i, ok := r.index[key]; ok {
+		r.Issues[i].Count++
+		return
+	}
+
+	issue.Count = 1
+	r.index[key] = len(r.Issues)
+	r.Issues = append(r.Issues, issue)
+}
+
+func (r *LintReport) count(severity string) int {
+	var n int
+
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Return the number of errors
+func (r *LintReport) Errors() int {
+	return r.count(LintError)
+}
+
+// Return the number of warnings
+func (r *LintReport) Warnings() int {
+	return r.count(LintWarning)
+}
+
+// Write the report as one line per issue followed by a summary, in the form
+// "error match.html: models.MatchSchema.Team1Score: selector '...' matched 0 elements (x3)"
+func (r *LintReport) WriteText(w io.Writer) error {
+	for _, issue := range r.Issues {
+		var location []string
+		if issue.Page != "" {
+			location = append(location, issue.Page)
+		}
+
+		if issue.Type != "" {
+			location = append(location, fieldPath(issue.Type, issue.Field))
+		} else if issue.Pos != "" {
+			location = append(location, issue.Pos)
+		}
+
+		line := fmt.Sprintf("%s %s: %s", issue.Severity, strings.Join(location, ": "), issue.Message)
+		if issue.Count > 1 {
+			line += fmt.Sprintf(" (x%d)", issue.Count)
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d checks, %d errors, %d warnings\n", r.Checked, r.Errors(), r.Warnings())
+	return err
+}
+
+// Write the report as an indented JSON document
+func (r *LintReport) WriteJSON(w io.Writer) error {
+	report := *r
+	if report.Issues == nil {
+		report.Issues = []LintIssue{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(report)
+}
+
+// Check if the field can be missing from the page without failing the parse
+func isOptionalField(field LintField, htmlxTags HtmlxTags) bool {
+	if htmlxTags.required != nil {
+		return !*htmlxTags.required
+	}
+
+	return htmlxTags.defaultVal != nil || strings.HasPrefix(field.Type, "*")
+}
+
+// Return the names of the parsers used by the parser tag, without the arguments of parameterized parsers
+func parserNames(parserTag string) []string {
+	var names []string
+
+	if parserTag == "" {
+		return nil
+	}
+
+	for _, stage := range splitPipeline(parserTag) {
+		if matches := regexp.MustCompile(`^([a-zA-Z0-9_]+)\(`).FindStringSubmatch(stage); matches != nil {
+			stage = matches[1]
+		}
+
+		names = append(names, stage)
+	}
+
+	return names
+}
+
+func isKnownParser(name string) bool {
+	if _, ok := builtinParsers[name]; ok {
+		return true
+	}
+
+	if _, ok := builtinParserFactories[name]; ok || name == "date" {
+		return true
+	}
+
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	if _, ok := registry.parsers[name]; ok {
+		return true
+	}
+
+	_, ok := registry.factories[name]
+	return ok
+}
+
+type lintScope struct {
+	sel    *goquery.Selection
+	matrix *matrixCell
+}
+
+// Return the elements the type is parsed from on the page, every cell of the tables in matrix mode
+func lintScopes(page LintPage, target LintTarget) []lintScope {
+	var scopes []lintScope
+
+	scopeSel := page.Doc.Selection
+	if target.Scope != "" {
+		scopeSel = page.Doc.Find(target.Scope)
+	}
+
+	scopeSel.Each(func(_ int, sel *goquery.Selection) {
+		if !target.Matrix {
+			scopes = append(scopes, lintScope{sel: sel})
+			return
+		}
+
+		table := tableOf(sel)
+		columnHeaders := tableHeaderRow(table).ChildrenFiltered("th, td")
+
+		TableBodyRows(table).Each(func(_ int, row *goquery.Selection) {
+			rowCells := row.ChildrenFiltered("th, td")
+
+			for j := 1; j < rowCells.Length(); j++ {
+				scopes = append(scopes, lintScope{
+					sel: rowCells.Eq(j),
+					matrix: &matrixCell{
+						rowHeader:    rowCells.First(),
+						columnHeader: columnHeaders.Eq(j),
+						cell:         rowCells.Eq(j),
+					},
+				})
+			}
+		})
+	})
+
+	return scopes
+}
+
+// Run the selectors of the field in the scope and report the problems found
+func lintField(
+	report *LintReport,
+	page LintPage,
+	lintType LintType,
+	field LintField,
+	scope lintScope,
+	config *Config,
+) {
+	report.Checked++
+
+	issue := func(severity, kind, selector, message string) {
+		report.add(LintIssue{
+			Severity: severity,
+			Kind:     kind,
+			Page:     page.Name,
+			Type:     lintType.QualifiedName(),
+			Field:    field.Name,
+			Selector: selector,
+			Message:  message,
+			Pos:      field.Pos,
+		})
+	}
+
+	htmlxTags, err := initializeHtmlxTags(reflect.StructField{Name: field.Name, Tag: field.Tag})
+	if err != nil {
+		issue(LintError, "invalid_tag", "", err.Error())
+		return
+	}
+
+	fieldConfig := *config
+	fieldConfig.matrixCell = scope.matrix
+
+	root, err := fieldRoot(scope.sel, htmlxTags, &fieldConfig)
+	if err != nil {
+		issue(LintError, "invalid_tag", "", err.Error())
+		return
+	}
+
+	htmlElement, selectorIdx := findHtmlElement(root, htmlxTags.selectors)
+	selector := strings.Join(htmlxTags.selectors, " || ")
+	if len(htmlxTags.columns) > 0 {
+		selector = fmt.Sprintf("column %s > %s", strings.Join(htmlxTags.columns, "||"), selector)
+	}
+
+	switch {
+	case htmlElement.Length() == 0:
+		severity := LintError
+		if isOptionalField(field, htmlxTags) {
+			severity = LintWarning
+		}
+
+		issue(severity, "zero_matches", selector, fmt.Sprintf("selector '%s' matched 0 elements", selector))
+		return
+	// Json fields are looked up in every matched element, e.g all the scripts of the page
+	case htmlElement.Length() > 1 && field.resolved != selectionType && htmlxTags.jsonPath == nil:
+		issue(
+			LintError,
+			"multiple_matches",
+			selector,
+			fmt.Sprintf("selector '%s' matched %d elements", selector, htmlElement.Length()),
+		)
+	}
+
+	if selectorIdx > 0 {
+		issue(
+			LintWarning,
+			"fallback_selector",
+			htmlxTags.selectors[0],
+			fmt.Sprintf("primary selector '%s' is stale, fallback '%s' matched", htmlxTags.selectors[0], htmlxTags.selectors[selectorIdx]),
+		)
+	}
+
+	if field.resolved == nil || field.resolved == selectionType {
+		return
+	}
+
+	// Values of fields using parsers unknown to htmlx, such as parsers querying the database, can't be checked
+	if htmlxTags.parser != "" {
+		if _, err := buildPipeline(htmlxTags.parser, &fieldConfig); err != nil {
+			return
+		}
+	}
+
+	rawVal, _, err := fieldRawValue(reflect.StructField{Name: field.Name}, htmlElement, htmlxTags, &fieldConfig)
+	if err != nil {
+		issue(LintError, "unparseable_value", selector, err.Error())
+		return
+	}
+
+	if htmlxTags.defaultVal != nil && strings.TrimSpace(rawVal) == "" {
+		rawVal = *htmlxTags.defaultVal
+	}
+
+	if _, err = parseValue(reflect.New(field.resolved).Elem(), rawVal, &fieldConfig, htmlxTags); err != nil {
+		issue(
+			LintError,
+			"unparseable_value",
+			selector,
+			fmt.Sprintf("value '%s' can't be parsed to %s: %s", strings.TrimSpace(rawVal), field.Type, err.Error()),
+		)
+	}
+}
+
+// Return the types to lint with their targets, every type is linted against every page if the config has no target
+func lintTargets(lintTypes []LintType, lintConfig LintConfig) ([]LintType, []LintTarget, []string) {
+	var targetTypes []LintType
+	var targets []LintTarget
+	var unmatched []string
+
+	if len(lintConfig.Targets) == 0 {
+		for _, lintType := range lintTypes {
+			targetTypes = append(targetTypes, lintType)
+			targets = append(targets, LintTarget{Type: lintType.QualifiedName()})
+		}
+
+		return targetTypes, targets, nil
+	}
+
+	for _, target := range lintConfig.Targets {
+		found := false
+
+		for _, lintType := range lintTypes {
+			if target.Type == lintType.Name || target.Type == lintType.QualifiedName() {
+				targetTypes = append(targetTypes, lintType)
+				targets = append(targets, target)
+				found = true
+			}
+		}
+
+		if !found {
+			unmatched = append(unmatched, target.Type)
+		}
+	}
+
+	return targetTypes, targets, unmatched
+}
+
+// Lint the selectors of the types against the pages. It report selectors matching zero or multiple elements,
+// stale primary selectors, values which can't be parsed by htmlx, parser names used in tags but never defined
+// and parsers defined but never used. The options are the ones the types are parsed with, e.g [SetDateFormat]
+func Lint(lintTypes []LintType, pages []LintPage, lintConfig LintConfig, definedParsers map[string]string, opts ...Option) *LintReport {
+	report := &LintReport{}
+
+	config := NewDefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	targetTypes, targets, unmatched := lintTargets(lintTypes, lintConfig)
+	for _, name := range unmatched {
+		report.add(LintIssue{
+			Severity: LintError,
+			Kind:     "invalid_tag",
+			Type:     name,
+			Message:  fmt.Sprintf("type %s of the config is not found", name),
+		})
+	}
+
+	for i, lintType := range targetTypes {
+		target := targets[i]
+
+		for _, page := range pages {
+			if target.Pages != "" {
+				if ok, _ := filepath.Match(target.Pages, page.Name); !ok {
+					continue
+				}
+			}
+
+			scopes := lintScopes(page, target)
+			if len(scopes) == 0 {
+				report.add(LintIssue{
+					Severity: LintWarning,
+					Kind:     "scope_not_found",
+					Page:     page.Name,
+					Type:     lintType.QualifiedName(),
+					Selector: target.Scope,
+					Message:  fmt.Sprintf("scope '%s' matched 0 elements", target.Scope),
+				})
+
+				continue
+			}
+
+			for _, scope := range scopes {
+				for _, field := range lintType.Fields {
+					lintField(report, page, lintType, field, scope, config)
+				}
+			}
+		}
+	}
+
+	used := map[string]bool{}
+	for _, lintType := range lintTypes {
+		for _, field := range lintType.Fields {
+			for _, name := range parserNames(field.Tag.Get("parser")) {
+				if !used[name] && definedParsers[name] == "" && config.parsers[name] == nil && !isKnownParser(name) {
+					report.add(LintIssue{
+						Severity: LintError,
+						Kind:     "unknown_parser",
+						Type:     lintType.QualifiedName(),
+						Field:    field.Name,
+						Message:  fmt.Sprintf("parser %s is never defined", name),
+						Pos:      field.Pos,
+					})
+				}
+
+				used[name] = true
+			}
+		}
+	}
+
+	var unused []string
+	for name := range definedParsers {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+
+	sort.Strings(unused)
+	for _, name := range unused {
+		report.add(LintIssue{
+			Severity: LintWarning,
+			Kind:     "unused_parser",
+			Message:  fmt.Sprintf("parser %s is defined but no tag uses it", name),
+			Pos:      definedParsers[name],
+		})
+	}
+
+	return report
+}
+
+// Check that the selectors of the types are sound against the pages, used from tests of the packages declaring them
+func LintDirs(typeDirs []string, pagesDir string, lintConfig LintConfig, opts ...Option) (*LintReport, error) {
+	lintTypes, err := LoadLintTypes(typeDirs...)
+	if err != nil {
+		return nil, err
+	}
+
+	definedParsers, err := LoadDefinedParsers(typeDirs...)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := LoadLintPages(pagesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return Lint(lintTypes, pages, lintConfig, definedParsers, opts...), nil
+}
@@ -0,0 +1,141 @@
+package htmlx
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func lintTestReport(t *testing.T) *LintReport {
+	report, err := LintDirs([]string{"testdata/lint/types"}, "testdata/lint/pages", LintConfig{
+		Targets: []LintTarget{
+			{Type: "Match", Pages: "match_*.html"},
+			{Type: "sample.PlayerRow", Scope: "table tr:has(td)"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return report
+}
+
+func findLintIssue(report *LintReport, kind, field string) *LintIssue {
+	for i, issue := range report.Issues {
+		if issue.Kind == kind && issue.Field == field {
+			return &report.Issues[i]
+		}
+	}
+
+	return nil
+}
+
+func TestLoadLintTypes(t *testing.T) {
+	lintTypes, err := LoadLintTypes("testdata/lint/types")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, lintType := range lintTypes {
+		names = append(names, lintType.QualifiedName())
+	}
+
+	if strings.Join(names, ",") != "sample.Score,sample.Match,sample.PlayerRow" {
+		t.Fatalf("Wrong types, get %v", names)
+	}
+
+	match := lintTypes[1]
+	if last := match.Fields[len(match.Fields)-1]; last.Name != "Score.Team2" || last.Type != "int" {
+		t.Errorf("Embedded struct fields should be flattened, get %+v", last)
+	}
+}
+
+func TestLint(t *testing.T) {
+	report := lintTestReport(t)
+
+	tests := []struct {
+		kind     string
+		field    string
+		severity string
+	}{
+		{"fallback_selector", "Event", LintWarning},
+		{"zero_matches", "Note", LintWarning},
+		{"zero_matches", "Vetoes", LintError},
+		{"unparseable_value", "Winner", LintError},
+		{"multiple_matches", "Players", LintError},
+		{"unknown_parser", "Vetoes", LintError},
+		{"unparseable_value", "Kills", LintError},
+		{"zero_matches", "Adr", LintWarning},
+		{"unused_parser", "", LintWarning},
+	}
+
+	for _, test := range tests {
+		issue := findLintIssue(report, test.kind, test.field)
+		if issue == nil {
+			t.Errorf("Missing %s issue for field '%s'", test.kind, test.field)
+			continue
+		}
+
+		if issue.Severity != test.severity {
+			t.Errorf("Wrong severity of %s issue for field '%s', want %s, get %s", test.kind, test.field, test.severity, issue.Severity)
+		}
+	}
+
+	for _, field := range []string{"Id", "Date", "Patch", "Stage", "Score.Team1", "Score.Team2", "Name"} {
+		for _, issue := range report.Issues {
+			if issue.Field == field {
+				t.Errorf("Field '%s' should have no issue, get %s: %s", field, issue.Kind, issue.Message)
+			}
+		}
+	}
+
+	if issue := findLintIssue(report, "zero_matches", "Adr"); issue != nil && issue.Count != 2 {
+		t.Errorf("Issue should be counted once per row, want 2, get %d", issue.Count)
+	}
+
+	if issue := findLintIssue(report, "unused_parser", ""); issue != nil && !strings.Contains(issue.Message, "oldParser") {
+		t.Errorf("Wrong unused parser, get %s", issue.Message)
+	}
+}
+
+func TestLintReportOutput(t *testing.T) {
+	report := lintTestReport(t)
+
+	var text bytes.Buffer
+	if err := report.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(text.String(), "error match_1.html: sample.Match.Winner: value 'TBD' can't be parsed to int") {
+		t.Errorf("Text report should contain one line per issue, get:\n%s", text.String())
+	}
+
+	var jsonReport LintReport
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &jsonReport); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(jsonReport.Issues) != len(report.Issues) || jsonReport.Checked != report.Checked {
+		t.Errorf("JSON report doesn't match the report")
+	}
+}
+
+func TestLintUnknownTarget(t *testing.T) {
+	report, err := LintDirs([]string{"testdata/lint/types"}, "testdata/lint/pages", LintConfig{
+		Targets: []LintTarget{{Type: "Nope"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Errors() == 0 {
+		t.Errorf("Unknown target type should be reported")
+	}
+}
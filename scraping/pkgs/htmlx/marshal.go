@@ -0,0 +1,832 @@
+package htmlx
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Formatter convert a field value back to the raw string its parser read, it is the inverse of a [Parser].
+// Pointer fields are dereferenced before being passed to the formatter
+type Formatter func(val any) (string, error)
+
+var formatterRegistry = struct {
+	mu         sync.RWMutex
+	formatters map[string]Formatter
+}{
+	formatters: map[string]Formatter{},
+}
+
+// Register a formatter globally under the parser tag it inverts, e.g "idParser" or "default(0)|int".
+// Formatters set with [SetFormatters] take precedence over the registered ones
+func RegisterFormatter(parserTag string, formatter Formatter) {
+	formatterRegistry.mu.Lock()
+	defer formatterRegistry.mu.Unlock()
+	formatterRegistry.formatters[parserTag] = formatter
+}
+
+// Return a copy of the globally registered formatters
+func RegisteredFormatters() map[string]Formatter {
+	formatterRegistry.mu.RLock()
+	defer formatterRegistry.mu.RUnlock()
+	return maps.Clone(formatterRegistry.formatters)
+}
+
+func lookupFormatter(parserTag string, config *Config) (Formatter, bool) {
+	if formatter, ok := config.formatters[parserTag]; ok {
+		return formatter, true
+	}
+
+	formatterRegistry.mu.RLock()
+	defer formatterRegistry.mu.RUnlock()
+	formatter, ok := formatterRegistry.formatters[parserTag]
+	return formatter, ok
+}
+
+// Render the struct into a minimal HTML document which parse back into the same struct.
+// Every field is rendered at its first selector, creating the elements the selector require along the way.
+// Values are formatted with the formatter of their parser tag if there is one, otherwise with the inverse of the
+// built-in parsing, so fields using a non invertible parser or regex need a formatter to round trip.
+// Nil pointers are not rendered
+func Marshal(s any, opts ...Option) (string, error) {
+	config := NewDefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	v := reflect.ValueOf(s)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", fmt.Errorf("Can't marshal a nil pointer")
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("value is not a struct")
+	}
+
+	doc, err := html.Parse(strings.NewReader(""))
+	if err != nil {
+		return "", err
+	}
+
+	m := &marshaler{
+		config:       config,
+		root:         cascadia.Query(doc, cascadia.MustCompile("body")),
+		placeholders: map[*html.Node]bool{},
+	}
+
+	// Structs with column fields are rendered as the only body row of a table
+	if hasColumnFields(v.Type()) {
+		m.header, m.row = newElement("tr"), newElement("tr")
+
+		table, thead, tbody := newElement("table"), newElement("thead"), newElement("tbody")
+		thead.AppendChild(m.header)
+		tbody.AppendChild(m.row)
+		table.AppendChild(thead)
+		table.AppendChild(tbody)
+		m.root.AppendChild(table)
+		m.root = m.row
+	}
+
+	if err = m.marshalReflectValue(v); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err = html.Render(&buf, doc); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+type marshaler struct {
+	config *Config
+	root   *html.Node
+	// Elements only inserted to satisfy :nth-child, they can be replaced by the element they stand for
+	placeholders map[*html.Node]bool
+	// Header and body row of the table the column fields are rendered into
+	header *html.Node
+	row    *html.Node
+}
+
+func hasColumnFields(t reflect.Type) bool {
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		if _, ok := field.Tag.Lookup("column"); ok {
+			return true
+		}
+
+		if field.Type.Kind() == reflect.Struct && hasColumnFields(field.Type) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Return the cell of the body row under the column with the name, creating the column if it doesn't exist yet
+func (m *marshaler) columnCell(name string) *html.Node {
+	headerCells, rowCells := elementChildren(m.header), elementChildren(m.row)
+
+	idx := slices.IndexFunc(headerCells, func(cell *html.Node) bool {
+		return cell.FirstChild != nil && cell.FirstChild.Data == name
+	})
+
+	if idx < 0 {
+		idx = max(len(headerCells), len(rowCells))
+
+		for range idx - len(headerCells) {
+			m.header.AppendChild(newElement("th"))
+		}
+
+		headerCell := newElement("th")
+		headerCell.AppendChild(&html.Node{Type: html.TextNode, Data: name})
+		m.header.AppendChild(headerCell)
+	}
+
+	for range idx + 1 - len(rowCells) {
+		cell := newElement("td")
+		m.row.AppendChild(cell)
+		m.placeholders[cell] = true
+	}
+
+	cell := elementChildren(m.row)[idx]
+	delete(m.placeholders, cell)
+	return cell
+}
+
+func (m *marshaler) marshalReflectValue(v reflect.Value) error {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		fieldType := t.Field(i)
+		fieldVal := v.Field(i)
+
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		_, hasJSONPath := fieldType.Tag.Lookup("jsonpath")
+
+		if fieldVal.Kind() == reflect.Struct && !isStructToParse(fieldVal) && !hasJSONPath {
+			if !m.config.noPassThroughStruct {
+				if err := m.marshalReflectValue(fieldVal); err != nil {
+					return fmt.Errorf("Error marshaling field '%s' : %s", fieldType.Name, err.Error())
+				}
+			}
+
+			continue
+		}
+
+		htmlxTags, err := initializeHtmlxTags(fieldType)
+		if err != nil {
+			if m.config.parseAllFields || !errors.Is(err, errMissingSelector) {
+				return fmt.Errorf("Error extracting tags from field '%s': %s", fieldType.Name, err.Error())
+			}
+
+			continue
+		}
+
+		if fieldVal.Type() == selectionType {
+			continue
+		}
+
+		if htmlxTags.matrix != "" {
+			return fmt.Errorf("Matrix field '%s' is not supported", fieldType.Name)
+		}
+
+		if htmlxTags.jsonPath != nil {
+			return fmt.Errorf("Json field '%s' is not supported", fieldType.Name)
+		}
+
+		rawVal, skip, err := formatValue(fieldVal, htmlxTags, m.config)
+		if err != nil {
+			return fmt.Errorf("Error formatting value of field '%s': %s", fieldType.Name, err.Error())
+		}
+
+		if skip {
+			continue
+		}
+
+		root := m.root
+		if len(htmlxTags.columns) > 0 {
+			root = m.columnCell(htmlxTags.columns[0])
+		}
+
+		node := root
+		if len(htmlxTags.selectors) > 0 {
+			node, err = m.build(root, htmlxTags.selectors[0])
+		}
+
+		if err != nil {
+			return fmt.Errorf("Error rendering selector of field '%s': %s", fieldType.Name, err.Error())
+		}
+
+		if err = m.setRawValue(node, htmlxTags.source, rawVal); err != nil {
+			return fmt.Errorf("Error rendering value of field '%s': %s", fieldType.Name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Format the field value to the raw string, skip is true for nil pointers
+func formatValue(fieldVal reflect.Value, htmlxTags HtmlxTags, config *Config) (rawVal string, skip bool, err error) {
+	if fieldVal.Kind() == reflect.Ptr {
+		if fieldVal.IsNil() {
+			return "", true, nil
+		}
+
+		fieldVal = fieldVal.Elem()
+	}
+
+	if htmlxTags.parser != "" {
+		if formatter, ok := lookupFormatter(htmlxTags.parser, config); ok {
+			rawVal, err = formatter(fieldVal.Interface())
+			return rawVal, false, err
+		}
+	}
+
+	if fieldVal.Kind() == reflect.Slice {
+		sep := ","
+		for _, stage := range splitPipeline(htmlxTags.parser) {
+			if matches := regexp.MustCompile(`^split\((.+)\)$`).FindStringSubmatch(stage); matches != nil {
+				sep = matches[1]
+			}
+		}
+
+		elements := make([]string, fieldVal.Len())
+		for i := range fieldVal.Len() {
+			if elements[i], err = formatSupportedValue(fieldVal.Index(i), config); err != nil {
+				return "", false, fmt.Errorf("element %d: %s", i, err.Error())
+			}
+		}
+
+		return strings.Join(elements, sep), false, nil
+	}
+
+	rawVal, err = formatSupportedValue(fieldVal, config)
+	return rawVal, false, err
+}
+
+// Format the value the way parseSupportedValues parse it
+func formatSupportedValue(v reflect.Value, config *Config) (string, error) {
+	switch v.Type() {
+	case reflect.TypeOf(time.Time{}):
+		return v.Interface().(time.Time).Format(config.dateFormat), nil
+	case reflect.TypeOf(time.Duration(0)):
+		return formatDuration(time.Duration(v.Int())), nil
+	}
+
+	textMarshalerType := reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	if v.Type().Implements(textMarshalerType) || reflect.PointerTo(v.Type()).Implements(textMarshalerType) {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+
+		text, err := ptr.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+
+	return "", fmt.Errorf("Value of type %s is not supported", v.Type().String())
+}
+
+// Format whole seconds durations as clock time the way they are shown on vlr.gg, e.g 1:05 or 1:02:05
+func formatDuration(d time.Duration) string {
+	if d < 0 || d%time.Second != 0 {
+		return d.String()
+	}
+
+	seconds := int(d / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+type selectorStep struct {
+	combinator byte
+	compound   string
+}
+
+// Split the selector into compound selectors and the combinators before them, the first one default to descendant
+func splitSelectorSteps(selector string) ([]selectorStep, error) {
+	var steps []selectorStep
+	var current strings.Builder
+	var depth int
+	var quote rune
+	combinator := byte(' ')
+
+	flush := func() {
+		if current.Len() > 0 {
+			steps = append(steps, selectorStep{combinator: combinator, compound: current.String()})
+			current.Reset()
+			combinator = ' '
+		}
+	}
+
+	for _, r := range selector {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(' || r == '[':
+			depth++
+		case r == ')' || r == ']':
+			depth--
+		case depth > 0:
+		case r == ',':
+			return nil, fmt.Errorf("selector groups are not supported: '%s'", selector)
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+			continue
+		case r == '>' || r == '+' || r == '~':
+			flush()
+			combinator = byte(r)
+			continue
+		}
+
+		current.WriteRune(r)
+	}
+
+	flush()
+
+	if len(steps) == 0 || current.Len() > 0 || combinator != ' ' {
+		return nil, fmt.Errorf("selector '%s' is incomplete", selector)
+	}
+
+	return steps, nil
+}
+
+// The parts of a compound selector needed to create an element matching it
+type compoundSpec struct {
+	tag     string
+	attrs   []html.Attribute
+	classes []string
+	// Position required by :nth-child(n) or :first-child, 0 if there is none
+	nth int
+	has []string
+}
+
+var (
+	identRegex = regexp.MustCompile(`^-?[a-zA-Z_][a-zA-Z0-9_-]*`)
+	attrRegex  = regexp.MustCompile(
+		`^\s*([a-zA-Z0-9_:-]+)\s*(?:([~|^$*!]?=)\s*(?:"([^"]*)"|'([^']*)'|([^\s"']*))\s*[iIsS]?)?\s*$`,
+	)
+)
+
+// Return the index of the bracket closing the one at start
+func closingBracket(s string, start int) int {
+	var depth int
+	var quote byte
+
+	for i := start; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// Parse the compound selector, pseudo classes other than :nth-child, :first-child and :has are left for cascadia to check
+func parseCompound(compound string) (compoundSpec, error) {
+	var spec compoundSpec
+
+	i := 0
+	if compound[0] == '*' {
+		i = 1
+	} else if tag := identRegex.FindString(compound); tag != "" {
+		spec.tag = strings.ToLower(tag)
+		i = len(tag)
+	}
+
+	for i < len(compound) {
+		switch compound[i] {
+		case '#', '.':
+			name := identRegex.FindString(compound[i+1:])
+			if name == "" {
+				return spec, fmt.Errorf("invalid compound selector '%s'", compound)
+			}
+
+			if compound[i] == '#' {
+				spec.attrs = append(spec.attrs, html.Attribute{Key: "id", Val: name})
+			} else {
+				spec.classes = append(spec.classes, name)
+			}
+
+			i += len(name) + 1
+		case '[':
+			end := closingBracket(compound, i)
+			if end < 0 {
+				return spec, fmt.Errorf("invalid compound selector '%s'", compound)
+			}
+
+			matches := attrRegex.FindStringSubmatch(compound[i+1 : end])
+			if matches == nil {
+				return spec, fmt.Errorf("invalid attribute selector in '%s'", compound)
+			}
+
+			// Leaving the attribute out satisfy [attr!=value]
+			if matches[2] != "!=" {
+				spec.attrs = append(spec.attrs, html.Attribute{Key: matches[1], Val: matches[3] + matches[4] + matches[5]})
+			}
+
+			i = end + 1
+		case ':':
+			name := identRegex.FindString(compound[i+1:])
+			if name == "" {
+				return spec, fmt.Errorf("invalid pseudo class in '%s'", compound)
+			}
+
+			i += len(name) + 1
+
+			var args string
+			if i < len(compound) && compound[i] == '(' {
+				end := closingBracket(compound, i)
+				if end < 0 {
+					return spec, fmt.Errorf("invalid compound selector '%s'", compound)
+				}
+
+				args = strings.TrimSpace(compound[i+1 : end])
+				i = end + 1
+			}
+
+			switch name {
+			case "first-child":
+				spec.nth = 1
+			case "nth-child":
+				if n, err := strconv.Atoi(args); err == nil && n > 0 {
+					spec.nth = n
+				}
+			case "has":
+				spec.has = append(spec.has, args)
+			}
+		default:
+			return spec, fmt.Errorf("invalid compound selector '%s'", compound)
+		}
+	}
+
+	if spec.tag == "" {
+		spec.tag = "div"
+	}
+
+	return spec, nil
+}
+
+func elementChildren(n *html.Node) []*html.Node {
+	var children []*html.Node
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			children = append(children, c)
+		}
+	}
+
+	return children
+}
+
+func newElement(tag string) *html.Node {
+	return &html.Node{Type: html.ElementNode, Data: tag, DataAtom: atom.Lookup([]byte(tag))}
+}
+
+func insertAfter(node, prev *html.Node) {
+	prev.Parent.InsertBefore(node, prev.NextSibling)
+}
+
+// Return the element matched by the selector relative to the parent, creating the missing elements along the way
+func (m *marshaler) build(parent *html.Node, selector string) (*html.Node, error) {
+	steps, err := splitSelectorSteps(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	// Selectors relative to an element can be anchored with the element itself, e.g "td > span" inside a cell
+	if matcher, err := cascadia.Compile(steps[0].compound); err == nil && steps[0].combinator == ' ' && matcher.Match(parent) {
+		steps = steps[1:]
+	}
+
+	current := parent
+	for _, step := range steps {
+		if current, err = m.buildStep(current, step); err != nil {
+			return nil, err
+		}
+	}
+
+	return current, nil
+}
+
+func (m *marshaler) buildStep(context *html.Node, step selectorStep) (*html.Node, error) {
+	matcher, err := cascadia.Compile(step.compound)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector '%s': %s", step.compound, err.Error())
+	}
+
+	spec, err := parseCompound(step.compound)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*html.Node
+
+	switch step.combinator {
+	case ' ', '>':
+		candidates = elementChildren(context)
+	case '+', '~':
+		if context == m.root {
+			return nil, fmt.Errorf("selector can't start with '%c'", step.combinator)
+		}
+
+		for sibling := context.NextSibling; sibling != nil; sibling = sibling.NextSibling {
+			if sibling.Type == html.ElementNode {
+				candidates = append(candidates, sibling)
+				if step.combinator == '+' {
+					break
+				}
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		if matcher.Match(candidate) {
+			delete(m.placeholders, candidate)
+			return candidate, nil
+		}
+	}
+
+	if step.combinator == ' ' {
+		if descendant := cascadia.Query(context, matcher); descendant != nil {
+			delete(m.placeholders, descendant)
+			return descendant, nil
+		}
+	}
+
+	node := newElement(spec.tag)
+	node.Attr = append(node.Attr, spec.attrs...)
+	if len(spec.classes) > 0 {
+		node.Attr = append(node.Attr, html.Attribute{Key: "class", Val: strings.Join(spec.classes, " ")})
+	}
+
+	switch step.combinator {
+	case ' ', '>':
+		if err = m.insertChild(context, node, spec.nth, step.combinator == ' '); err != nil {
+			return nil, err
+		}
+	case '+', '~':
+		insertAfter(node, context)
+	}
+
+	for _, hasSelector := range spec.has {
+		if _, err = m.build(node, hasSelector); err != nil {
+			return nil, err
+		}
+	}
+
+	if !matcher.Match(node) {
+		return nil, fmt.Errorf("unable to render an element matching '%s'", step.compound)
+	}
+
+	return node, nil
+}
+
+// Insert the element as the n-th element child of the parent, n = 0 means anywhere.
+// Missing elements before it are filled with placeholders of the same tag.
+// Descendant elements can be wrapped into new elements when the position is taken or the HTML parser require a context
+func (m *marshaler) insertChild(parent, node *html.Node, n int, wrappable bool) error {
+	parent, err := m.tableContext(parent, node.Data, wrappable)
+	if err != nil {
+		return err
+	}
+
+	children := elementChildren(parent)
+
+	if n == 0 {
+		parent.AppendChild(node)
+		return nil
+	}
+
+	if len(children) < n {
+		for range n - 1 - len(children) {
+			placeholder := newElement(node.Data)
+			parent.AppendChild(placeholder)
+			m.placeholders[placeholder] = true
+		}
+
+		parent.AppendChild(node)
+		return nil
+	}
+
+	if target := children[n-1]; m.placeholders[target] {
+		parent.InsertBefore(node, target)
+		parent.RemoveChild(target)
+		delete(m.placeholders, target)
+		return nil
+	}
+
+	if !wrappable || parent.DataAtom == atom.Tr {
+		return fmt.Errorf("position %d of <%s> is already taken by another element", n, parent.Data)
+	}
+
+	wrapper := newElement("div")
+	parent.AppendChild(wrapper)
+	return m.insertChild(wrapper, node, n, false)
+}
+
+// The parent table elements must have for the HTML parser to keep them in place
+var tableParents = map[atom.Atom][]atom.Atom{
+	atom.Td:       {atom.Tr},
+	atom.Th:       {atom.Tr},
+	atom.Tr:       {atom.Tbody, atom.Thead, atom.Tfoot},
+	atom.Tbody:    {atom.Table},
+	atom.Thead:    {atom.Table},
+	atom.Tfoot:    {atom.Table},
+	atom.Caption:  {atom.Table},
+	atom.Colgroup: {atom.Table},
+}
+
+// Return the element to insert a table element of the tag into, creating the implied table, tbody and tr if needed
+func (m *marshaler) tableContext(parent *html.Node, tag string, wrappable bool) (*html.Node, error) {
+	parents, ok := tableParents[atom.Lookup([]byte(tag))]
+	if !ok {
+		return parent, nil
+	}
+
+	for _, a := range parents {
+		if parent.DataAtom == a {
+			return parent, nil
+		}
+	}
+
+	if !wrappable {
+		return nil, fmt.Errorf("<%s> can't be a direct child of <%s>", tag, parent.Data)
+	}
+
+	wrapper := newElement(parents[0].String())
+	wrapperParent, err := m.tableContext(parent, wrapper.Data, true)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapperParent.AppendChild(wrapper)
+	return wrapper, nil
+}
+
+func getAttr(node *html.Node, key string) (string, bool) {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+
+	return "", false
+}
+
+func setAttr(node *html.Node, key, val string) {
+	for i, attr := range node.Attr {
+		if attr.Key == key {
+			node.Attr[i].Val = val
+			return
+		}
+	}
+
+	node.Attr = append(node.Attr, html.Attribute{Key: key, Val: val})
+}
+
+// Return the non blank text nodes which are direct children of the element
+func childTextNodes(node *html.Node) []*html.Node {
+	var textNodes []*html.Node
+
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode && strings.TrimSpace(c.Data) != "" {
+			textNodes = append(textNodes, c)
+		}
+	}
+
+	return textNodes
+}
+
+// Write the raw value into the element so that getRawValue read it back from the source
+func (m *marshaler) setRawValue(node *html.Node, source, rawVal string) error {
+	if source == "content" || source == "text" {
+		source = "text=0"
+	}
+
+	switch {
+	case source == "html":
+		if node.FirstChild != nil {
+			return fmt.Errorf("<%s> already has content", node.Data)
+		}
+
+		nodes, err := html.ParseFragment(strings.NewReader(rawVal), node)
+		if err != nil {
+			return err
+		}
+
+		for _, n := range nodes {
+			node.AppendChild(n)
+		}
+	case regexp.MustCompile(`^text=[0-9]+$`).MatchString(source):
+		if strings.TrimSpace(rawVal) == "" {
+			return nil
+		}
+
+		n, _ := strconv.Atoi(source[5:])
+		textNodes := childTextNodes(node)
+
+		if len(textNodes) > n {
+			if textNodes[n].Data != rawVal {
+				return fmt.Errorf("text node %d of <%s> already contains '%s'", n, node.Data, textNodes[n].Data)
+			}
+
+			return nil
+		}
+
+		// Text nodes are separated with <br> so the HTML parser doesn't merge them
+		for i := len(textNodes); i <= n; i++ {
+			if node.LastChild != nil && node.LastChild.Type == html.TextNode {
+				node.AppendChild(newElement("br"))
+			}
+
+			text := "-"
+			if i == n {
+				text = rawVal
+			}
+
+			node.AppendChild(&html.Node{Type: html.TextNode, Data: text})
+		}
+	case regexp.MustCompile(`^attr=[a-zA-Z-0-9]+$`).MatchString(source):
+		attrName := source[5:]
+
+		existing, ok := getAttr(node, attrName)
+		if ok && existing != rawVal {
+			// Classes required by the selector can be kept as long as the value contains them
+			if attrName != "class" || !containsAllClasses(rawVal, existing) {
+				return fmt.Errorf("attribute %s of <%s> already set to '%s'", attrName, node.Data, existing)
+			}
+		}
+
+		setAttr(node, attrName, rawVal)
+	default:
+		return fmt.Errorf("source %s is not supported", source)
+	}
+
+	return nil
+}
+
+// Check if the class attribute value contains all the classes of the required one
+func containsAllClasses(classAttr, required string) bool {
+	classes := strings.Fields(classAttr)
+
+	for _, class := range strings.Fields(required) {
+		if !slices.Contains(classes, class) {
+			return false
+		}
+	}
+
+	return true
+}
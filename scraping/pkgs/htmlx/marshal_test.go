@@ -0,0 +1,240 @@
+package htmlx
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type MarshalPlayerStat struct {
+	PlayerId    int      `selector:"td.mod-player > div > a"          source:"attr=href"  parser:"playerIdParser"`
+	AgentId     int      `selector:"td.mod-agents > div > span > img" source:"attr=title" parser:"agentParser"`
+	Rating      *float64 `selector:"td:nth-child(3) > span > span"`
+	Acs         *float64 `selector:"td:nth-child(4) > span > span"`
+	Kills       *int     `selector:"td:nth-child(5) > span > span"`
+	Deaths      *int     `selector:"td:nth-child(6) > span > span:nth-child(2) > span"`
+	Assists     *int     `selector:"td:nth-child(7) > span > span"`
+	Kast        *float64 `selector:"td:nth-child(9) > span > span"`
+	FirstKills  *int     `selector:"td:nth-child(12) > span > span"`
+	FirstDeaths *int     `selector:"td:nth-child(13) > span > span"`
+}
+
+var marshalAgents = map[string]int{"Jett": 1, "Sova": 2}
+
+var marshalPlayerStatOptions = []Option{
+	SetParsers(map[string]Parser{
+		"playerIdParser": func(rawVal string) (any, error) {
+			return IntParser(strings.Split(rawVal, "/")[2])
+		},
+		"agentParser": func(rawVal string) (any, error) {
+			id, ok := marshalAgents[rawVal]
+			if !ok {
+				return nil, fmt.Errorf("unknown agent %s", rawVal)
+			}
+
+			return id, nil
+		},
+	}),
+	SetFormatters(map[string]Formatter{
+		"playerIdParser": func(val any) (string, error) {
+			return fmt.Sprintf("/player/%d/tenz", val), nil
+		},
+		"agentParser": func(val any) (string, error) {
+			for name, id := range marshalAgents {
+				if id == val {
+					return name, nil
+				}
+			}
+
+			return "", fmt.Errorf("unknown agent id %v", val)
+		},
+	}),
+}
+
+type MarshalScore struct {
+	Def int `selector:"div.score > span.mod-ct"`
+	Atk int `selector:"div.score > span.mod-t"`
+}
+
+type MarshalMatchHeader struct {
+	Team1Id    int           `selector:"div.header > a.mod-1 || a.team-1" source:"attr=href" parser:"idParser"`
+	Team2Id    int           `selector:"div.header > a.mod-2 || a.team-2" source:"attr=href" parser:"idParser"`
+	Team1Score int           `selector:"div.vs > div:nth-child(1) > span:nth-child(1)"`
+	Team2Score int           `selector:"div.vs > div:nth-child(1) > span:nth-child(3)"`
+	Side       string        `selector:"div.vs > div:nth-child(1) > span:nth-child(2)" source:"attr=class"`
+	Date       time.Time     `selector:"div.date" source:"attr=data-utc-ts"`
+	Duration   time.Duration `selector:"div.map-duration"`
+	Completed  bool          `selector:"div.status" source:"text=1"`
+	Patch      float64       `selector:"div.patch" parser:"stripPrefix(Patch )"`
+	Agents     []string      `selector:"div.agents" parser:"split(, )"`
+	Note       *string       `selector:"div.note"`
+	Notes      string        `selector:"div.notes:has(span.icon)"`
+	Score      MarshalScore
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	rating, acs, kast := 1.25, 245.5, 0.0
+	kills, deaths, assists, firstKills := 21, 14, 0, 3
+
+	stat := MarshalPlayerStat{
+		PlayerId:   9,
+		AgentId:    2,
+		Rating:     &rating,
+		Acs:        &acs,
+		Kills:      &kills,
+		Deaths:     &deaths,
+		Assists:    &assists,
+		Kast:       &kast,
+		FirstKills: &firstKills,
+	}
+
+	content, err := Marshal(stat, marshalPlayerStatOptions...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedStat := MarshalPlayerStat{}
+	if err = ParseFromString(&parsedStat, content, marshalPlayerStatOptions...); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(stat, parsedStat) {
+		t.Errorf("Round trip changed the player stat\nwant %+v\nget  %+v\nhtml %s", stat, parsedStat, content)
+	}
+}
+
+func TestMarshalRoundTripTypes(t *testing.T) {
+	note := "Map 2 was a forfeit"
+
+	header := MarshalMatchHeader{
+		Team1Id:    624,
+		Team2Id:    2,
+		Team1Score: 2,
+		Team2Score: 1,
+		Side:       "mod-ct",
+		Date:       time.Date(2025, 4, 12, 16, 30, 0, 0, time.UTC),
+		Duration:   time.Hour + 2*time.Minute + 5*time.Second,
+		Completed:  true,
+		Patch:      10.04,
+		Agents:     []string{"Jett", "Sova", "Omen"},
+		Note:       &note,
+		Notes:      "no notes",
+		Score:      MarshalScore{Def: 7, Atk: 6},
+	}
+
+	idOption := SetParsers(map[string]Parser{
+		"idParser": func(rawVal string) (any, error) {
+			return IntParser(strings.Split(rawVal, "/")[2])
+		},
+	})
+	idFormatter := SetFormatters(map[string]Formatter{
+		"idParser": func(val any) (string, error) {
+			return "/team/" + strconv.Itoa(val.(int)) + "/", nil
+		},
+		"stripPrefix(Patch )": func(val any) (string, error) {
+			return fmt.Sprintf("Patch %v", val), nil
+		},
+	})
+
+	content, err := Marshal(&header, idOption, idFormatter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedHeader := MarshalMatchHeader{}
+	if err = ParseFromString(&parsedHeader, content, idOption); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(header, parsedHeader) {
+		t.Errorf("Round trip changed the match header\nwant %+v\nget  %+v\nhtml %s", header, parsedHeader, content)
+	}
+
+	header.Note = nil
+	if content, err = Marshal(header, idOption, idFormatter); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(content, "note\"") {
+		t.Errorf("Nil pointer field should not be rendered, get %s", content)
+	}
+}
+
+func TestMarshalTableContext(t *testing.T) {
+	type row struct {
+		Name  string `selector:"td.name"`
+		Kills int    `selector:"td:nth-child(3)"`
+	}
+
+	content, err := Marshal(row{Name: "TenZ", Kills: 21})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(content, "<table><tbody><tr>") {
+		t.Errorf("Table cells should be wrapped in a table, get %s", content)
+	}
+
+	parsedRow := row{}
+	if err = ParseFromString(&parsedRow, content); err != nil {
+		t.Fatal(err)
+	}
+
+	if parsedRow.Name != "TenZ" || parsedRow.Kills != 21 {
+		t.Errorf("Wrong row, want {TenZ 21}, get %+v", parsedRow)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	tests := map[string]any{
+		"not a struct": 1,
+		"conflicting values": struct {
+			A string `selector:"div.a"`
+			B string `selector:"div.a"`
+		}{A: "x", B: "y"},
+		"conflicting attributes": struct {
+			A string `selector:"a" source:"attr=href"`
+			B string `selector:"a" source:"attr=href"`
+		}{A: "/x", B: "/y"},
+		"unsupported source": struct {
+			A string `selector:"div.a" source:"outerHtml"`
+		}{A: "<div></div>"},
+		"taken position": struct {
+			A string `selector:"div > span.a"`
+			B string `selector:"div > p:nth-child(1)"`
+		}{A: "x", B: "y"},
+		"selector group": struct {
+			A string `selector:"div.a, div.b"`
+		}{A: "x"},
+	}
+
+	for name, s := range tests {
+		if _, err := Marshal(s); err == nil {
+			t.Errorf("%s: marshal should return error", name)
+		}
+	}
+}
+
+func TestSplitSelectorSteps(t *testing.T) {
+	tests := map[string][]selectorStep{
+		"div.a > span":                {{' ', "div.a"}, {'>', "span"}},
+		"td:nth-child(3)  span + div": {{' ', "td:nth-child(3)"}, {' ', "span"}, {'+', "div"}},
+		`a[title="x > y"]~b`:          {{' ', `a[title="x > y"]`}, {'~', "b"}},
+		"> div:has(div + div)":        {{'>', "div:has(div + div)"}},
+	}
+
+	for selector, want := range tests {
+		got, err := splitSelectorSteps(selector)
+		if err != nil {
+			t.Errorf("'%s': %s", selector, err.Error())
+			continue
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Wrong steps for '%s', want %v, get %v", selector, want, got)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package htmlx
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NumberLocale hold the separators used to write numbers.
+// The zero locale guess the separators and reject the numbers which can be read both ways, e.g "1,234"
+type NumberLocale struct {
+	Decimal rune
+	Group   []rune
+}
+
+// The locales which can be passed to the number and percent parser tags, e.g `parser:"number(de)"`
+var NumberLocales = map[string]NumberLocale{
+	"auto": {},
+	"en":   {Decimal: '.', Group: []rune{','}},
+	"de":   {Decimal: ',', Group: []rune{'.'}},
+	"fr":   {Decimal: ',', Group: []rune{' ', '\u00a0', '\u202f'}},
+	"ch":   {Decimal: '.', Group: []rune{'\''}},
+}
+
+// Content meaning that there is no value, e.g a stat the site couldn't compute
+var nullNumbers = []string{"", "-", "\u2013", "\u2014", "n/a", "na"}
+
+var numberSuffixes = map[rune]float64{
+	'k': 1e3,
+	'K': 1e3,
+	'M': 1e6,
+	'B': 1e9,
+}
+
+func isNumberSeparator(r rune) bool {
+	return r == '.' || r == ',' || r == '\'' || r == ' ' || r == '\u00a0' || r == '\u202f'
+}
+
+// Remove the floating point error, e.g 12.3 * 1000 = 12300.000000000002
+func roundFloatError(val float64) float64 {
+	if rounded := math.Round(val); math.Abs(val-rounded) < 1e-9 {
+		return rounded
+	}
+
+	return val
+}
+
+// A number split from its decorations, the digits still contain the separators
+type numberParts struct {
+	negative   bool
+	percent    bool
+	multiplier float64
+	digits     string
+}
+
+// Split the sign, the currency symbols, the percent sign and the k/M/B suffix from the digits
+func splitNumber(numberStr string) (numberParts, error) {
+	parts := numberParts{multiplier: 1}
+	runes := []rune(numberStr)
+
+	start, signed := 0, false
+	for ; start < len(runes); start++ {
+		r := runes[start]
+
+		if (r == '-' || r == '+' || r == '\u2212') && !signed {
+			parts.negative = r != '+'
+			signed = true
+		} else if !unicode.Is(unicode.Sc, r) && !unicode.IsSpace(r) {
+			break
+		}
+	}
+
+	stop, suffixed := len(runes), false
+	for ; stop > start; stop-- {
+		r := runes[stop-1]
+
+		if multiplier, ok := numberSuffixes[r]; ok && !suffixed && !parts.percent {
+			parts.multiplier = multiplier
+			suffixed = true
+		} else if r == '%' && !parts.percent && !suffixed {
+			parts.percent = true
+		} else if !unicode.Is(unicode.Sc, r) && !unicode.IsSpace(r) {
+			break
+		}
+	}
+
+	parts.digits = string(runes[start:stop])
+
+	if parts.digits == "" || !unicode.IsDigit(runes[start]) || !unicode.IsDigit(runes[stop-1]) {
+		return parts, fmt.Errorf("%s is not valid for parsing to number", numberStr)
+	}
+
+	for _, r := range parts.digits {
+		if !unicode.IsDigit(r) && !isNumberSeparator(r) {
+			return parts, fmt.Errorf("%s is not valid for parsing to number", numberStr)
+		}
+	}
+
+	return parts, nil
+}
+
+// Check that the integer part is grouped by thousands, e.g 1,234,567
+func checkDigitGroups(integer string, group rune, numberStr string) error {
+	groups := strings.Split(integer, string(group))
+
+	for i, digits := range groups {
+		if (i == 0 && (len(digits) == 0 || len(digits) > 3)) || (i > 0 && len(digits) != 3) {
+			return fmt.Errorf("%s has invalid digit grouping", numberStr)
+		}
+	}
+
+	return nil
+}
+
+// Guess the decimal separator of the digits, 0 if there is none.
+// A single separator followed by exactly three digits could be either, so the number is ambiguous
+func guessDecimalSeparator(digits, numberStr string) (rune, error) {
+	var separators []rune
+	for _, r := range digits {
+		if isNumberSeparator(r) && !slices.Contains(separators, r) {
+			separators = append(separators, r)
+		}
+	}
+
+	switch len(separators) {
+	case 0:
+		return 0, nil
+	case 1:
+		sep := separators[0]
+		if sep != '.' && sep != ',' || strings.Count(digits, string(sep)) > 1 {
+			return 0, nil
+		}
+
+		integer, fraction, _ := strings.Cut(digits, string(sep))
+		if len(fraction) != 3 || integer == "0" {
+			return sep, nil
+		}
+
+		return 0, fmt.Errorf("%s is ambiguous, the locale must be set, e.g number(en) or number(de)", numberStr)
+	case 2:
+		// The last separator is the decimal one, e.g 1.234,5
+		sep, _ := utf8.DecodeRuneInString(digits[strings.LastIndexFunc(digits, isNumberSeparator):])
+		if sep != '.' && sep != ',' {
+			return 0, fmt.Errorf("%s has invalid decimal separator '%c'", numberStr, sep)
+		}
+
+		return sep, nil
+	default:
+		return 0, fmt.Errorf("%s has too many kinds of separators", numberStr)
+	}
+}
+
+// Parse the number written in the locale, nil is returned for empty content and dashes.
+// Signs, currency symbols, the percent sign and k/M/B suffixes are accepted, e.g "-$1,234.5", "12.3k", "23%".
+// The number is divided by 100 if ratio is true
+func parseNumber(rawVal string, locale NumberLocale, ratio bool) (any, error) {
+	numberStr := strings.TrimSpace(rawVal)
+	if slices.Contains(nullNumbers, strings.ToLower(numberStr)) {
+		return nil, nil
+	}
+
+	parts, err := splitNumber(numberStr)
+	if err != nil {
+		return nil, err
+	}
+
+	decimal := locale.Decimal
+	groups := locale.Group
+
+	if decimal == 0 {
+		if decimal, err = guessDecimalSeparator(parts.digits, numberStr); err != nil {
+			return nil, err
+		}
+
+		for _, r := range parts.digits {
+			if isNumberSeparator(r) && r != decimal && !slices.Contains(groups, r) {
+				groups = append(groups, r)
+			}
+		}
+	}
+
+	integer, fraction, hasFraction := parts.digits, "", false
+	if decimal != 0 {
+		integer, fraction, hasFraction = strings.Cut(parts.digits, string(decimal))
+	}
+
+	if strings.IndexFunc(fraction, isNumberSeparator) >= 0 {
+		return nil, fmt.Errorf("%s has separators after the decimal one", numberStr)
+	}
+
+	for _, r := range integer {
+		if isNumberSeparator(r) && !slices.Contains(groups, r) {
+			return nil, fmt.Errorf("%s has unexpected separator '%c'", numberStr, r)
+		}
+	}
+
+	for _, group := range groups {
+		if strings.ContainsRune(integer, group) {
+			if err = checkDigitGroups(integer, group, numberStr); err != nil {
+				return nil, err
+			}
+
+			integer = strings.ReplaceAll(integer, string(group), "")
+		}
+	}
+
+	floatStr := integer
+	if hasFraction {
+		floatStr += "." + fraction
+	}
+
+	floatVal, err := strconv.ParseFloat(floatStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not valid for parsing to number", numberStr)
+	}
+
+	floatVal *= parts.multiplier
+	if ratio {
+		floatVal /= 100
+	}
+
+	if parts.negative {
+		floatVal = -floatVal
+	}
+
+	return roundFloatError(floatVal), nil
+}
+
+// Return the float64 value of a number written in the locale, nil for empty content and dashes, see [NumberLocale].
+// Signs, currency symbols and k/M/B suffixes are accepted, percentages are returned as is, e.g "23%" give 23
+func NumberParser(locale NumberLocale) Parser {
+	return func(rawVal string) (any, error) {
+		return parseNumber(rawVal, locale, false)
+	}
+}
+
+// Return the ratio of a percentage written in the locale, e.g "23%" give 0.23, nil for empty content and dashes.
+// The percent sign is optional
+func PercentParser(locale NumberLocale) Parser {
+	return func(rawVal string) (any, error) {
+		return parseNumber(rawVal, locale, true)
+	}
+}
+
+// Look up the locale by name, the empty name is the guessing locale
+func lookupNumberLocale(name string) (NumberLocale, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return NumberLocale{}, nil
+	}
+
+	locale, ok := NumberLocales[name]
+	if !ok {
+		return locale, fmt.Errorf("unknown number locale '%s'", name)
+	}
+
+	return locale, nil
+}
@@ -0,0 +1,173 @@
+package htmlx
+
+import (
+	"testing"
+)
+
+func TestNumberParser(t *testing.T) {
+	tests := []struct {
+		locale string
+		rawVal string
+		want   any
+	}{
+		{"auto", "42", 42.0},
+		{"auto", " -3 ", -3.0},
+		{"auto", "+1.31", 1.31},
+		{"auto", "−0.5", -0.5},
+		{"auto", "0.981", 0.981},
+		{"auto", "1,234.5", 1234.5},
+		{"auto", "1.234,5", 1234.5},
+		{"auto", "1,234,567", 1234567.0},
+		{"auto", "12.3k", 12300.0},
+		{"auto", "$1.2M", 1200000.0},
+		{"auto", "-$250", -250.0},
+		{"auto", "1 234 €", 1234.0},
+		{"auto", "28%", 28.0},
+		{"auto", "-", nil},
+		{"auto", "–", nil},
+		{"auto", "N/A", nil},
+		{"auto", "", nil},
+		{"en", "1,234", 1234.0},
+		{"en", "1.234", 1.234},
+		{"de", "1.234", 1234.0},
+		{"de", "1,234", 1.234},
+		{"fr", "1 234,5", 1234.5},
+		{"ch", "1'234.5", 1234.5},
+	}
+
+	for _, test := range tests {
+		parser, err := lookupParser("number("+test.locale+")", NewDefaultConfig())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		get, err := parser(test.rawVal)
+		if err != nil {
+			t.Errorf("number(%s) '%s': %s", test.locale, test.rawVal, err.Error())
+			continue
+		}
+
+		if get != test.want {
+			t.Errorf("number(%s) '%s': want %v, get %v", test.locale, test.rawVal, test.want, get)
+		}
+	}
+}
+
+func TestNumberParserErrors(t *testing.T) {
+	tests := [][2]string{
+		{"auto", "1,234"},
+		{"auto", "1.234"},
+		{"auto", "1,23,4"},
+		{"auto", "12a"},
+		{"auto", "--3"},
+		{"auto", "1.2.3,4,5"},
+		{"auto", "12kk"},
+		{"en", "1.234.5"},
+		{"en", "1'234"},
+		{"de", "1,234.5"},
+	}
+
+	for _, test := range tests {
+		locale, err := lookupNumberLocale(test[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if val, err := NumberParser(locale)(test[1]); err == nil {
+			t.Errorf("number(%s) '%s' should return error, get %v", test[0], test[1], val)
+		}
+	}
+
+	if _, err := lookupParser("number(xx)", NewDefaultConfig()); err == nil {
+		t.Errorf("Unknown locale should return error")
+	}
+}
+
+func TestFloatParser(t *testing.T) {
+	tests := map[string]float64{
+		"0.981":  0.981,
+		" -3 ":   -3,
+		"1.234":  1.234,
+		"74.5%":  74.5,
+		"$250":   250,
+		"1234.5": 1234.5,
+	}
+
+	for rawVal, want := range tests {
+		get, err := FloatParser(rawVal)
+		if err != nil {
+			t.Errorf("'%s': %s", rawVal, err.Error())
+			continue
+		}
+
+		if get != want {
+			t.Errorf("'%s': want %v, get %v", rawVal, want, get)
+		}
+	}
+
+	// Digit grouping is ambiguous without a locale, it is left to the number parser
+	for _, rawVal := range []string{"1,234", "1.234,5", "1,234.5", "1.234.5", "0,5"} {
+		if val, err := FloatParser(rawVal); err == nil {
+			t.Errorf("'%s' should return error, get %v", rawVal, val)
+		}
+	}
+}
+
+func TestPercentParser(t *testing.T) {
+	tests := map[string]any{
+		"23%":   0.23,
+		"23":    0.23,
+		"-7.5%": -0.075,
+		"100 %": 1.0,
+		"-":     nil,
+	}
+
+	for rawVal, want := range tests {
+		get, err := PercentParser(NumberLocale{})(rawVal)
+		if err != nil {
+			t.Errorf("'%s': %s", rawVal, err.Error())
+			continue
+		}
+
+		if get != want {
+			t.Errorf("'%s': want %v, get %v", rawVal, want, get)
+		}
+	}
+}
+
+const numberTestContent = `
+<div>
+	<span class="bank">12.3k</span>
+	<span class="kast">76%</span>
+	<span class="adr">-</span>
+	<span class="diff">-3</span>
+	<span class="fk">4</span>
+</div>`
+
+func TestNumberParserFields(t *testing.T) {
+	var stat struct {
+		Bank int      `selector:"span.bank" parser:"number(en)"`
+		Kast *float64 `selector:"span.kast" parser:"percent"`
+		Adr  *float64 `selector:"span.adr"  parser:"number"`
+		Diff int      `selector:"span.diff" parser:"int"`
+		Fk   *int     `selector:"span.fk"   parser:"number"`
+		Fd   *int     `selector:"span.fd"   parser:"number"`
+	}
+
+	if err := ParseFromString(&stat, numberTestContent); err != nil {
+		t.Fatal(err)
+	}
+
+	if stat.Bank != 12300 || stat.Kast == nil || *stat.Kast != 0.76 || stat.Adr != nil ||
+		stat.Diff != -3 || stat.Fk == nil || *stat.Fk != 4 || stat.Fd != nil {
+		t.Errorf("Wrong stat, get %+v", stat)
+	}
+
+	var ratio struct {
+		Kast int `selector:"span.kast" parser:"percent"`
+	}
+
+	if err := ParseFromString(&ratio, numberTestContent); err == nil {
+		t.Errorf("Setting 0.76 to an int field should return error")
+	}
+}
@@ -33,18 +33,23 @@ func IntParser(rawVal string) (any, error) {
 		return nil, err
 	}
 
+	if strings.HasPrefix(trimmedRawVal, "-") {
+		intVal = -intVal
+	}
+
 	return intVal, nil
 }
 
-// Return float value of the content
+// Return float value of the content, the dot is the only decimal separator and digit grouping is rejected.
+// Use [NumberParser] for numbers with digit grouping, signs or suffixes
 func FloatParser(rawVal string) (any, error) {
 	trimmedRawVal := strings.TrimSpace(rawVal)
-	if !regexp.MustCompile(`^-?[a-zA-Z$%]?\s*-?\d+(?:[,.]\d+)*(\.\d+)?\s*[a-zA-Z$%]?$`).
+	if !regexp.MustCompile(`^-?[a-zA-Z$%]?\s*-?\d+(\.\d+)?\s*[a-zA-Z$%]?$`).
 		MatchString(trimmedRawVal) {
 		return nil, fmt.Errorf("%s is not valid for parsing to float", trimmedRawVal)
 	}
 
-	floatStr := regexp.MustCompile(`-?\d+(?:[,.]\d+)*(\.\d+)?`).FindString(trimmedRawVal)
+	floatStr := regexp.MustCompile(`-?\d+(\.\d+)?`).FindString(trimmedRawVal)
 	floatVal, err := strconv.ParseFloat(floatStr, 64)
 	if err != nil {
 		return nil, err
@@ -79,3 +84,126 @@ func IfNullParser(defaultVal any, alternateParser Parser) Parser {
 		return alternateParser(rawVal)
 	}
 }
+
+// Return the content in lower case
+func LowerParser(rawVal string) (any, error) {
+	return strings.ToLower(rawVal), nil
+}
+
+// Return the content in upper case
+func UpperParser(rawVal string) (any, error) {
+	return strings.ToUpper(rawVal), nil
+}
+
+// Return the default value if the content is empty, if not the content is returned unchanged
+func DefaultParser(defaultVal string) Parser {
+	return func(rawVal string) (any, error) {
+		if strings.TrimSpace(rawVal) == "" {
+			return defaultVal, nil
+		}
+
+		return rawVal, nil
+	}
+}
+
+// Map the trimmed content to a value, content which is not in the map return an error
+func EnumParser(values map[string]string) Parser {
+	return func(rawVal string) (any, error) {
+		val, ok := values[strings.TrimSpace(rawVal)]
+		if !ok {
+			return nil, fmt.Errorf("%s is not a recognizable enum value", strings.TrimSpace(rawVal))
+		}
+
+		return val, nil
+	}
+}
+
+// Return the float value of the content multiplied by the factor
+func ScaleParser(factor float64) Parser {
+	return func(rawVal string) (any, error) {
+		floatVal, err := FloatParser(rawVal)
+		if err != nil {
+			return nil, err
+		}
+
+		return roundFloatError(floatVal.(float64) * factor), nil
+	}
+}
+
+// Split the content by the separator, blank elements are removed
+func SplitParser(sep string) Parser {
+	return func(rawVal string) (any, error) {
+		elements := []string{}
+
+		for _, element := range strings.Split(rawVal, sep) {
+			if strings.TrimSpace(element) == "" {
+				continue
+			}
+
+			elements = append(elements, element)
+		}
+
+		return elements, nil
+	}
+}
+
+// Return the trimmed content with the prefix removed
+func StripPrefixParser(prefix string) Parser {
+	return func(rawVal string) (any, error) {
+		return strings.TrimPrefix(strings.TrimSpace(rawVal), prefix), nil
+	}
+}
+
+// Return the trimmed content with the suffix removed
+func StripSuffixParser(suffix string) Parser {
+	return func(rawVal string) (any, error) {
+		return strings.TrimSuffix(strings.TrimSpace(rawVal), suffix), nil
+	}
+}
+
+// Return bool value of the content, accept the values of [strconv.ParseBool] and yes/no
+func BoolParser(rawVal string) (any, error) {
+	boolStr := strings.ToLower(strings.TrimSpace(rawVal))
+
+	switch boolStr {
+	case "yes", "y":
+		return true, nil
+	case "no", "n":
+		return false, nil
+	}
+
+	boolVal, err := strconv.ParseBool(boolStr)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not valid for parsing to bool", boolStr)
+	}
+
+	return boolVal, nil
+}
+
+// Return time.Duration value of the content, accept clock format (mm:ss, h:mm:ss) and Go duration format (1h2m3s)
+func DurationParser(rawVal string) (any, error) {
+	durationStr := strings.TrimSpace(rawVal)
+
+	if !regexp.MustCompile(`^[0-9]+(:[0-9]{2}){1,2}$`).MatchString(durationStr) {
+		duration, err := time.ParseDuration(durationStr)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not valid for parsing to duration", durationStr)
+		}
+
+		return duration, nil
+	}
+
+	parts := strings.Split(durationStr, ":")
+	var seconds int
+
+	for i, part := range parts {
+		val, _ := strconv.Atoi(part)
+		if i > 0 && val >= 60 {
+			return nil, fmt.Errorf("%s is not valid for parsing to duration", durationStr)
+		}
+
+		seconds = seconds*60 + val
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
@@ -0,0 +1,411 @@
+package htmlx
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ParserFactory build a parser from the arguments written inside the parentheses of the parser tag.
+// For example the factory registered as "enum" receive "a=x,b=y" for the tag `parser:"enum(a=x,b=y)"`
+type ParserFactory func(args string) (Parser, error)
+
+var registry = struct {
+	mu        sync.RWMutex
+	parsers   map[string]Parser
+	factories map[string]ParserFactory
+}{
+	parsers:   map[string]Parser{},
+	factories: map[string]ParserFactory{},
+}
+
+// Register a parser globally, it can then be used by name in the parser tag of every parse call.
+// Parsers set with [SetParsers] take precedence over the registered ones
+func RegisterParser(name string, parser Parser) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.parsers[name] = parser
+}
+
+// Register a parameterized parser globally, it can then be used as name(args) in the parser tag of every parse call
+func RegisterParserFactory(name string, factory ParserFactory) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.factories[name] = factory
+}
+
+// Return a copy of the globally registered parsers
+func RegisteredParsers() map[string]Parser {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return maps.Clone(registry.parsers)
+}
+
+var builtinParsers = map[string]Parser{
+	"string":   StringParser,
+	"trim":     StringParserClean,
+	"int":      IntParser,
+	"float":    FloatParser,
+	"bool":     BoolParser,
+	"duration": DurationParser,
+	"lower":    LowerParser,
+	"upper":    UpperParser,
+	"number":   NumberParser(NumberLocale{}),
+	"percent":  PercentParser(NumberLocale{}),
+}
+
+var builtinParserFactories = map[string]ParserFactory{
+	"default": func(args string) (Parser, error) {
+		return DefaultParser(args), nil
+	},
+	"enum": func(args string) (Parser, error) {
+		values := map[string]string{}
+		for _, pair := range strings.Split(args, ",") {
+			key, val, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, fmt.Errorf("enum value '%s' is not in the form key=value", pair)
+			}
+
+			values[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		}
+
+		return EnumParser(values), nil
+	},
+	"scale": func(args string) (Parser, error) {
+		factor, err := strconv.ParseFloat(strings.TrimSpace(args), 64)
+		if err != nil {
+			return nil, fmt.Errorf("scale factor '%s' is not a number", args)
+		}
+
+		return ScaleParser(factor), nil
+	},
+	"split": func(args string) (Parser, error) {
+		if args == "" {
+			return nil, fmt.Errorf("split separator is empty")
+		}
+
+		return SplitParser(args), nil
+	},
+	"stripPrefix": func(args string) (Parser, error) {
+		return StripPrefixParser(args), nil
+	},
+	"stripSuffix": func(args string) (Parser, error) {
+		return StripSuffixParser(args), nil
+	},
+	"date": func(args string) (Parser, error) {
+		return DateParser(args), nil
+	},
+	"number": func(args string) (Parser, error) {
+		locale, err := lookupNumberLocale(args)
+		if err != nil {
+			return nil, err
+		}
+
+		return NumberParser(locale), nil
+	},
+	"percent": func(args string) (Parser, error) {
+		locale, err := lookupNumberLocale(args)
+		if err != nil {
+			return nil, err
+		}
+
+		return PercentParser(locale), nil
+	},
+}
+
+type parserStage struct {
+	name   string
+	parser Parser
+}
+
+// Split the parser tag into stages on '|', ignoring the ones inside parentheses
+func splitPipeline(tag string) []string {
+	var stages []string
+	var depth, start int
+
+	for i, r := range tag {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case '|':
+			if depth == 0 {
+				stages = append(stages, strings.TrimSpace(tag[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	return append(stages, strings.TrimSpace(tag[start:]))
+}
+
+// Look up a single stage of the pipeline, in the order: config parsers, registered parsers, built-in parsers
+func lookupParser(stage string, config *Config) (Parser, error) {
+	matches := regexp.MustCompile(`^([a-zA-Z0-9_]+)\((.*)\)$`).FindStringSubmatch(stage)
+	if matches == nil {
+		if parser, ok := config.parsers[stage]; ok {
+			return parser, nil
+		}
+
+		registry.mu.RLock()
+		parser, ok := registry.parsers[stage]
+		registry.mu.RUnlock()
+		if ok {
+			return parser, nil
+		}
+
+		if stage == "date" {
+			return DateParser(config.dateFormat), nil
+		}
+
+		if parser, ok := builtinParsers[stage]; ok {
+			return parser, nil
+		}
+
+		return nil, fmt.Errorf("parser %s is not recognizable", stage)
+	}
+
+	name, args := matches[1], matches[2]
+
+	registry.mu.RLock()
+	factory, ok := registry.factories[name]
+	registry.mu.RUnlock()
+	if !ok {
+		if factory, ok = builtinParserFactories[name]; !ok {
+			return nil, fmt.Errorf("parser %s is not recognizable", name)
+		}
+	}
+
+	parser, err := factory(args)
+	if err != nil {
+		return nil, fmt.Errorf("Error building parser %s: %s", stage, err.Error())
+	}
+
+	return parser, nil
+}
+
+func buildPipeline(tag string, config *Config) ([]parserStage, error) {
+	var pipeline []parserStage
+
+	for _, stage := range splitPipeline(tag) {
+		if stage == "" {
+			return nil, fmt.Errorf("parser tag '%s' contains an empty stage", tag)
+		}
+
+		parser, err := lookupParser(stage, config)
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline = append(pipeline, parserStage{name: stage, parser: parser})
+	}
+
+	return pipeline, nil
+}
+
+// Convert the output of a stage back to a string so it can be fed to the next stage
+func stringify(val any) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+
+	rv := reflect.ValueOf(val)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+
+		return stringify(rv.Elem().Interface())
+	}
+
+	return fmt.Sprint(val)
+}
+
+// Run the pipeline on the raw value, a []string output is fanned out so that the remaining stages run on every element
+func runPipeline(pipeline []parserStage, rawVal string) (any, error) {
+	var val any = rawVal
+
+	for i, stage := range pipeline {
+		var err error
+
+		val, err = stage.parser(stringify(val))
+		if err != nil {
+			return nil, fmt.Errorf("parser '%s' error: %s", stage.name, err.Error())
+		}
+
+		if val == nil {
+			return nil, nil
+		}
+
+		elements, ok := val.([]string)
+		if !ok || i == len(pipeline)-1 {
+			continue
+		}
+
+		results := make([]any, len(elements))
+		for j, element := range elements {
+			if results[j], err = runPipeline(pipeline[i+1:], element); err != nil {
+				return nil, fmt.Errorf("element %d: %s", j, err.Error())
+			}
+		}
+
+		return results, nil
+	}
+
+	return val, nil
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Uintptr
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// Set the number to the numeric field, numbers out of the range of the field type return error
+func setNumber(fieldVal reflect.Value, numVal reflect.Value, parserName string) error {
+	outOfRange := fmt.Errorf("Parser %s returned %v which is out of the range of %s", parserName, numVal.Interface(), fieldVal.Type().String())
+	fieldKind := fieldVal.Kind()
+
+	switch {
+	case isFloatKind(numVal.Kind()):
+		f := numVal.Float()
+		switch {
+		case isFloatKind(fieldKind):
+			if fieldVal.OverflowFloat(f) {
+				return outOfRange
+			}
+			fieldVal.SetFloat(f)
+		case isUintKind(fieldKind):
+			if f < 0 || f >= 1<<64 || fieldVal.OverflowUint(uint64(f)) {
+				return outOfRange
+			}
+			fieldVal.SetUint(uint64(f))
+		default:
+			if f < math.MinInt64 || f >= 1<<63 || fieldVal.OverflowInt(int64(f)) {
+				return outOfRange
+			}
+			fieldVal.SetInt(int64(f))
+		}
+	case isUintKind(numVal.Kind()):
+		u := numVal.Uint()
+		switch {
+		case isFloatKind(fieldKind):
+			fieldVal.SetFloat(float64(u))
+		case isUintKind(fieldKind):
+			if fieldVal.OverflowUint(u) {
+				return outOfRange
+			}
+			fieldVal.SetUint(u)
+		default:
+			if u > math.MaxInt64 || fieldVal.OverflowInt(int64(u)) {
+				return outOfRange
+			}
+			fieldVal.SetInt(int64(u))
+		}
+	default:
+		i := numVal.Int()
+		switch {
+		case isFloatKind(fieldKind):
+			fieldVal.SetFloat(float64(i))
+		case isUintKind(fieldKind):
+			if i < 0 || fieldVal.OverflowUint(uint64(i)) {
+				return outOfRange
+			}
+			fieldVal.SetUint(uint64(i))
+		default:
+			if fieldVal.OverflowInt(i) {
+				return outOfRange
+			}
+			fieldVal.SetInt(i)
+		}
+	}
+
+	return nil
+}
+
+// Set the value returned by the pipeline to the field.
+// String values which are not assignable are parsed as if there was no parser,
+// numbers are converted between numeric kinds as long as no fractional part is lost and they are in range
+func assignParsedValue(fieldVal reflect.Value, val any, config *Config, parserName string) error {
+	if val == nil {
+		fieldVal.Set(reflect.Zero(fieldVal.Type()))
+		return nil
+	}
+
+	processedVal := reflect.ValueOf(val)
+	if !processedVal.IsValid() {
+		return fmt.Errorf("processed value using parser %s is invalid", parserName)
+	}
+
+	fieldType := fieldVal.Type()
+
+	switch {
+	case processedVal.Type().AssignableTo(fieldType):
+		fieldVal.Set(processedVal)
+		return nil
+	case fieldType.Kind() == reflect.Ptr && processedVal.Type().AssignableTo(fieldType.Elem()):
+		ptr := reflect.New(fieldType.Elem())
+		ptr.Elem().Set(processedVal)
+		fieldVal.Set(ptr)
+		return nil
+	case processedVal.Kind() == reflect.String:
+		if strings.TrimSpace(processedVal.String()) == "" {
+			return nil
+		}
+
+		return parseSupportedValues(fieldVal, processedVal.String(), config, HtmlxTags{})
+	case fieldType.Kind() == reflect.Slice && processedVal.Kind() == reflect.Slice:
+		slice := reflect.MakeSlice(fieldType, processedVal.Len(), processedVal.Len())
+		for i := range processedVal.Len() {
+			if err := assignParsedValue(slice.Index(i), processedVal.Index(i).Interface(), config, parserName); err != nil {
+				return fmt.Errorf("element %d: %s", i, err.Error())
+			}
+		}
+
+		fieldVal.Set(slice)
+		return nil
+	case isFloatKind(processedVal.Kind()) && isIntKind(fieldType.Kind()):
+		if f := processedVal.Float(); f != math.Trunc(f) {
+			return fmt.Errorf("Parser %s returned %v which can't be set to %s without losing precision", parserName, f, fieldType.String())
+		}
+
+		return setNumber(fieldVal, processedVal, parserName)
+	case (isIntKind(processedVal.Kind()) || isFloatKind(processedVal.Kind())) &&
+		(isIntKind(fieldType.Kind()) || isFloatKind(fieldType.Kind())):
+		return setNumber(fieldVal, processedVal, parserName)
+	case fieldType.Kind() == reflect.Ptr:
+		ptr := reflect.New(fieldType.Elem())
+		if err := assignParsedValue(ptr.Elem(), val, config, parserName); err != nil {
+			return err
+		}
+
+		fieldVal.Set(ptr)
+		return nil
+	}
+
+	return fmt.Errorf(
+		"Incompatible type when using parser %s, want %s, get %s",
+		parserName,
+		fieldType.String(),
+		processedVal.Type().String(),
+	)
+}
@@ -0,0 +1,191 @@
+package htmlx
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const pipelineTestContent = `
+<table>
+	<tr class="stats">
+		<td class="hs">23%</td>
+		<td class="bank">12.3k</td>
+		<td class="empty"></td>
+		<td class="buy">$$</td>
+		<td class="agents">Jett, Sova ,Omen</td>
+		<td class="scores">13,11, 7</td>
+		<td class="patch">Patch 10.04</td>
+		<td class="team"><a href="/team/624/paper-rex">PRX</a></td>
+	</tr>
+</table>`
+
+type PipelineTestInfo struct {
+	Hs      float64  `selector:"td.hs"     parser:"trim|stripSuffix(%)|float"`
+	Bank    int      `selector:"td.bank"   parser:"stripSuffix(k)|scale(1000)"`
+	Empty   int      `selector:"td.empty"  parser:"default(5)|int"`
+	EmptyP  *int     `selector:"td.empty"  parser:"default(0)"`
+	Buy     string   `selector:"td.buy"    parser:"enum(=eco,$=semi_eco,$$=semi_buy,$$$=full_buy)"`
+	Agents  []string `selector:"td.agents" parser:"split(,)|trim|lower"`
+	Scores  []int    `selector:"td.scores" parser:"split(,)"`
+	Patch   float64  `selector:"td.patch"  parser:"stripPrefix(Patch )"`
+	TeamId  int      `selector:"td.team a" parser:"teamIdParser"   source:"attr=href"`
+	TeamTag string   `selector:"td.team a" parser:"trim|shout"`
+}
+
+func TestHtmlxParserPipeline(t *testing.T) {
+	RegisterParser("teamIdParser", func(rawVal string) (any, error) {
+		return IntParser(strings.Split(rawVal, "/")[2])
+	})
+
+	info := PipelineTestInfo{}
+
+	if err := ParseFromString(&info, pipelineTestContent, SetParsers(map[string]Parser{
+		"shout": func(rawVal string) (any, error) {
+			return rawVal + "!", nil
+		},
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Hs != 23 {
+		t.Errorf("Wrong hs, want 23, get %v", info.Hs)
+	}
+
+	if info.Bank != 12300 {
+		t.Errorf("Wrong bank, want 12300, get %d", info.Bank)
+	}
+
+	if info.Empty != 5 {
+		t.Errorf("Wrong empty value, want 5, get %d", info.Empty)
+	}
+
+	if info.EmptyP == nil || *info.EmptyP != 0 {
+		t.Errorf("Empty pointer should point to 0, get %v", info.EmptyP)
+	}
+
+	if info.Buy != "semi_buy" {
+		t.Errorf("Wrong buy type, want 'semi_buy', get '%s'", info.Buy)
+	}
+
+	if !reflect.DeepEqual(info.Agents, []string{"jett", "sova", "omen"}) {
+		t.Errorf("Wrong agents, want [jett sova omen], get %v", info.Agents)
+	}
+
+	if !reflect.DeepEqual(info.Scores, []int{13, 11, 7}) {
+		t.Errorf("Wrong scores, want [13 11 7], get %v", info.Scores)
+	}
+
+	if info.Patch != 10.04 {
+		t.Errorf("Wrong patch, want 10.04, get %v", info.Patch)
+	}
+
+	if info.TeamId != 624 {
+		t.Errorf("Wrong team id, want 624, get %d", info.TeamId)
+	}
+
+	if info.TeamTag != "PRX!" {
+		t.Errorf("Wrong team tag, want 'PRX!', get '%s'", info.TeamTag)
+	}
+}
+
+func TestHtmlxParserPipelineErrors(t *testing.T) {
+	tests := map[string]any{
+		"unknown parser": &struct {
+			V int `selector:"td.hs" parser:"trim|nope"`
+		}{},
+		"unknown factory": &struct {
+			V int `selector:"td.hs" parser:"nope(1)"`
+		}{},
+		"empty stage": &struct {
+			V int `selector:"td.hs" parser:"trim||int"`
+		}{},
+		"bad scale": &struct {
+			V int `selector:"td.hs" parser:"scale(abc)"`
+		}{},
+		"unknown enum": &struct {
+			V string `selector:"td.hs" parser:"enum(a=x)"`
+		}{},
+		"lossy conversion": &struct {
+			V int `selector:"td.bank" parser:"stripSuffix(k)|float"`
+		}{},
+	}
+
+	for name, s := range tests {
+		if err := ParseFromString(s, pipelineTestContent); err == nil {
+			t.Errorf("%s: pipeline should return error", name)
+		}
+	}
+}
+
+func TestHtmlxParserPipelineOutOfRange(t *testing.T) {
+	parsers := SetParsers(map[string]Parser{
+		"big":       func(string) (any, error) { return 300, nil },
+		"negative":  func(string) (any, error) { return -1, nil },
+		"negativeF": func(string) (any, error) { return -2.0, nil },
+		"huge":      func(string) (any, error) { return 1e40, nil },
+		"hugeInt":   func(string) (any, error) { return 1e20, nil },
+		"maxUint":   func(string) (any, error) { return uint64(math.MaxUint64), nil },
+	})
+
+	tests := map[string]any{
+		"int8 overflow": &struct {
+			V int8 `selector:"td.hs" parser:"big"`
+		}{},
+		"uint8 overflow": &struct {
+			V uint8 `selector:"td.hs" parser:"big"`
+		}{},
+		"negative uint": &struct {
+			V uint `selector:"td.hs" parser:"negative"`
+		}{},
+		"negative float to uint": &struct {
+			V *uint16 `selector:"td.hs" parser:"negativeF"`
+		}{},
+		"float32 overflow": &struct {
+			V float32 `selector:"td.hs" parser:"huge"`
+		}{},
+		"float to int64 overflow": &struct {
+			V int64 `selector:"td.hs" parser:"hugeInt"`
+		}{},
+		"uint64 to int64 overflow": &struct {
+			V int64 `selector:"td.hs" parser:"maxUint"`
+		}{},
+	}
+
+	for name, s := range tests {
+		if err := ParseFromString(s, pipelineTestContent, parsers); err == nil {
+			t.Errorf("%s: pipeline should return error, get %+v", name, s)
+		}
+	}
+
+	var inRange struct {
+		Int8  int8    `selector:"td.hs" parser:"negative"`
+		Uint  uint    `selector:"td.hs" parser:"big"`
+		Float float32 `selector:"td.hs" parser:"hugeInt"`
+		Int   int64   `selector:"td.hs" parser:"negativeF"`
+	}
+
+	if err := ParseFromString(&inRange, pipelineTestContent, parsers); err != nil {
+		t.Fatal(err)
+	}
+
+	if inRange.Int8 != -1 || inRange.Uint != 300 || inRange.Float != 1e20 || inRange.Int != -2 {
+		t.Errorf("Wrong converted numbers, get %+v", inRange)
+	}
+}
+
+func TestSplitPipeline(t *testing.T) {
+	tests := map[string][]string{
+		"int":                        {"int"},
+		"trim | int":                 {"trim", "int"},
+		"enum(a=x|y,b=z)|upper":      {"enum(a=x|y,b=z)", "upper"},
+		"default(0)|scale(1000)|int": {"default(0)", "scale(1000)", "int"},
+	}
+
+	for tag, want := range tests {
+		if got := splitPipeline(tag); !reflect.DeepEqual(got, want) {
+			t.Errorf("Wrong stages for '%s', want %v, get %v", tag, want, got)
+		}
+	}
+}
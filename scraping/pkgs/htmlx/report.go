@@ -0,0 +1,79 @@
+package htmlx
+
+import "sync"
+
+// SelectorMatch record which selector of a field's fallback list matched the document
+type SelectorMatch struct {
+	// Dot separated path of the field, e.g "TeamInfo.Team1Name"
+	Field string
+	// Index of the matched selector in the fallback list, -1 if none matched
+	Index int
+	// The matched selector, empty if none matched
+	Selector string
+	// All selectors of the field in order
+	Selectors []string
+}
+
+// IsFallback report whether the primary selector failed but a fallback one matched
+func (m SelectorMatch) IsFallback() bool {
+	return m.Index > 0
+}
+
+// SelectorReport collect the selector matches of every field parsed with [SetSelectorReport].
+// It is safe for concurrent usage, so one report can be shared by many parse calls.
+type SelectorReport struct {
+	mu sync.Mutex
+
+	matches []SelectorMatch
+}
+
+// NewSelectorReport return an empty report
+func NewSelectorReport() *SelectorReport {
+	return &SelectorReport{}
+}
+
+func (r *SelectorReport) add(field string, selectors []string, index int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := SelectorMatch{Field: field, Index: index, Selectors: selectors}
+	if index >= 0 && index < len(selectors) {
+		match.Selector = selectors[index]
+	}
+
+	r.matches = append(r.matches, match)
+}
+
+// Matches return all the recorded selector matches
+func (r *SelectorReport) Matches() []SelectorMatch {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]SelectorMatch{}, r.matches...)
+}
+
+// Fallbacks return the matches where the primary selector has gone stale and a fallback one was used
+func (r *SelectorReport) Fallbacks() []SelectorMatch {
+	var fallbacks []SelectorMatch
+
+	for _, match := range r.Matches() {
+		if match.IsFallback() {
+			fallbacks = append(fallbacks, match)
+		}
+	}
+
+	return fallbacks
+}
+
+// Misses return the matches where no selector matched any element
+func (r *SelectorReport) Misses() []SelectorMatch {
+	var misses []SelectorMatch
+
+	for _, match := range r.Matches() {
+		if match.Index < 0 {
+			misses = append(misses, match)
+		}
+	}
+
+	return misses
+}
@@ -0,0 +1,87 @@
+package htmlx
+
+import (
+	"strings"
+	"testing"
+)
+
+const fallbackTestContent = `
+<div class="match-header">
+	<a class="match-header-link mod-1" href="/team/624/paper-rex">Paper Rex</a>
+	<div class="match-header-vs-score"><span>3</span><span>:</span><span>1</span></div>
+	<div class="match-header-note"></div>
+</div>`
+
+type FallbackTestInfo struct {
+	Team1Name  string `selector:"div.match-header-vs > a.mod-1 || a.match-header-link.mod-1"  required:"true"`
+	Team1Score int    `selector:"div.match-header-vs-score > span:nth-child(1) || span.winner"`
+	Note       string `selector:"div.match-header-note"                                         default:"no veto"`
+	Rating     int    `selector:"div.match-header-link-name-elo"                                default:"0"`
+	Missing    *int   `selector:"div.missing"                                                   required:"false"`
+}
+
+func TestHtmlxFallbackSelectors(t *testing.T) {
+	info := FallbackTestInfo{}
+	report := NewSelectorReport()
+
+	if err := ParseFromString(&info, fallbackTestContent, SetNoEmptySelection(true), SetSelectorReport(report)); err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Team1Name != "Paper Rex" {
+		t.Errorf("Wrong team 1 name, want 'Paper Rex', get '%s'", info.Team1Name)
+	}
+
+	if info.Team1Score != 3 {
+		t.Errorf("Wrong team 1 score, want 3, get %d", info.Team1Score)
+	}
+
+	if info.Note != "no veto" {
+		t.Errorf("Wrong note, want 'no veto', get '%s'", info.Note)
+	}
+
+	if info.Missing != nil {
+		t.Errorf("Missing should be nil, get %d", *info.Missing)
+	}
+
+	fallbacks := report.Fallbacks()
+	if len(fallbacks) != 1 {
+		t.Fatalf("Wrong number of fallbacks, want 1, get %d", len(fallbacks))
+	}
+
+	if fallbacks[0].Field != "Team1Name" || fallbacks[0].Index != 1 || fallbacks[0].Selector != "a.match-header-link.mod-1" {
+		t.Errorf("Wrong fallback match: %+v", fallbacks[0])
+	}
+
+	misses := report.Misses()
+	if len(misses) != 2 {
+		t.Fatalf("Wrong number of misses, want 2, get %d", len(misses))
+	}
+
+	if misses[0].Field != "Rating" || misses[1].Field != "Missing" {
+		t.Errorf("Wrong misses: %+v", misses)
+	}
+}
+
+func TestHtmlxRequiredSelector(t *testing.T) {
+	info := struct {
+		Team2Name string `selector:"a.mod-2 || a.match-header-link.mod-2" required:"true"`
+	}{}
+
+	err := ParseFromString(&info, fallbackTestContent)
+	if err == nil {
+		t.Fatalf("Missing required field should return error")
+	}
+
+	if !strings.Contains(err.Error(), "Team2Name") {
+		t.Errorf("Error should mention the field, get '%s'", err.Error())
+	}
+
+	invalid := struct {
+		Team1Name string `selector:"a.mod-1" required:"maybe"`
+	}{}
+
+	if err := ParseFromString(&invalid, fallbackTestContent, SetParseAllFields(true)); err == nil {
+		t.Errorf("Invalid required tag should return error")
+	}
+}
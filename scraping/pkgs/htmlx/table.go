@@ -0,0 +1,285 @@
+package htmlx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var selectionType = reflect.TypeOf(&goquery.Selection{})
+
+// TableHeader hold the names of the columns of a table, a column is named by both its text and its title attribute
+type TableHeader struct {
+	columns [][]string
+}
+
+// Lower case the header name and collapse its whitespaces so that "HS %" and "hs  %" are the same column
+func normalizeHeaderName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Read the header of the table, which is the first row of its thead or its first row if it has none
+func ReadTableHeader(table *goquery.Selection) TableHeader {
+	var header TableHeader
+
+	tableHeaderRow(tableOf(table)).ChildrenFiltered("th, td").Each(func(_ int, cell *goquery.Selection) {
+		names := []string{normalizeHeaderName(cell.Text())}
+		if title, ok := cell.Attr("title"); ok {
+			names = append(names, normalizeHeaderName(title))
+		}
+
+		header.columns = append(header.columns, names)
+	})
+
+	return header
+}
+
+// Return the index of the first column named after any of the aliases, in the order of the aliases, -1 if none is found
+func (h TableHeader) Index(aliases ...string) int {
+	for _, alias := range aliases {
+		alias = normalizeHeaderName(alias)
+
+		for i, names := range h.columns {
+			for _, name := range names {
+				if name == alias {
+					return i
+				}
+			}
+		}
+	}
+
+	return -1
+}
+
+// Return the number of columns of the header
+func (h TableHeader) Len() int {
+	return len(h.columns)
+}
+
+// Return the table of the selection, which can be the table itself, an element inside it or an element wrapping it
+func tableOf(sel *goquery.Selection) *goquery.Selection {
+	if table := sel.Closest("table"); table.Length() > 0 {
+		return table.First()
+	}
+
+	return sel.Find("table").First()
+}
+
+// Return the rows belonging to the table and not to a nested one
+func tableRows(table *goquery.Selection) *goquery.Selection {
+	return table.Find("tr").FilterFunction(func(_ int, row *goquery.Selection) bool {
+		return row.Closest("table").IsSelection(table)
+	})
+}
+
+func tableHeaderRow(table *goquery.Selection) *goquery.Selection {
+	rows := tableRows(table)
+
+	if headerRow := rows.FilterFunction(func(_ int, row *goquery.Selection) bool {
+		return row.Parent().Is("thead")
+	}); headerRow.Length() > 0 {
+		return headerRow.First()
+	}
+
+	return rows.First()
+}
+
+// Return the rows of the table except the header row
+func TableBodyRows(table *goquery.Selection) *goquery.Selection {
+	table = tableOf(table)
+	headerRow := tableHeaderRow(table)
+
+	return tableRows(table).FilterFunction(func(_ int, row *goquery.Selection) bool {
+		return !row.IsSelection(headerRow) && !row.Parent().Is("thead")
+	})
+}
+
+// The cell of a matrix table being parsed with the header cells of its row and its column
+type matrixCell struct {
+	rowHeader    *goquery.Selection
+	columnHeader *goquery.Selection
+	cell         *goquery.Selection
+}
+
+// Return the selection the selectors of the field are relative to:
+//   - matrix=rowHeader, matrix=columnHeader, matrix=cell: the header cells or the cell of the matrix being parsed
+//   - column=NAME: the cell of the row under the column NAME, an empty selection if there is no such column
+//   - otherwise: the selection itself
+func fieldRoot(sel *goquery.Selection, htmlxTags HtmlxTags, config *Config) (*goquery.Selection, error) {
+	if htmlxTags.matrix != "" {
+		if config.matrixCell == nil {
+			return nil, fmt.Errorf("matrix tag %s is only supported by ParseMatrix", htmlxTags.matrix)
+		}
+
+		switch htmlxTags.matrix {
+		case "rowHeader":
+			return config.matrixCell.rowHeader, nil
+		case "columnHeader":
+			return config.matrixCell.columnHeader, nil
+		default:
+			return config.matrixCell.cell, nil
+		}
+	}
+
+	if len(htmlxTags.columns) == 0 {
+		return sel, nil
+	}
+
+	row := sel.First()
+	if !row.Is("tr") {
+		row = TableBodyRows(sel).First()
+	}
+
+	header := config.tableHeader
+	if header == nil {
+		if table := row.Closest("table"); table.Length() > 0 {
+			tableHeader := ReadTableHeader(table)
+			header = &tableHeader
+		} else {
+			return nil, fmt.Errorf("the row is detached from its table, the header must be set with SetTableHeader")
+		}
+	}
+
+	idx := header.Index(htmlxTags.columns...)
+	if idx < 0 {
+		return sel.FilterFunction(func(int, *goquery.Selection) bool { return false }), nil
+	}
+
+	return row.ChildrenFiltered("th, td").Eq(idx), nil
+}
+
+// Check that the rows is a pointer to a slice of structs or of pointers to structs and return the slice and element type
+func sliceOfStructs(rows any) (reflect.Value, reflect.Type, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return v, nil, fmt.Errorf("rows must be a pointer to a slice of structs")
+	}
+
+	elemType := v.Elem().Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct {
+		return v, nil, fmt.Errorf("rows must be a pointer to a slice of structs")
+	}
+
+	return v.Elem(), elemType, nil
+}
+
+// Create a new element of the slice, returning the element and the struct to parse into
+func newSliceElement(elemType reflect.Type) (reflect.Value, reflect.Value) {
+	if elemType.Kind() == reflect.Ptr {
+		ptr := reflect.New(elemType.Elem())
+		return ptr, ptr.Elem()
+	}
+
+	elem := reflect.New(elemType).Elem()
+	return elem, elem
+}
+
+// Parse every body row of the table into an element of the slice pointed by rows.
+// Fields with a column tag, e.g `column:"K||Kills"`, are parsed from the cell under the first column named after
+// any of the aliases, matched against the text or the title of the header cells case insensitively.
+// Their selector is optional and relative to the cell, other fields are parsed relative to the row
+func ParseTable(rows any, table *goquery.Selection, opts ...Option) error {
+	return ParseTableWithContext(context.Background(), rows, table, opts...)
+}
+
+// Parse the table like [ParseTable] with context, the context is checked between every row and every field
+func ParseTableWithContext(ctx context.Context, rows any, table *goquery.Selection, opts ...Option) error {
+	slice, elemType, err := sliceOfStructs(rows)
+	if err != nil {
+		return err
+	}
+
+	config := NewDefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	table = tableOf(table)
+	header := ReadTableHeader(table)
+	config.tableHeader = &header
+
+	bodyRows := TableBodyRows(table)
+	for i := range bodyRows.Length() {
+		if err = ctx.Err(); err != nil {
+			return fmt.Errorf("Parsing cancelled at row %d: %w", i, err)
+		}
+
+		elem, s := newSliceElement(elemType)
+
+		if err = parseFromReflectValue(ctx, s, bodyRows.Eq(i), config, fmt.Sprintf("[%d]", i)); err != nil {
+			if ctx.Err() != nil {
+				// Cancellation errors already carry the full field path
+				return err
+			}
+
+			return fmt.Errorf("Error parsing row %d: %s", i, err.Error())
+		}
+
+		slice.Set(reflect.Append(slice, elem))
+	}
+
+	return nil
+}
+
+// Parse every cell of a table with both row and column headers into an element of the slice pointed by cells,
+// in row major order. The first row hold the column headers and the first cell of every other row is its row header.
+// Fields with the tag matrix=rowHeader or matrix=columnHeader are parsed from the header cells of the cell,
+// matrix=cell from the cell itself, their selector is optional. Other fields are parsed relative to the cell
+func ParseMatrix(cells any, table *goquery.Selection, opts ...Option) error {
+	return ParseMatrixWithContext(context.Background(), cells, table, opts...)
+}
+
+// Parse the table like [ParseMatrix] with context, the context is checked between every cell and every field
+func ParseMatrixWithContext(ctx context.Context, cells any, table *goquery.Selection, opts ...Option) error {
+	slice, elemType, err := sliceOfStructs(cells)
+	if err != nil {
+		return err
+	}
+
+	config := NewDefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	table = tableOf(table)
+	columnHeaders := tableHeaderRow(table).ChildrenFiltered("th, td")
+	bodyRows := TableBodyRows(table)
+
+	for i := range bodyRows.Length() {
+		rowCells := bodyRows.Eq(i).ChildrenFiltered("th, td")
+
+		for j := 1; j < rowCells.Length(); j++ {
+			if err = ctx.Err(); err != nil {
+				return fmt.Errorf("Parsing cancelled at cell [%d][%d]: %w", i, j-1, err)
+			}
+
+			elem, s := newSliceElement(elemType)
+
+			config.matrixCell = &matrixCell{
+				rowHeader:    rowCells.First(),
+				columnHeader: columnHeaders.Eq(j),
+				cell:         rowCells.Eq(j),
+			}
+
+			if err = parseFromReflectValue(ctx, s, rowCells.Eq(j), config, fmt.Sprintf("[%d][%d]", i, j-1)); err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+
+				return fmt.Errorf("Error parsing cell [%d][%d]: %s", i, j-1, err.Error())
+			}
+
+			slice.Set(reflect.Append(slice, elem))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,316 @@
+package htmlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const tableTestContent = `
+<table class="overview">
+	<thead>
+		<tr>
+			<th></th>
+			<th title="Rating 2.0">R2.0</th>
+			<th title="Average Combat Score">ACS</th>
+			<th>K</th>
+			<th>HS%</th>
+		</tr>
+	</thead>
+	<tbody>
+		<tr>
+			<td class="mod-player"><a href="/player/9/tenz">TenZ</a></td>
+			<td><span>1.31</span></td>
+			<td><span>251</span></td>
+			<td><span>22</span></td>
+			<td><span>28%</span></td>
+		</tr>
+		<tr>
+			<td class="mod-player"><a href="/player/10/zekken">zekken</a></td>
+			<td><span>0.98</span></td>
+			<td><span>190</span></td>
+			<td><span>15</span></td>
+			<td><span></span></td>
+		</tr>
+	</tbody>
+</table>`
+
+// The same table with a column inserted and the columns reordered
+const shiftedTableTestContent = `
+<table class="overview">
+	<tr>
+		<td></td>
+		<td>K</td>
+		<td>Agent</td>
+		<td>Rating</td>
+		<td>acs</td>
+	</tr>
+	<tr>
+		<td class="mod-player"><a href="/player/9/tenz">TenZ</a></td>
+		<td><span>22</span></td>
+		<td><span>Jett</span></td>
+		<td><span>1.31</span></td>
+		<td><span>251</span></td>
+	</tr>
+</table>`
+
+const matrixTestContent = `
+<table class="mod-matrix">
+	<tbody>
+		<tr>
+			<td></td>
+			<td><div class="team"><div>Boaster<div class="team-tag">FNC</div></div></div></td>
+			<td><div class="team"><div>Chronicle<div class="team-tag">FNC</div></div></div></td>
+		</tr>
+		<tr>
+			<td><div class="team"><div>TenZ<div class="team-tag">SEN</div></div></div></td>
+			<td><div><div>3</div><div>2</div></div></td>
+			<td><div><div>1</div><div></div></div></td>
+		</tr>
+		<tr>
+			<td><div class="team"><div>zekken<div class="team-tag">SEN</div></div></div></td>
+			<td><div><div>0</div><div>4</div></div></td>
+			<td><div><div>2</div><div>2</div></div></td>
+		</tr>
+	</tbody>
+</table>`
+
+type TableTestRow struct {
+	Name   string   `selector:"td.mod-player > a"`
+	Rating float64  `column:"Rating||R2.0"      selector:"span"`
+	Acs    int      `column:"ACS"               selector:"span"`
+	Kills  int      `column:"K||Kills"          selector:"span"`
+	Hs     *float64 `column:"HS%"               selector:"span" parser:"stripSuffix(%)"`
+	Agent  string   `column:"Agent"                                     source:"text"`
+}
+
+type MatrixTestCell struct {
+	Team1Player string             `matrix:"rowHeader"    selector:"div.team > div"`
+	Team2Player string             `matrix:"columnHeader" selector:"div.team > div"`
+	Kills       int                `selector:"div > div:nth-child(1)" parser:"default(0)|int"`
+	Deaths      int                `selector:"div > div:nth-child(2)" parser:"default(0)|int"`
+	Cell        *goquery.Selection `matrix:"cell"`
+}
+
+func newTestTable(t *testing.T, content string) *goquery.Selection {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return doc.Find("table")
+}
+
+func TestParseTable(t *testing.T) {
+	var rows []TableTestRow
+
+	if err := ParseTable(&rows, newTestTable(t, tableTestContent)); err != nil {
+		t.Fatal(err)
+	}
+
+	hs := 28.0
+	want := []TableTestRow{
+		{Name: "TenZ", Rating: 1.31, Acs: 251, Kills: 22, Hs: &hs},
+		{Name: "zekken", Rating: 0.98, Acs: 190, Kills: 15},
+	}
+
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Wrong rows\nwant %+v\nget  %+v", want, rows)
+	}
+}
+
+func TestParseTableShiftedColumns(t *testing.T) {
+	var rows []*TableTestRow
+
+	if err := ParseTable(&rows, newTestTable(t, shiftedTableTestContent)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*TableTestRow{{Name: "TenZ", Rating: 1.31, Acs: 251, Kills: 22, Agent: "Jett"}}
+
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Wrong rows\nwant %+v\nget  %+v", want[0], rows[0])
+	}
+}
+
+func TestParseTableRow(t *testing.T) {
+	table := newTestTable(t, tableTestContent)
+	row := TableBodyRows(table).Eq(1)
+
+	var parsedRow TableTestRow
+	if err := ParseFromSelection(&parsedRow, row); err != nil {
+		t.Fatal(err)
+	}
+
+	if parsedRow.Name != "zekken" || parsedRow.Kills != 15 {
+		t.Errorf("Wrong row, get %+v", parsedRow)
+	}
+
+	// A cloned row is detached from the table so the header must be passed along
+	if err := ParseFromSelection(&parsedRow, row.Clone()); err == nil {
+		t.Errorf("Parsing a detached row without header should return error")
+	}
+
+	if err := ParseFromSelection(&parsedRow, row.Clone(), SetTableHeader(ReadTableHeader(table))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseTableMissingColumn(t *testing.T) {
+	var rows []struct {
+		Adr int `column:"ADR" required:"true"`
+	}
+
+	if err := ParseTable(&rows, newTestTable(t, tableTestContent)); err == nil {
+		t.Errorf("Missing required column should return error")
+	}
+
+	var optionalRows []struct {
+		Adr int `column:"ADR" default:"7"`
+	}
+
+	if err := ParseTable(&optionalRows, newTestTable(t, tableTestContent)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(optionalRows) != 2 || optionalRows[0].Adr != 7 {
+		t.Errorf("Missing optional column should use default value, get %+v", optionalRows)
+	}
+}
+
+func TestParseMatrix(t *testing.T) {
+	var cells []MatrixTestCell
+
+	if err := ParseMatrix(&cells, newTestTable(t, matrixTestContent)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][4]any{
+		{"TenZ", "Boaster", 3, 2},
+		{"TenZ", "Chronicle", 1, 0},
+		{"zekken", "Boaster", 0, 4},
+		{"zekken", "Chronicle", 2, 2},
+	}
+
+	if len(cells) != len(want) {
+		t.Fatalf("Wrong number of cells, want %d, get %d", len(want), len(cells))
+	}
+
+	for i, cell := range cells {
+		if get := [4]any{cell.Team1Player, cell.Team2Player, cell.Kills, cell.Deaths}; get != want[i] {
+			t.Errorf("Wrong cell %d, want %v, get %v", i, want[i], get)
+		}
+
+		if cell.Cell == nil || cell.Cell.Length() != 1 || !cell.Cell.Is("td") {
+			t.Errorf("Cell %d should hold its td", i)
+		}
+	}
+}
+
+func TestReadTableHeader(t *testing.T) {
+	header := ReadTableHeader(newTestTable(t, tableTestContent))
+
+	tests := map[string]int{
+		"r2.0":                 1,
+		"Average Combat Score": 2,
+		"hs %":                 -1,
+		"HS%":                  4,
+		"ADR":                  -1,
+	}
+
+	for alias, want := range tests {
+		if get := header.Index(alias); get != want {
+			t.Errorf("Wrong index for '%s', want %d, get %d", alias, want, get)
+		}
+	}
+}
+
+func TestMarshalTableRoundTrip(t *testing.T) {
+	hs := 28.0
+	row := TableTestRow{Name: "TenZ", Rating: 1.31, Acs: 251, Kills: 22, Hs: &hs, Agent: "Jett"}
+
+	content, err := Marshal(row, SetFormatters(map[string]Formatter{
+		"stripSuffix(%)": func(val any) (string, error) {
+			return fmt.Sprintf("%v%%", val), nil
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parsedRow TableTestRow
+	if err = ParseFromString(&parsedRow, content); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(row, parsedRow) {
+		t.Errorf("Round trip changed the row\nwant %+v\nget  %+v\nhtml %s", row, parsedRow, content)
+	}
+}
+
+func TestParseTableContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The last field cancel the context, the first row is parsed and the second is not
+	rows := []struct {
+		Name  string `selector:"td.mod-player > a"`
+		Kills int    `column:"K" selector:"span" parser:"cancelParser"`
+	}{}
+
+	parsers := map[string]Parser{
+		"cancelParser": func(rawVal string) (any, error) {
+			cancel()
+			return strconv.Atoi(rawVal)
+		},
+	}
+
+	err := ParseTableWithContext(ctx, &rows, newTestTable(t, tableTestContent), SetParsers(parsers))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Error should wrap context.Canceled, get %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "row 1") {
+		t.Errorf("Error should contain the row 1, get '%s'", err.Error())
+	}
+
+	if len(rows) != 1 || rows[0].Name != "TenZ" || rows[0].Kills != 22 {
+		t.Errorf("Only the first row should be parsed, get %+v", rows)
+	}
+}
+
+func TestParseMatrixContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cells := []struct {
+		Team1Player string `matrix:"rowHeader" selector:"div.team > div"`
+		Kills       int    `selector:"div > div:nth-child(1)" parser:"cancelParser"`
+	}{}
+
+	parsers := map[string]Parser{
+		"cancelParser": func(rawVal string) (any, error) {
+			cancel()
+			return strconv.Atoi(rawVal)
+		},
+	}
+
+	err := ParseMatrixWithContext(ctx, &cells, newTestTable(t, matrixTestContent), SetParsers(parsers))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Error should wrap context.Canceled, get %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "cell [0][1]") {
+		t.Errorf("Error should contain the cell [0][1], get '%s'", err.Error())
+	}
+
+	if len(cells) != 1 || cells[0].Team1Player != "TenZ" || cells[0].Kills != 3 {
+		t.Errorf("Only the first cell should be parsed, get %+v", cells)
+	}
+}
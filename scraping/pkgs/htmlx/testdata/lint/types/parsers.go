@@ -0,0 +1,8 @@
+package sample
+
+import "github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
+
+var parsers = map[string]htmlx.Parser{
+	"stageParser": htmlx.StringParser,
+	"oldParser":   htmlx.StringParser,
+}
@@ -0,0 +1,31 @@
+package sample
+
+import "time"
+
+type Score struct {
+	Team1 int `selector:"div.score > span.team-1"`
+	Team2 int `selector:"div.score > span.team-2"`
+}
+
+type Match struct {
+	Id      int       `selector:"div.match"                       source:"attr=data-id"`
+	Date    time.Time `selector:"div.date"                        source:"attr=data-utc-ts"`
+	Event   string    `selector:"a.event-link || a.event"`
+	Note    *string   `selector:"div.note"`
+	Winner  int       `selector:"div.winner"`
+	Patch   float64   `selector:"div.patch"                       parser:"stripPrefix(Patch )"`
+	Stage   string    `selector:"div.stage"                       parser:"stageParser"`
+	Vetoes  string    `selector:"div.veto"                        parser:"vetoParser"`
+	Players string    `selector:"span.player"`
+	Score
+}
+
+type PlayerRow struct {
+	Name  string `selector:"td.mod-player"`
+	Kills int    `column:"K"`
+	Adr   *int   `column:"ADR"`
+}
+
+type notTagged struct {
+	Value int
+}
@@ -0,0 +1,197 @@
+package htmlx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// FieldTrace explain how the value of a field was parsed
+type FieldTrace struct {
+	// Dot separated path of the field, e.g "TeamInfo.Team1Name"
+	Field string `json:"field"`
+	// All selectors of the field in order
+	Selectors []string `json:"selectors,omitempty"`
+	// The matched selector, empty if none matched
+	Selector string `json:"selector,omitempty"`
+	// Number of elements matched by the selector
+	Matches int    `json:"matches"`
+	Source  string `json:"source,omitempty"`
+	// The raw string passed to the parser, after the regex and the default value are applied
+	Raw string `json:"raw"`
+	// Whether the raw string was empty and the default tag was used instead
+	DefaultUsed bool   `json:"defaultUsed,omitempty"`
+	Parser      string `json:"parser,omitempty"`
+	// The value of the field after parsing, pointers are dereferenced
+	Result any `json:"result"`
+	// Whether the field was left untouched, SkipReason tell why
+	Skipped    bool   `json:"skipped"`
+	SkipReason string `json:"skipReason,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func (f *FieldTrace) setMatch(htmlElement *goquery.Selection, selectors []string, index int) {
+	if f == nil {
+		return
+	}
+
+	f.Matches = htmlElement.Length()
+	if index >= 0 && index < len(selectors) {
+		f.Selector = selectors[index]
+	}
+}
+
+func (f *FieldTrace) setRaw(rawVal string, defaultUsed bool) {
+	if f == nil {
+		return
+	}
+
+	f.Raw = rawVal
+	f.DefaultUsed = defaultUsed
+}
+
+// Mark the field as skipped, the first reason is kept since it is the closest to the cause
+func (f *FieldTrace) skip(reason string) {
+	if f == nil || reason == "" || f.Skipped {
+		return
+	}
+
+	f.Skipped = true
+	f.SkipReason = reason
+}
+
+func (f *FieldTrace) setResult(fieldVal reflect.Value) {
+	if f == nil || !fieldVal.IsValid() {
+		return
+	}
+
+	if fieldVal.Type() == selectionType {
+		f.Result = fmt.Sprintf("%d elements", f.Matches)
+		return
+	}
+
+	if fieldVal.Kind() == reflect.Ptr {
+		if fieldVal.IsNil() {
+			return
+		}
+
+		fieldVal = fieldVal.Elem()
+	}
+
+	f.Result = fieldVal.Interface()
+}
+
+// DisplaySelector return the matched selector, or the first selector if none matched
+func (f FieldTrace) DisplaySelector() string {
+	if f.Selector == "" && len(f.Selectors) > 0 {
+		return f.Selectors[0]
+	}
+
+	return f.Selector
+}
+
+// DisplayRaw return the raw string shortened to 40 runes, marked if it is the default value
+func (f FieldTrace) DisplayRaw() string {
+	raw := truncate(f.Raw, 40)
+	if f.DefaultUsed {
+		raw += " (default)"
+	}
+
+	return raw
+}
+
+// DisplayResult return the result formatted with %v, or the error of the field
+func (f FieldTrace) DisplayResult() string {
+	if f.Error != "" {
+		return "error: " + f.Error
+	}
+
+	if f.Result == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", f.Result)
+}
+
+// Trace collect the field traces of every parse call made with [SetTrace].
+// It is safe for concurrent usage, so one trace can be shared by many parse calls.
+type Trace struct {
+	mu sync.Mutex
+
+	fields []FieldTrace
+}
+
+// NewTrace return an empty trace
+func NewTrace() *Trace {
+	return &Trace{}
+}
+
+func (t *Trace) add(field FieldTrace) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.fields = append(t.fields, field)
+}
+
+// Fields return all the recorded field traces in parsing order
+func (t *Trace) Fields() []FieldTrace {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]FieldTrace{}, t.fields...)
+}
+
+// Skipped return the traces of the fields which were left untouched
+func (t *Trace) Skipped() []FieldTrace {
+	var skipped []FieldTrace
+
+	for _, field := range t.Fields() {
+		if field.Skipped {
+			skipped = append(skipped, field)
+		}
+	}
+
+	return skipped
+}
+
+// Shorten the string to at most n runes so that long raw html doesn't blow up the table
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n-3]) + "..."
+	}
+
+	return s
+}
+
+// String return the trace as tab aligned columns, one line per field
+func (t *Trace) String() string {
+	var buf strings.Builder
+
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tSELECTOR\tMATCHES\tSOURCE\tRAW\tPARSER\tRESULT\tSKIPPED")
+
+	for _, field := range t.Fields() {
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			field.Field,
+			truncate(field.DisplaySelector(), 60),
+			field.Matches,
+			field.Source,
+			field.DisplayRaw(),
+			field.Parser,
+			truncate(field.DisplayResult(), 40),
+			field.SkipReason,
+		)
+	}
+
+	tw.Flush()
+
+	return buf.String()
+}
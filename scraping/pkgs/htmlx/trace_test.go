@@ -0,0 +1,134 @@
+package htmlx
+
+import (
+	"strings"
+	"testing"
+)
+
+const traceTestContent = `
+<div class="match">
+	<a class="team" href="/team/624/paper-rex">Paper Rex</a>
+	<a class="event">VCT Pacific</a>
+	<div class="score">13 : 11</div>
+	<div class="note"> </div>
+	<div class="patch">Patch 10.04</div>
+	<div class="status">finished</div>
+</div>`
+
+type TraceTestMatch struct {
+	TeamName string  `selector:"a.team"`
+	EventId  int     `selector:"a.event" source:"attr=href" parser:"idParser"`
+	Score    int     `selector:"div.score" regex:"^([0-9]+) :"`
+	Rounds   int     `selector:"div.rounds"`
+	Note     *string `selector:"div.note"`
+	Patch    float64 `selector:"div.patch" regex:"Version ([0-9.]+)"`
+	Status   *bool   `selector:"div.status" parser:"nilParser"`
+	Vod      string  `selector:"div.vod" default:"none"`
+	Id       int
+	Tags     []string `selector:"div.tags" parser:"split(,)"`
+}
+
+var traceTestParsers = SetParsers(map[string]Parser{
+	"idParser": func(rawVal string) (any, error) {
+		if rawVal == "" {
+			return nil, nil
+		}
+
+		return IntParser(strings.Split(rawVal, "/")[2])
+	},
+	"nilParser": func(rawVal string) (any, error) {
+		return nil, nil
+	},
+})
+
+func TestTrace(t *testing.T) {
+	trace := NewTrace()
+
+	var match TraceTestMatch
+	if err := ParseFromString(&match, traceTestContent, SetTrace(trace), traceTestParsers); err != nil {
+		t.Fatal(err)
+	}
+
+	fields := map[string]FieldTrace{}
+	for _, field := range trace.Fields() {
+		fields[field.Field] = field
+	}
+
+	if len(fields) != 10 {
+		t.Fatalf("Every field should be traced, get %d traces", len(fields))
+	}
+
+	skipReasons := map[string]string{
+		"TeamName": "",
+		"EventId":  "attribute href missing",
+		"Score":    "",
+		"Rounds":   "no element matched",
+		"Note":     "empty raw value",
+		"Patch":    "regex didn't match",
+		"Status":   "parser returned nil",
+		"Vod":      "",
+		"Id":       "Missing selector for field 'Id'",
+	}
+
+	for name, want := range skipReasons {
+		field := fields[name]
+		if field.SkipReason != want || field.Skipped != (want != "") {
+			t.Errorf("Wrong skip reason for %s, want '%s', get '%s'", name, want, field.SkipReason)
+		}
+	}
+
+	if team := fields["TeamName"]; team.Matches != 1 || team.Raw != "Paper Rex" || team.Result != "Paper Rex" {
+		t.Errorf("Wrong trace for TeamName, get %+v", team)
+	}
+
+	if score := fields["Score"]; score.Raw != "13" || score.Result != 13 {
+		t.Errorf("Raw value should be traced after the regex, get %+v", score)
+	}
+
+	if vod := fields["Vod"]; !vod.DefaultUsed || vod.Raw != "none" || vod.Matches != 0 {
+		t.Errorf("Default value should be traced, get %+v", vod)
+	}
+
+	if status := fields["Status"]; status.Parser != "nilParser" || status.Result != nil {
+		t.Errorf("Nil parser result should be traced, get %+v", status)
+	}
+
+	if len(trace.Skipped()) != 6 {
+		t.Errorf("Wrong number of skipped fields, want 6, get %d", len(trace.Skipped()))
+	}
+}
+
+func TestTraceError(t *testing.T) {
+	trace := NewTrace()
+
+	var match struct {
+		TeamName string `selector:"a.team"`
+		Score    int    `selector:"div.score"`
+	}
+
+	if err := ParseFromString(&match, traceTestContent, SetTrace(trace)); err == nil {
+		t.Fatal("Parsing '13 : 11' to int should return error")
+	}
+
+	fields := trace.Fields()
+	if len(fields) != 2 || fields[1].Error == "" || fields[1].Raw != "13 : 11" {
+		t.Errorf("The failing field should be traced with its error, get %+v", fields)
+	}
+}
+
+func TestTraceString(t *testing.T) {
+	trace := NewTrace()
+
+	var match TraceTestMatch
+	if err := ParseFromString(&match, traceTestContent, SetTrace(trace), traceTestParsers); err != nil {
+		t.Fatal(err)
+	}
+
+	str := trace.String()
+
+	for _, want := range []string{"FIELD", "SKIPPED", "TeamName", "Paper Rex", "attribute href missing"} {
+		if !strings.Contains(str, want) {
+			t.Errorf("Trace string should contain '%s', get\n%s", want, str)
+		}
+	}
+}
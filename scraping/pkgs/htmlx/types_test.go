@@ -0,0 +1,152 @@
+package htmlx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const typesTestContent = `
+<div class="event">
+	<span class="tier">Yes</span>
+	<span class="stage">Playoffs</span>
+	<span class="count">42</span>
+	<span class="big">300</span>
+	<span class="negative">-3</span>
+	<span class="ratio">1.25</span>
+	<span class="duration">49:10</span>
+	<span class="long-duration">1:50:20</span>
+	<span class="go-duration">1h2m3s</span>
+	<span class="region">emea</span>
+</div>`
+
+type Stage string
+
+type Region struct {
+	Code string
+}
+
+func (r *Region) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return fmt.Errorf("empty region")
+	}
+
+	r.Code = strings.ToUpper(string(text))
+	return nil
+}
+
+type TypesTestInfo struct {
+	Tier1        bool          `selector:"span.tier"`
+	Stage        Stage         `selector:"span.stage"`
+	Count64      int64         `selector:"span.count"`
+	Count8       int8          `selector:"span.count"`
+	CountU       uint          `selector:"span.count"`
+	CountU16     *uint16       `selector:"span.count"`
+	Ratio32      float32       `selector:"span.ratio"`
+	Duration     time.Duration `selector:"span.duration"`
+	LongDuration time.Duration `selector:"span.long-duration"`
+	GoDuration   time.Duration `selector:"span.go-duration"`
+	Region       Region        `selector:"span.region"`
+	RegionPtr    *Region       `selector:"span.region"`
+	StageParsed  Stage         `selector:"span.stage"         parser:"lower|enum(playoffs=playoff)"`
+}
+
+func TestHtmlxTypes(t *testing.T) {
+	info := TypesTestInfo{}
+
+	if err := ParseFromString(&info, typesTestContent); err != nil {
+		t.Fatal(err)
+	}
+
+	if !info.Tier1 {
+		t.Errorf("Tier1 should be true")
+	}
+
+	if info.Stage != "Playoffs" {
+		t.Errorf("Wrong stage, want 'Playoffs', get '%s'", info.Stage)
+	}
+
+	if info.Count64 != 42 || info.Count8 != 42 || info.CountU != 42 {
+		t.Errorf("Wrong counts, want 42, get %d, %d, %d", info.Count64, info.Count8, info.CountU)
+	}
+
+	if info.CountU16 == nil || *info.CountU16 != 42 {
+		t.Errorf("Wrong count pointer, want 42, get %v", info.CountU16)
+	}
+
+	if info.Ratio32 != 1.25 {
+		t.Errorf("Wrong ratio, want 1.25, get %v", info.Ratio32)
+	}
+
+	if info.Duration != 49*time.Minute+10*time.Second {
+		t.Errorf("Wrong duration, want 49m10s, get %s", info.Duration)
+	}
+
+	if info.LongDuration != time.Hour+50*time.Minute+20*time.Second {
+		t.Errorf("Wrong long duration, want 1h50m20s, get %s", info.LongDuration)
+	}
+
+	if info.GoDuration != time.Hour+2*time.Minute+3*time.Second {
+		t.Errorf("Wrong go duration, want 1h2m3s, get %s", info.GoDuration)
+	}
+
+	if info.Region.Code != "EMEA" {
+		t.Errorf("Wrong region, want 'EMEA', get '%s'", info.Region.Code)
+	}
+
+	if info.RegionPtr == nil || info.RegionPtr.Code != "EMEA" {
+		t.Errorf("Wrong region pointer, want 'EMEA', get %v", info.RegionPtr)
+	}
+
+	if info.StageParsed != "playoff" {
+		t.Errorf("Wrong parsed stage, want 'playoff', get '%s'", info.StageParsed)
+	}
+}
+
+func TestHtmlxTypesOverflow(t *testing.T) {
+	tests := map[string]any{
+		"int8 overflow": &struct {
+			V int8 `selector:"span.big"`
+		}{},
+		"invalid bool": &struct {
+			V bool `selector:"span.stage"`
+		}{},
+		"invalid duration": &struct {
+			V time.Duration `selector:"span.stage"`
+		}{},
+		"unsupported type": &struct {
+			V complex128 `selector:"span.count"`
+		}{},
+	}
+
+	for name, s := range tests {
+		if err := ParseFromString(s, typesTestContent); err == nil {
+			t.Errorf("%s: parsing should return error", name)
+		}
+	}
+}
+
+func TestDurationParser(t *testing.T) {
+	tests := map[string]time.Duration{
+		"00:00":   0,
+		"49:10":   2950 * time.Second,
+		"1:50:20": 6620 * time.Second,
+		"90m":     90 * time.Minute,
+	}
+
+	for rawVal, want := range tests {
+		duration, err := DurationParser(rawVal)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if duration != want {
+			t.Errorf("Wrong duration for '%s', want %s, get %s", rawVal, want, duration)
+		}
+	}
+
+	if _, err := DurationParser("1:75:00"); err == nil {
+		t.Errorf("1:75:00 should not be parsable to duration")
+	}
+}
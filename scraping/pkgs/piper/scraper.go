@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"regexp"
 	"sync"
 
@@ -47,18 +48,34 @@ func (sc *Scraper) Handle(regex *regexp.Regexp, h Handler) {
 // It return error if either the no matching regex expression is found or the handler return error.
 // Pipe is safe for concurrent usage.
 func (sc *Scraper) Pipe(pattern string, ctx context.Context, selection *goquery.Selection) error {
+	handler := sc.lookupHandler(pattern)
+	if handler == nil {
+		return fmt.Errorf("No handler match the pattern: '%s'", pattern)
+	}
+
+	if err := handler(sc, ctx, selection); err != nil {
+		sc.mu.Lock()
+		sc.errors[pattern] = err
+		sc.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// Return the handler of the first regex expression that match the pattern, nil if there is none
+func (sc *Scraper) lookupHandler(pattern string) Handler {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
 	for regex, handler := range sc.handlers {
 		if regex.MatchString(pattern) {
-			if err := handler(sc, ctx, selection); err != nil {
-				sc.errors[pattern] = err
-				return err
-			}
-
-			return nil
+			return handler
 		}
 	}
 
-	return fmt.Errorf("No handler match the pattern: '%s'", pattern)
+	return nil
 }
 
 // Get make a GET request using the given url and body and pass the [github.com/PuerkitoBio/goquery.Selection] to [Scraper.Pipe]
@@ -108,5 +125,8 @@ func (sc *Scraper) Cache() Cache {
 
 // Errors return all errors returned from the handlers
 func (sc *Scraper) Errors() map[string]error {
-	return sc.errors
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	return maps.Clone(sc.errors)
 }
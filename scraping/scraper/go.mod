@@ -12,6 +12,7 @@ require (
 	github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper v0.0.0
 	github.com/mattn/go-sqlite3 v1.14.30
 	github.com/sirupsen/logrus v1.9.3
+	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/driver/sqlite v1.6.0
 	gorm.io/gorm v1.30.1
 )
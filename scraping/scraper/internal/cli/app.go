@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/crawler"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/discovery"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/mappool"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/migrations"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/banpicklog"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/matches"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/matchmaps"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/playerduelstats"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/playerhighlights"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/players"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/playerstats"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/rankings"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/roundstats"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/teams"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/tournaments"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const (
+	queueDbName = "vlr_cache.db"
+	cacheDbName = "scraper_cache.db"
+)
+
+// Everything the commands need, opened from the config
+type app struct {
+	config  Config
+	vlrDb   *gorm.DB
+	sink    sinks.Sink
+	queue   *crawler.Queue
+	scraper *piper.Scraper
+	// The scraped matches without a veto note
+	vetoReport *banpicklog.Report
+	// The map pool timeline the ban pick logs are checked against, loaded once by the first scraped match
+	mapPool     *mappool.Timeline
+	mapPoolOnce sync.Once
+	// Serialize the dry run documents written to stdout and the traces
+	outMu sync.Mutex
+}
+
+// Return the sqlite dsn of the vlr db. With concurrent scraping, a transaction waits for the write lock held by another
+// instead of failing with "database is locked". The transactions are deferred so the lock is only taken by their first
+// write, a match whose transaction can't take it is scraped again, see [app.retryBusy]
+func vlrDbDsn(vlrDbPath string, concurrency int) string {
+	if strings.Contains(vlrDbPath, "?") {
+		return vlrDbPath
+	}
+
+	if concurrency <= 1 {
+		return vlrDbPath
+	}
+
+	return vlrDbPath + "?_busy_timeout=30000"
+}
+
+func newScraper(cachePath string) (*piper.Scraper, error) {
+	cache, err := piper.NewCacheDb(cachePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cache.Validate(); err != nil && err != piper.ErrIncorrectSchema {
+		return nil, err
+	} else if err == piper.ErrIncorrectSchema {
+		if err = cache.Setup(); err != nil {
+			return nil, err
+		}
+	}
+
+	backend := piper.NewPiperBackend(&http.Client{})
+
+	sc := piper.NewScraper(backend, cache)
+	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/[0-9]+\/[a-z0-9\/-]*$`), matches.Handler)
+	sc.Handle(regexp.MustCompile(`matchMaps`), matchmaps.Handler)
+	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/team\/[0-9]+\/[a-z0-9\/-]*$`), teams.Handler)
+	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/event\/[0-9]+\/[a-z0-9\/-]*$`), tournaments.Handler)
+	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/event\/matches\/[0-9]+\/\?series_id=all$`), tournaments.MatchesHandler)
+	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/event\/[0-9]+\/\?bracket$`), tournaments.BracketHandler)
+	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/rankings\/?$`), rankings.Handler)
+	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/rankings\/[a-z-]+\/?$`), rankings.RegionHandler)
+	sc.Handle(regexp.MustCompile(`^roundStat$`), roundstats.Handler)
+	sc.Handle(regexp.MustCompile(`^playerStats$`), playerstats.Handler)
+	sc.Handle(regexp.MustCompile(`^duelStats$`), playerduelstats.Handler)
+	sc.Handle(regexp.MustCompile(`^highlights$`), playerhighlights.Handler)
+	sc.Handle(regexp.MustCompile(`^banPickLog$`), banpicklog.Handler)
+	sc.Handle(regexp.MustCompile(`^scheduledMatch$`), matches.ScheduledHandler)
+	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/player\/[0-9]+\/[a-z0-9-]*$`), players.Handler)
+	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/player\/[0-9]+\/\?timespan=[a-z0-9]+$`), players.StatsHandler)
+
+	return sc, nil
+}
+
+func newApp(config Config) (*app, error) {
+	if config.VlrDbPath == "" {
+		return nil, fmt.Errorf("Vlr db path is not set, use -db or VLR_DB_PATH")
+	}
+
+	logrus.Debug("Connecting to vlr db")
+	vlrDb, err := gorm.Open(sqlite.Open(vlrDbDsn(config.VlrDbPath, config.Concurrency)), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("Error opening vlr db: %s", err.Error())
+	}
+
+	if config.DryRun && config.OutDir != "" {
+		if err = os.MkdirAll(config.OutDir, 0o755); err != nil {
+			return nil, fmt.Errorf("Error creating output directory: %s", err.Error())
+		}
+	}
+
+	sink, err := openSink(config, vlrDb)
+	if err != nil {
+		return nil, fmt.Errorf("Error opening %s sink: %s", config.Sink, err.Error())
+	}
+
+	logrus.Debug("Connecting to cache db")
+	queue, err := crawler.OpenQueue(path.Join(config.TmpDir, queueDbName))
+	if err != nil {
+		sink.Close()
+		return nil, fmt.Errorf("Error opening queue: %s", err.Error())
+	}
+
+	sc, err := newScraper(path.Join(config.TmpDir, cacheDbName))
+	if err != nil {
+		sink.Close()
+		queue.Close()
+		return nil, fmt.Errorf("Error opening scraper cache: %s", err.Error())
+	}
+
+	return &app{config: config, vlrDb: vlrDb, sink: sink, queue: queue, scraper: sc, vetoReport: &banpicklog.Report{}}, nil
+}
+
+// Open the sink of the config, the sqlite sink save to the vlr db
+func openSink(config Config, vlrDb *gorm.DB) (sinks.Sink, error) {
+	switch config.Sink {
+	case ndjsonSink:
+		return sinks.NewNDJSON(config.OutDir)
+	case csvSink:
+		return sinks.NewCSV(config.OutDir)
+	default:
+		return sinks.NewSQLite(vlrDb), nil
+	}
+}
+
+func (a *app) Close() {
+	if err := a.sink.Close(); err != nil {
+		logrus.Errorf("Error closing sink: %s", err.Error())
+	}
+
+	if err := a.queue.Close(); err != nil {
+		logrus.Errorf("Error closing queue: %s", err.Error())
+	}
+
+	if db, err := a.vlrDb.DB(); err == nil {
+		db.Close()
+	}
+}
+
+// Run fc in a transaction of the sink, tx is the transaction of the vlr db the reference data is read from.
+// With the sqlite sink both are the same transaction, otherwise the vlr db transaction is committed with the sink's
+func (a *app) transaction(fc func(tx *gorm.DB, sink sinks.Sink) error) error {
+	return a.sink.Transaction(func(sink sinks.Sink) error {
+		return a.withReferenceTx(sink, fc)
+	})
+}
+
+// Run fc with the vlr db transaction matching the sink transaction, see [app.transaction]
+func (a *app) withReferenceTx(sink sinks.Sink, fc func(tx *gorm.DB, sink sinks.Sink) error) error {
+	if sqliteSink, ok := sink.(*sinks.SQLite); ok {
+		return fc(sqliteSink.DB(), sink)
+	}
+
+	return a.vlrDb.Transaction(func(tx *gorm.DB) error {
+		return fc(tx, sink)
+	})
+}
+
+// Return the map pool timeline of the vlr db, nil if it can't be loaded so the ban pick logs are not checked
+func (a *app) mapPoolTimeline() *mappool.Timeline {
+	a.mapPoolOnce.Do(func() {
+		timeline, err := mappool.Load(a.vlrDb)
+		if err != nil {
+			logrus.Warnf("Unable to check the maps of the ban pick logs: %s", err.Error())
+			return
+		}
+
+		a.mapPool = timeline
+	})
+
+	return a.mapPool
+}
+
+// Log the scraped matches without a veto note, their ban pick log is missing
+func (a *app) reportMissingVetoNotes() {
+	matchIds := a.vetoReport.MatchIds()
+	if len(matchIds) == 0 {
+		return
+	}
+
+	logrus.Warnf("%d matches have no veto note, their ban pick log is missing: %v", len(matchIds), matchIds)
+}
+
+// Warn about the migrations of the vlr db schema which are not applied, see the migrate command
+func (a *app) warnPendingMigrations() {
+	pending, err := migrations.Pending(a.vlrDb)
+	if err != nil {
+		logrus.Errorf("Error checking schema migrations: %s", err.Error())
+		return
+	}
+
+	if len(pending) > 0 {
+		logrus.Warnf("%d migrations of the vlr db are not applied, run migrate up", len(pending))
+	}
+}
+
+// Log the maps and agents discovered while scraping which are not confirmed, see the pending and confirm commands
+func (a *app) reportPendingReferences() {
+	maps, agents, err := discovery.Pending(a.vlrDb)
+	if err != nil {
+		logrus.Errorf("Error reporting pending maps and agents: %s", err.Error())
+		return
+	}
+
+	for _, vlrMap := range maps {
+		logrus.Warnf("Map '%s' first seen on %s is pending, confirm it with its release date", vlrMap.Name, vlrMap.ReleaseDate)
+	}
+
+	for _, agent := range agents {
+		logrus.Warnf("Agent '%s' (%s) first seen on %s is pending, confirm it with its release date", agent.Name, agent.AgentType, agent.ReleaseDate)
+	}
+}
+
+// Write the dry run document of the match to the output directory, or to stdout
+func (a *app) writeDocument(matchId int, doc *sinks.Memory) error {
+	jsonDat, err := json.MarshalIndent(doc, "", "	")
+	if err != nil {
+		return err
+	}
+
+	if a.config.OutDir != "" {
+		return os.WriteFile(path.Join(a.config.OutDir, fmt.Sprintf("%d.json", matchId)), append(jsonDat, '\n'), 0o644)
+	}
+
+	a.outMu.Lock()
+	defer a.outMu.Unlock()
+
+	_, err = fmt.Println(string(jsonDat))
+	return err
+}
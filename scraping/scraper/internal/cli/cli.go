@@ -0,0 +1,108 @@
+// Package cli implement the command line interface of the scraper
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	exitOk    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: scraper <command> [flags] [args]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-28s %s\n", cmd.name+" "+cmd.args, cmd.description)
+	}
+
+	fmt.Fprintf(w, "\nFlags must come before the arguments, run 'scraper <command> -h' to list them.\n")
+	fmt.Fprintf(w, "Unset flags fall back to the environment and the env file.\n")
+}
+
+// Run the command given by the arguments, without the program name, and return the exit code
+func Run(args []string) int {
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return exitUsage
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		usage(os.Stdout)
+		return exitOk
+	}
+
+	cmd, ok := lookupCommand(args[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command '%s'\n\n", args[0])
+		usage(os.Stderr)
+		return exitUsage
+	}
+
+	fs, values := newFlagSet(cmd.name)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: scraper %s [flags] %s\n\n%s\n\nFlags:\n", cmd.name, cmd.args, cmd.description)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOk
+		}
+
+		return exitUsage
+	}
+
+	config, err := values.config()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return exitUsage
+	}
+
+	if config.DryRun && !cmd.dryRun {
+		fmt.Fprintf(os.Stderr, "-dry-run is not supported by %s\n", cmd.name)
+		return exitUsage
+	}
+
+	logrus.SetLevel(config.LogLevel)
+
+	a, err := newApp(config)
+	if err != nil {
+		logrus.Error(err)
+		return exitError
+	}
+	defer a.Close()
+
+	// The reports read the tables of the migrations, the migrate command may run before they exist
+	migrate := cmd.name == "migrate"
+	if !migrate {
+		a.warnPendingMigrations()
+	}
+
+	err = cmd.run(a, fs.Args())
+	a.reportMissingVetoNotes()
+	if !migrate {
+		a.reportPendingReferences()
+	}
+
+	if err != nil {
+		var usageErr usageError
+		if errors.As(err, &usageErr) {
+			fmt.Fprintf(os.Stderr, "%s\n\n", usageErr.Error())
+			fs.Usage()
+			return exitUsage
+		}
+
+		logrus.Error(err)
+		return exitError
+	}
+
+	return exitOk
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseMatchArg(t *testing.T) {
+	tests := map[string]struct {
+		id  int
+		url string
+	}{
+		"449010":                                   {449010, "/449010/"},
+		"https://www.vlr.gg/449010/prx-vs-sen":     {449010, "/449010/prx-vs-sen"},
+		"https://www.vlr.gg/449010/prx-vs-sen/":    {449010, "/449010/prx-vs-sen"},
+		"https://www.vlr.gg/449010":                {449010, "/449010/"},
+		"/449010/prx-vs-sen":                       {449010, "/449010/prx-vs-sen"},
+		"https://www.vlr.gg/449010/prx-vs-sen?x=1": {449010, "/449010/prx-vs-sen"},
+	}
+
+	for arg, want := range tests {
+		id, url, err := parseMatchArg(arg)
+		if err != nil {
+			t.Errorf("'%s': %s", arg, err.Error())
+			continue
+		}
+
+		if id != want.id || url != want.url {
+			t.Errorf("'%s': want %d %s, get %d %s", arg, want.id, want.url, id, url)
+		}
+	}
+
+	for _, arg := range []string{"-1", "abc", "https://www.vlr.gg/team/624/paper-rex", "https://example.com/449010/x"} {
+		if _, _, err := parseMatchArg(arg); err == nil {
+			t.Errorf("'%s' should return error", arg)
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	envFile := path.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envFile, []byte("VLR_DB_PATH=env.db\nTMP_DIR=env_tmp\nCONCURRENCY=3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("VLR_DB_PATH", "")
+	t.Setenv("TMP_DIR", "")
+	t.Setenv("CONCURRENCY", "")
+	t.Setenv("SINK", "")
+	t.Setenv("OUT_DIR", "")
+	t.Setenv("LOG_LEVEL", "warn")
+	os.Unsetenv("VLR_DB_PATH")
+	os.Unsetenv("TMP_DIR")
+	os.Unsetenv("CONCURRENCY")
+	os.Unsetenv("SINK")
+	os.Unsetenv("OUT_DIR")
+
+	fs, values := newFlagSet("scrape")
+	if err := fs.Parse([]string{"-env", envFile, "-db", "flag.db", "-from", "2025-01-01", "-to", "2025-02-01", "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := values.config()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Config{
+		VlrDbPath:   "flag.db",
+		TmpDir:      "env_tmp",
+		Concurrency: 3,
+		From:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		LogLevel:    logrus.WarnLevel,
+		Sink:        sqliteSink,
+	}
+
+	if config != want {
+		t.Errorf("Wrong config\nwant %+v\nget  %+v", want, config)
+	}
+
+	if args := fs.Args(); len(args) != 1 || args[0] != "x" {
+		t.Errorf("Wrong args, get %v", args)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	t.Setenv("SINK", "")
+	t.Setenv("OUT_DIR", "")
+
+	tests := [][]string{
+		{"-from", "01/02/2025"},
+		{"-from", "2025-02-01", "-to", "2025-01-01"},
+		{"-concurrency", "-2"},
+		{"-log-level", "loud"},
+		{"-out", "documents"},
+		{"-sink", "parquet"},
+		{"-sink", "csv"},
+		{"-sink", "ndjson", "-out", "documents", "-dry-run"},
+	}
+
+	for _, args := range tests {
+		fs, values := newFlagSet("scrape")
+		if err := fs.Parse(append([]string{"-env", "missing.env"}, args...)); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := values.config(); err == nil {
+			t.Errorf("%v should return error", args)
+		}
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	if code := Run(nil); code != exitUsage {
+		t.Errorf("No command should exit with %d, get %d", exitUsage, code)
+	}
+
+	if code := Run([]string{"unknown"}); code != exitUsage {
+		t.Errorf("Unknown command should exit with %d, get %d", exitUsage, code)
+	}
+
+	if code := Run([]string{"status", "-env", "missing.env", "-from", "yesterday"}); code != exitUsage {
+		t.Errorf("Invalid flag should exit with %d, get %d", exitUsage, code)
+	}
+}
+
+func TestRunDryRunUnsupported(t *testing.T) {
+	if code := Run([]string{"crawl", "-env", "missing.env", "-dry-run"}); code != exitUsage {
+		t.Errorf("Dry run of crawl should exit with %d, get %d", exitUsage, code)
+	}
+}
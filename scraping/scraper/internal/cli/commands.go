@@ -0,0 +1,726 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/crawler"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/discovery"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/mappool"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/migrations"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/players"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/rankings"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/tiers"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/utils/urlinfo"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Error caused by the arguments of the command, the usage of the command is printed
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+type command struct {
+	name        string
+	args        string
+	description string
+	// The command can be run with -dry-run
+	dryRun bool
+	run    func(a *app, args []string) error
+}
+
+var commands = []command{
+	{"crawl", "", "crawl the results pages and add the new matches to the queue", false, runCrawl},
+	{"scrape", "", "scrape the pending matches of the queue", true, runScrape},
+	{"match", "<id|url>...", "scrape the matches, even if they are not in the queue", true, runMatch},
+	{"rescrape", "<id|url>...", "scrape the saved matches again, replacing their rows and logging the changes", false, runRescrape},
+	{"team", "<id>...", "scrape the teams", false, runEntity(sinks.Teams, "https://www.vlr.gg/team/%d/", "teamSchema",
+		func(id int, url string) any { return &models.TeamSchema{Id: id, Url: url} })},
+	{"player", "<id>...", "scrape the players", false, runEntity(sinks.Players, "https://www.vlr.gg/player/%d/", "player",
+		func(id int, url string) any { return &models.PlayerSchema{Id: id, Url: url} })},
+	{"event", "<id>...", "scrape the events", false, runEntity(sinks.Tournaments, "https://www.vlr.gg/event/%d/", "tournamentSchema",
+		func(id int, url string) any { return &models.TournamentSchema{Id: id, Url: url} })},
+	{"player-stats", "<30d|60d|90d|all> <id>...", "save a snapshot of the agent stats and the teams of the players over the timespan", false, runPlayerStats},
+	{"reclassify", "", "classify the saved events again with the tier rules", false, runReclassify},
+	{"pending", "", "show the maps and agents discovered while scraping which are not confirmed", false, runPending},
+	{"confirm", "<map|agent> <name> [release date]", "confirm the discovered map or agent, with its release date if it isn't the day it was first seen", false, runConfirm},
+	{"map-pool", "[date]", "show the map pool on the date, or today", false, runMapPool},
+	{"map-pool-update", "[overrides]", "add the map pool rotations inferred from the scraped matches and the pools of the yaml overrides file", false, runMapPoolUpdate},
+	{"roster", "<id>...", "scrape the teams again to refresh their rosters", false, runRoster},
+	{"rankings", "[region]...", "save a snapshot of the team rankings of every region, or of the regions", false, runRankings},
+	{"upcoming", "", "scrape the matches which are not played yet to the scheduled matches", false, runUpcoming},
+	{"status", "", "show the number of pending and failed matches and the failures", false, runStatus},
+	{"migrate", "<up|status>", "apply the pending migrations of the vlr db schema, or show the migrations", false, runMigrate},
+	{"retry-failed", "", "scrape the failed matches again", false, runRetryFailed},
+}
+
+func lookupCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+
+	return command{}, false
+}
+
+func noArgs(args []string) error {
+	if len(args) > 0 {
+		return usageError{fmt.Sprintf("Unexpected arguments: %s", strings.Join(args, " "))}
+	}
+
+	return nil
+}
+
+// Parse the id of the team, player or event
+func parseIdArg(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id <= 0 {
+		return 0, usageError{fmt.Sprintf("Invalid id '%s'", arg)}
+	}
+
+	return id, nil
+}
+
+// Parse the match id and the relative url of the match from an id or an url, e.g "449010" or "https://www.vlr.gg/449010/prx-vs-sen"
+func parseMatchArg(arg string) (int, string, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		if id <= 0 {
+			return 0, "", usageError{fmt.Sprintf("Invalid match id '%s'", arg)}
+		}
+
+		return id, fmt.Sprintf("/%d/", id), nil
+	}
+
+	parsedUrl, err := url.Parse(arg)
+	if err != nil || (parsedUrl.Host != "" && parsedUrl.Host != "www.vlr.gg" && parsedUrl.Host != "vlr.gg") {
+		return 0, "", usageError{fmt.Sprintf("Invalid match url '%s'", arg)}
+	}
+
+	relativeUrl := strings.TrimSuffix(parsedUrl.Path, "/")
+	if _, err := strconv.Atoi(strings.TrimPrefix(relativeUrl, "/")); err == nil {
+		// Url without the slug, e.g https://www.vlr.gg/449010
+		relativeUrl += "/"
+	}
+
+	urlInfo, err := urlinfo.ExtractUrlInfo(relativeUrl)
+	if err != nil || !urlInfo.IsMatch() {
+		return 0, "", usageError{fmt.Sprintf("'%s' is not a match url", arg)}
+	}
+
+	return urlInfo.Id, relativeUrl, nil
+}
+
+func runCrawl(a *app, args []string) error {
+	if err := noArgs(args); err != nil {
+		return err
+	}
+
+	newMatches, err := crawler.FillQueue(a.queue, a.config.VlrDbPath, a.config.From, a.config.To)
+	if err != nil {
+		return fmt.Errorf("Error crawling matches: %s", err.Error())
+	}
+
+	logrus.Infof("Added %d matches to the queue", len(newMatches))
+	return nil
+}
+
+func runScrape(a *app, args []string) error {
+	if err := noArgs(args); err != nil {
+		return err
+	}
+
+	pending, err := a.queue.Pending(a.config.From, a.config.To)
+	if err != nil {
+		return fmt.Errorf("Error retrieving matches from queue: %s", err.Error())
+	}
+
+	return a.scrapeQueue(pending)
+}
+
+func runRetryFailed(a *app, args []string) error {
+	if err := noArgs(args); err != nil {
+		return err
+	}
+
+	failed, err := a.queue.Failed(a.config.From, a.config.To)
+	if err != nil {
+		return fmt.Errorf("Error retrieving matches from queue: %s", err.Error())
+	}
+
+	if _, err = a.queue.ResetFailed(a.config.From, a.config.To); err != nil {
+		return fmt.Errorf("Error resetting failed matches: %s", err.Error())
+	}
+
+	logrus.Infof("Retrying %d failed matches", len(failed))
+	return a.scrapeQueue(failed)
+}
+
+func runMatch(a *app, args []string) error {
+	if len(args) == 0 {
+		return usageError{"Missing match id or url"}
+	}
+
+	for _, arg := range args {
+		matchId, relativeUrl, err := parseMatchArg(arg)
+		if err != nil {
+			return err
+		}
+
+		exists, err := a.sink.Exists(sinks.Matches, matchId)
+		if err != nil {
+			return err
+		}
+
+		if exists && !a.config.DryRun {
+			logrus.Warnf("Match %d exists, use rescrape to scrape it again", matchId)
+			continue
+		}
+
+		// The date of a queued match is known from the results pages, otherwise it is parsed from the match page
+		queued, err := a.queue.Find(relativeUrl)
+		if err != nil {
+			return fmt.Errorf("Error retrieving match from queue: %s", err.Error())
+		}
+
+		var matchToBeScraped crawler.MatchToBeScraped
+		if queued != nil {
+			matchToBeScraped = *queued
+		}
+
+		if err = a.scrapeMatch(matchId, vlrBaseUrl+relativeUrl, matchToBeScraped.Date); err != nil {
+			return err
+		}
+
+		if queued != nil && !a.config.DryRun {
+			if err = a.queue.Done(relativeUrl); err != nil {
+				logrus.Errorf("Error deleting match from cache: '%s'", err.Error())
+			}
+		}
+
+		logrus.Infof("Match %d scraped", matchId)
+	}
+
+	return nil
+}
+
+func runRescrape(a *app, args []string) error {
+	if len(args) == 0 {
+		return usageError{"Missing match id or url"}
+	}
+
+	rescraper, ok := a.sink.(sinks.Rescraper)
+	if !ok {
+		return fmt.Errorf("The %s sink can't replace saved matches, rescrape needs the sqlite sink", a.config.Sink)
+	}
+
+	for _, arg := range args {
+		matchId, relativeUrl, err := parseMatchArg(arg)
+		if err != nil {
+			return err
+		}
+
+		exists, err := a.sink.Exists(sinks.Matches, matchId)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			logrus.Warnf("Match %d isn't saved, scraping it", matchId)
+
+			if err = a.scrapeMatch(matchId, vlrBaseUrl+relativeUrl, time.Time{}); err != nil {
+				return err
+			}
+
+			logrus.Infof("Match %d scraped", matchId)
+			continue
+		}
+
+		changes, err := a.rescrapeMatch(rescraper, matchId, vlrBaseUrl+relativeUrl)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range changes {
+			switch change.Kind {
+			case models.RowUpdated:
+				logrus.Infof("Match %d: %s %s %s changed from %s to %s", matchId, change.TableName, change.RowKey, *change.Field, nullableString(change.OldValue), nullableString(change.NewValue))
+			default:
+				logrus.Infof("Match %d: %s %s %s", matchId, change.TableName, change.RowKey, change.Kind)
+			}
+		}
+
+		logrus.Infof("Match %d scraped again, %d changes", matchId, len(changes))
+	}
+
+	return nil
+}
+
+func nullableString(value *string) string {
+	if value == nil {
+		return "null"
+	}
+
+	return *value
+}
+
+func runUpcoming(a *app, args []string) error {
+	if err := noArgs(args); err != nil {
+		return err
+	}
+
+	upcoming, err := crawler.CrawlUpcoming(upcomingPages)
+	if err != nil {
+		return fmt.Errorf("Error crawling upcoming matches: %s", err.Error())
+	}
+
+	failed := 0
+
+	for _, match := range upcoming {
+		urlInfo, err := urlinfo.ExtractUrlInfo(match.Url)
+		if err != nil || !urlInfo.IsMatch() {
+			return fmt.Errorf("Unable to extract match information from url '%s'", match.Url)
+		}
+
+		// A live match can be listed while its result is already saved
+		exists, err := a.sink.Exists(sinks.Matches, urlInfo.Id)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			logrus.Debugf("Match %d is already played, continue", urlInfo.Id)
+			continue
+		}
+
+		if err = a.scrapeScheduledMatch(urlInfo.Id, vlrBaseUrl+match.Url); err != nil {
+			logrus.Errorf("Error scraping scheduled match %d: %s", urlInfo.Id, err.Error())
+			failed++
+			continue
+		}
+
+		logrus.Infof("Scheduled match %d scraped", urlInfo.Id)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d scheduled matches failed", failed, len(upcoming))
+	}
+
+	return nil
+}
+
+// Return the command scraping the entities of the table, the schema is passed to the handler under the context key
+func runEntity(tableName, urlFormat, ctxKey string, newSchema func(id int, url string) any) func(a *app, args []string) error {
+	return func(a *app, args []string) error {
+		if len(args) == 0 {
+			return usageError{"Missing id"}
+		}
+
+		for _, arg := range args {
+			id, err := parseIdArg(arg)
+			if err != nil {
+				return err
+			}
+
+			exists, err := a.sink.Exists(tableName, id)
+			if err != nil {
+				return err
+			}
+
+			if exists {
+				logrus.Warnf("%d already exists in %s, continue", id, tableName)
+				continue
+			}
+
+			entityUrl := fmt.Sprintf(urlFormat, id)
+			if err = a.scrapeEntity(entityUrl, ctxKey, newSchema(id, entityUrl)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// Scrape the team, player or event page, the schema is passed to the handler under the context key
+func (a *app) scrapeEntity(entityUrl, ctxKey string, schema any) error {
+	if err := a.transaction(func(tx *gorm.DB, sink sinks.Sink) error {
+		ctx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), ctxKey, schema), "tx", tx), sink)
+		return a.scraper.Get(entityUrl, ctx, nil)
+	}); err != nil {
+		return fmt.Errorf("Error scraping %s: %s", entityUrl, err.Error())
+	}
+
+	logrus.Infof("%s scraped", entityUrl)
+	return nil
+}
+
+// Scrape the teams again, saved or not, the roster members who left since the last scrape become former members
+func runRoster(a *app, args []string) error {
+	if len(args) == 0 {
+		return usageError{"Missing id"}
+	}
+
+	for _, arg := range args {
+		id, err := parseIdArg(arg)
+		if err != nil {
+			return err
+		}
+
+		teamUrl := fmt.Sprintf("https://www.vlr.gg/team/%d/", id)
+		if err = a.scrapeEntity(teamUrl, "teamSchema", &models.TeamSchema{Id: id, Url: teamUrl}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Scrape the rankings pages of the regions, of every region if there is no region, to the snapshot of the day
+func runRankings(a *app, args []string) error {
+	for _, region := range args {
+		if !rankings.IsRegionSlug(region) {
+			return usageError{fmt.Sprintf("Invalid region '%s', use the slug of the rankings page, e.g north-america", region)}
+		}
+	}
+
+	snapshotDate := time.Now().UTC().Truncate(24 * time.Hour)
+
+	if len(args) == 0 {
+		return a.scrapeEntity(rankings.RankingsUrl, "teamRanking", &models.TeamRankingSchema{SnapshotDate: snapshotDate})
+	}
+
+	for _, region := range args {
+		if err := a.scrapeEntity(rankings.RegionUrl(region), "teamRanking", &models.TeamRankingSchema{SnapshotDate: snapshotDate, Region: region}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Scrape the agent stats and the team history of the players over the timespan to the snapshot of the day
+func runPlayerStats(a *app, args []string) error {
+	if len(args) == 0 {
+		return usageError{"Missing timespan"}
+	}
+
+	timespan := models.StatsTimespan(args[0])
+	if !players.IsTimespan(timespan) {
+		return usageError{fmt.Sprintf("Invalid timespan '%s', want 30d, 60d, 90d or all", args[0])}
+	}
+
+	if len(args) == 1 {
+		return usageError{"Missing id"}
+	}
+
+	snapshotDate := time.Now().UTC().Truncate(24 * time.Hour)
+
+	for _, arg := range args[1:] {
+		id, err := parseIdArg(arg)
+		if err != nil {
+			return err
+		}
+
+		snapshot := models.PlayerAgentStatSchema{PlayerId: id, SnapshotDate: snapshotDate, Timespan: timespan}
+		if err = a.scrapeEntity(players.StatsUrl(id, timespan), "playerAgentStat", &snapshot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runReclassify(a *app, args []string) error {
+	if err := noArgs(args); err != nil {
+		return err
+	}
+
+	if _, ok := a.sink.(*sinks.SQLite); !ok {
+		return fmt.Errorf("The %s sink can't update saved events, reclassify needs the sqlite sink", a.config.Sink)
+	}
+
+	return a.vlrDb.Transaction(func(tx *gorm.DB) error {
+		classifier, err := tiers.Load(tx)
+		if err != nil {
+			return err
+		}
+
+		reclassifications, err := classifier.Reclassify(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, reclassification := range reclassifications {
+			logrus.Infof("Event %d: tier changed from %s to %s", reclassification.TournamentId, reclassification.OldTier, reclassification.NewTier)
+		}
+
+		logrus.Infof("%d events reclassified", len(reclassifications))
+		return nil
+	})
+}
+
+func runPending(a *app, args []string) error {
+	if err := noArgs(args); err != nil {
+		return err
+	}
+
+	maps, agents, err := discovery.Pending(a.vlrDb)
+	if err != nil {
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"KIND", "NAME", "AGENT TYPE", "FIRST SEEN"})
+	for _, vlrMap := range maps {
+		t.AppendRow(table.Row{"map", vlrMap.Name, "", vlrMap.ReleaseDate})
+	}
+	for _, agent := range agents {
+		t.AppendRow(table.Row{"agent", agent.Name, agent.AgentType, agent.ReleaseDate})
+	}
+	t.Render()
+
+	return nil
+}
+
+func runConfirm(a *app, args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return usageError{"Confirm needs the kind and the name, and optionally the release date"}
+	}
+
+	if args[0] != "map" && args[0] != "agent" {
+		return usageError{fmt.Sprintf("Invalid kind '%s', want map or agent", args[0])}
+	}
+
+	var releaseDate string
+	if len(args) == 3 {
+		releaseDate = args[2]
+	}
+
+	if err := a.vlrDb.Transaction(func(tx *gorm.DB) error {
+		return discovery.Confirm(tx, args[0], args[1], releaseDate)
+	}); err != nil {
+		return err
+	}
+
+	logrus.Infof("Confirmed %s '%s'", args[0], args[1])
+	return nil
+}
+
+// Return the names of the maps by id
+func mapNames(tx *gorm.DB) (map[int]string, error) {
+	var maps []models.MapSchema
+	if err := tx.Table("maps").Find(&maps).Error; err != nil {
+		return nil, fmt.Errorf("Error loading maps: %s", err.Error())
+	}
+
+	names := make(map[int]string, len(maps))
+	for _, vlrMap := range maps {
+		names[vlrMap.Id] = vlrMap.Name
+	}
+
+	return names, nil
+}
+
+func printMapPools(tx *gorm.DB, pools []mappool.Pool) error {
+	names, err := mapNames(tx)
+	if err != nil {
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"DATE", "PATCH", "MAPS"})
+	for _, pool := range pools {
+		poolNames := make([]string, 0, len(pool.MapIds))
+		for _, mapId := range pool.MapIds {
+			poolNames = append(poolNames, names[mapId])
+		}
+		sort.Strings(poolNames)
+
+		t.AppendRow(table.Row{pool.Date.Format("2006-01-02"), pool.PatchNo, strings.Join(poolNames, ", ")})
+	}
+	t.Render()
+
+	return nil
+}
+
+func runMapPool(a *app, args []string) error {
+	if len(args) > 1 {
+		return usageError{fmt.Sprintf("Unexpected arguments: %s", strings.Join(args[1:], " "))}
+	}
+
+	date := time.Now().UTC()
+	if len(args) == 1 {
+		var err error
+		if date, err = time.Parse("2006-01-02", args[0]); err != nil {
+			return usageError{fmt.Sprintf("Invalid date '%s', want YYYY-MM-DD", args[0])}
+		}
+	}
+
+	timeline, err := mappool.Load(a.vlrDb)
+	if err != nil {
+		return err
+	}
+
+	pool, ok := timeline.At(date)
+	if !ok {
+		return fmt.Errorf("No map pool on %s", date.Format("2006-01-02"))
+	}
+
+	return printMapPools(a.vlrDb, []mappool.Pool{pool})
+}
+
+func runMapPoolUpdate(a *app, args []string) error {
+	if len(args) > 1 {
+		return usageError{fmt.Sprintf("Unexpected arguments: %s", strings.Join(args[1:], " "))}
+	}
+
+	var overrides []mappool.Override
+	if len(args) == 1 {
+		var err error
+		if overrides, err = mappool.ReadOverrides(args[0]); err != nil {
+			return err
+		}
+	}
+
+	return a.vlrDb.Transaction(func(tx *gorm.DB) error {
+		pools, err := mappool.ResolveOverrides(tx, overrides)
+		if err != nil {
+			return err
+		}
+
+		saved, err := mappool.Update(tx, pools, mappool.DefaultOptions)
+		if err != nil {
+			return err
+		}
+
+		logrus.Infof("%d map pools saved", len(saved))
+		if len(saved) == 0 {
+			return nil
+		}
+
+		return printMapPools(tx, saved)
+	})
+}
+
+func runMigrate(a *app, args []string) error {
+	if len(args) != 1 {
+		return usageError{"Migrate needs up or status"}
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrations.Up(a.vlrDb)
+		for _, m := range applied {
+			logrus.Infof("Applied migration %d %s", m.Version, m.Name)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		logrus.Infof("%d migrations applied", len(applied))
+		return nil
+	case "status":
+		return printMigrations(a.vlrDb)
+	default:
+		return usageError{fmt.Sprintf("Invalid migrate action '%s', want up or status", args[0])}
+	}
+}
+
+func printMigrations(tx *gorm.DB) error {
+	applied, err := migrations.Applied(tx)
+	if err != nil {
+		return err
+	}
+
+	pending, err := migrations.Pending(tx)
+	if err != nil {
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"VERSION", "NAME", "STATUS", "APPLIED AT"})
+	for _, m := range applied {
+		status, appliedAt := "applied", m.AppliedAt.Format(time.DateTime)
+		if m.Baseline {
+			status = "baseline"
+		}
+
+		if m.AppliedAt.IsZero() {
+			appliedAt = ""
+		}
+
+		t.AppendRow(table.Row{m.Version, m.Name, status, appliedAt})
+	}
+	for _, m := range pending {
+		t.AppendRow(table.Row{m.Version, m.Name, "pending", ""})
+	}
+	t.Render()
+
+	return nil
+}
+
+func runStatus(a *app, args []string) error {
+	if err := noArgs(args); err != nil {
+		return err
+	}
+
+	status, err := a.queue.Status()
+	if err != nil {
+		return fmt.Errorf("Error retrieving queue status: %s", err.Error())
+	}
+
+	var scraped int64
+	if err = a.vlrDb.Table("matches").Count(&scraped).Error; err != nil {
+		return fmt.Errorf("Error counting scraped matches: %s", err.Error())
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"PENDING", "FAILED", "SCRAPED"})
+	t.AppendRow(table.Row{status.Pending, status.Failed, scraped})
+	t.Render()
+
+	if status.Failed == 0 {
+		return nil
+	}
+
+	errs, err := a.queue.Errors()
+	if err != nil {
+		return fmt.Errorf("Error retrieving failures: %s", err.Error())
+	}
+
+	urls := make([]string, 0, len(errs))
+	for matchUrl := range errs {
+		urls = append(urls, matchUrl)
+	}
+	sort.Strings(urls)
+
+	failures := table.NewWriter()
+	failures.SetOutputMirror(os.Stdout)
+	failures.AppendHeader(table.Row{"FAILED MATCH", "ERROR"})
+	for _, matchUrl := range urls {
+		failures.AppendRow(table.Row{matchUrl, errs[matchUrl]})
+	}
+	failures.Render()
+
+	return nil
+}
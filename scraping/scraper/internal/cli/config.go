@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+)
+
+const dateFlagLayout = "2006-01-02"
+
+const (
+	sqliteSink = "sqlite"
+	ndjsonSink = "ndjson"
+	csvSink    = "csv"
+)
+
+// Config of the scraper, every value can be set by a flag or by an environment variable, flags take precedence
+type Config struct {
+	// Path of the sqlite db the scraped data is saved to, VLR_DB_PATH. The reference data (agents, maps, countries
+	// and regions) is always read from it
+	VlrDbPath string
+	// Directory of the queue and page cache dbs, TMP_DIR, the system temp directory by default
+	TmpDir string
+	// Number of matches scraped at the same time, CONCURRENCY, 1 by default. The writes of a match hold the lock of
+	// the vlr db until it is saved, including the fetches of its missing teams, players and tournament, so mostly the
+	// match pages are fetched in parallel
+	Concurrency int
+	// Only the matches played between From and To are crawled and scraped, zero dates are not checked
+	From time.Time
+	To   time.Time
+	// LOG_LEVEL, info by default
+	LogLevel logrus.Level
+	// Where the scraped entities are saved, one of sqliteSink, ndjsonSink or csvSink, SINK, sqlite by default
+	Sink string
+	// Scrape the matches without writing to the vlr db and the queue, a JSON document is emitted per match
+	DryRun bool
+	// Directory of the ndjson and csv files, or of the dry run documents written as <match id>.json, OUT_DIR.
+	// Dry run documents are written to stdout if empty
+	OutDir string
+	// Print the htmlx trace of the match page fields of every scraped match to stderr
+	Trace bool
+}
+
+type flagValues struct {
+	envFile     string
+	vlrDbPath   string
+	tmpDir      string
+	concurrency int
+	from        string
+	to          string
+	logLevel    string
+	sink        string
+	dryRun      bool
+	outDir      string
+	trace       bool
+}
+
+// Create the flag set of the command with the flags shared by every command
+func newFlagSet(name string) (*flag.FlagSet, *flagValues) {
+	var values flagValues
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&values.envFile, "env", ".env", "env file loaded as fallback for the unset flags, ignored if missing")
+	fs.StringVar(&values.vlrDbPath, "db", "", "path of the vlr sqlite db (VLR_DB_PATH)")
+	fs.StringVar(&values.tmpDir, "tmp-dir", "", "directory of the queue and cache dbs (TMP_DIR)")
+	fs.IntVar(&values.concurrency, "concurrency", 0, "number of matches scraped at the same time (CONCURRENCY)")
+	fs.StringVar(&values.from, "from", "", "only crawl and scrape matches played on or after this date, YYYY-MM-DD")
+	fs.StringVar(&values.to, "to", "", "only crawl and scrape matches played on or before this date, YYYY-MM-DD")
+	fs.StringVar(&values.logLevel, "log-level", "", "trace, debug, info, warn or error (LOG_LEVEL)")
+	fs.StringVar(&values.sink, "sink", "", "where the scraped entities are saved, sqlite, ndjson or csv (SINK)")
+	fs.BoolVar(&values.dryRun, "dry-run", false, "emit a JSON document per match instead of saving it, the db and the queue are not modified")
+	fs.BoolVar(&values.trace, "trace", false, "print how every field of the match pages was parsed, to debug the selectors")
+	fs.StringVar(&values.outDir, "out", "", "directory of the ndjson and csv files or of the dry run documents, stdout by default (OUT_DIR)")
+
+	return fs, &values
+}
+
+// Load the env file without overriding the variables already set, a missing file is not an error
+func loadEnvFile(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	if err := godotenv.Load(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logrus.Debugf("Env file %s not found, continue with flags and environment", path)
+			return nil
+		}
+
+		return fmt.Errorf("Error loading env file %s: %s", path, err.Error())
+	}
+
+	return nil
+}
+
+// Return the flag value, or the environment variable if the flag is not set
+func flagOrEnv(flagVal, envKey string) string {
+	if flagVal != "" {
+		return flagVal
+	}
+
+	return os.Getenv(envKey)
+}
+
+func parseDateFlag(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse(dateFlagLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid -%s date '%s', want YYYY-MM-DD", name, value)
+	}
+
+	return date, nil
+}
+
+// Resolve the config from the parsed flags, the env file and the environment
+func (v flagValues) config() (Config, error) {
+	var config Config
+	var err error
+
+	if err = loadEnvFile(v.envFile); err != nil {
+		return config, err
+	}
+
+	config.VlrDbPath = flagOrEnv(v.vlrDbPath, "VLR_DB_PATH")
+
+	config.TmpDir = flagOrEnv(v.tmpDir, "TMP_DIR")
+	if config.TmpDir == "" {
+		config.TmpDir = os.TempDir()
+	}
+
+	config.Concurrency = v.concurrency
+	if config.Concurrency == 0 {
+		config.Concurrency = 1
+
+		if concurrencyStr := os.Getenv("CONCURRENCY"); concurrencyStr != "" {
+			if config.Concurrency, err = strconv.Atoi(concurrencyStr); err != nil {
+				return config, fmt.Errorf("Invalid CONCURRENCY '%s'", concurrencyStr)
+			}
+		}
+	}
+
+	if config.Concurrency < 1 {
+		return config, fmt.Errorf("Concurrency must be at least 1, get %d", config.Concurrency)
+	}
+
+	if config.From, err = parseDateFlag("from", v.from); err != nil {
+		return config, err
+	}
+
+	if config.To, err = parseDateFlag("to", v.to); err != nil {
+		return config, err
+	}
+
+	if !config.From.IsZero() && !config.To.IsZero() && config.To.Before(config.From) {
+		return config, fmt.Errorf("-to date %s is before -from date %s", v.to, v.from)
+	}
+
+	config.Sink = flagOrEnv(v.sink, "SINK")
+	if config.Sink == "" {
+		config.Sink = sqliteSink
+	}
+
+	config.DryRun = v.dryRun
+	config.OutDir = flagOrEnv(v.outDir, "OUT_DIR")
+	config.Trace = v.trace
+
+	switch config.Sink {
+	case sqliteSink:
+		if config.OutDir != "" && !config.DryRun {
+			return config, fmt.Errorf("-out is only used with -dry-run or the ndjson and csv sinks")
+		}
+	case ndjsonSink, csvSink:
+		if config.DryRun {
+			return config, fmt.Errorf("-dry-run can't be used with the %s sink", config.Sink)
+		}
+
+		if config.OutDir == "" {
+			return config, fmt.Errorf("The %s sink needs an output directory, use -out or OUT_DIR", config.Sink)
+		}
+	default:
+		return config, fmt.Errorf("Invalid sink '%s', want sqlite, ndjson or csv", config.Sink)
+	}
+
+	config.LogLevel = logrus.InfoLevel
+	if logLevelStr := flagOrEnv(v.logLevel, "LOG_LEVEL"); logLevelStr != "" {
+		if config.LogLevel, err = logrus.ParseLevel(logLevelStr); err != nil {
+			return config, fmt.Errorf("Invalid log level '%s'", logLevelStr)
+		}
+	}
+
+	return config, nil
+}
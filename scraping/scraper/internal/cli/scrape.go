@@ -0,0 +1,390 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/crawler"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/banpicklog"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/utils/progressbar"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/utils/urlinfo"
+	"github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	vlrBaseUrl = "https://www.vlr.gg"
+	// The scraping pause for pauseDuration every pauseEvery matches to not get rate limited
+	pauseEvery    = 50
+	pauseDuration = 30 * time.Second
+	// Number of pages of /matches crawled for the upcoming matches
+	upcomingPages = 10
+	// A match failing because the vlr db is locked is scraped up to busyAttempts times, waiting one more busyBackoff
+	// after each attempt
+	busyAttempts = 5
+	busyBackoff  = time.Second
+)
+
+// Returned from the dry run transaction so nothing is written to the vlr db
+var errDryRunRollback = errors.New("Dry run rollback")
+
+// The tabs of the match page which are combined and piped to the match handler, in this order
+var matchTabs = []string{"overview", "performance", "economy"}
+
+func fetchMatchTab(fullUrl, tab string) (*goquery.Document, error) {
+	res, err := http.Get(strings.TrimSuffix(fullUrl, "/") + "/?games=all&tab=" + tab)
+	if err != nil {
+		return nil, fmt.Errorf("Error fetching from %s page: %s", tab, err.Error())
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error fetching from %s page: status %d", tab, res.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing %s content: %s", tab, err.Error())
+	}
+
+	return doc, nil
+}
+
+// Fetch the tabs of the match page and combine them
+func fetchMatch(fullUrl string) (*goquery.Selection, error) {
+	var combined *goquery.Selection
+
+	for _, tab := range matchTabs {
+		doc, err := fetchMatchTab(fullUrl, tab)
+		if err != nil {
+			return nil, err
+		}
+
+		if combined == nil {
+			combined = doc.Selection
+		} else {
+			combined = combined.AddSelection(doc.Selection)
+		}
+	}
+
+	return combined, nil
+}
+
+// Return the function piping the match page to the match handler, with the vlr db transaction, the sink and the map
+// pool timeline. The match
+// is added to the veto report if it has no veto note, the report is merged into a.vetoReport once the match is saved.
+// With config.Trace, the trace of the match page fields is printed after the match is scraped
+func (a *app) matchPipe(
+	matchSchema *models.MatchSchema,
+	combined *goquery.Selection,
+	vetoReport *banpicklog.Report,
+) func(tx *gorm.DB, sink sinks.Sink) error {
+	return func(tx *gorm.DB, sink sinks.Sink) error {
+		var trace *htmlx.Trace
+		if a.config.Trace {
+			trace = htmlx.NewTrace()
+			defer a.printTrace(matchSchema.Id, trace)
+		}
+
+		ctx := sinks.WithSink(
+			context.WithValue(
+				context.WithValue(
+					context.WithValue(
+						context.WithValue(context.WithValue(context.Background(), "matchSchema", matchSchema), "tx", tx),
+						"banPickReport",
+						vetoReport,
+					),
+					"htmlxTrace",
+					trace,
+				),
+				"mapPool",
+				a.mapPoolTimeline(),
+			),
+			sink,
+		)
+
+		if err := a.scraper.Pipe(matchSchema.Url, ctx, combined); err != nil {
+			return fmt.Errorf("Error scraping match %d: %s", matchSchema.Id, err.Error())
+		}
+
+		return nil
+	}
+}
+
+// Print the trace of the match as a table to stderr, so it isn't mixed with the dry run documents
+func (a *app) printTrace(matchId int, trace *htmlx.Trace) {
+	t := table.NewWriter()
+	t.SetTitle("Match %d", matchId)
+	t.SetColumnConfigs([]table.ColumnConfig{{Number: 2, WidthMax: 60}, {Number: 7, WidthMax: 40}})
+	t.AppendHeader(table.Row{"FIELD", "SELECTOR", "MATCHES", "SOURCE", "RAW", "PARSER", "RESULT", "SKIPPED"})
+	for _, field := range trace.Fields() {
+		t.AppendRow(table.Row{
+			field.Field,
+			field.DisplaySelector(),
+			field.Matches,
+			field.Source,
+			field.DisplayRaw(),
+			field.Parser,
+			field.DisplayResult(),
+			field.SkipReason,
+		})
+	}
+
+	a.outMu.Lock()
+	defer a.outMu.Unlock()
+
+	fmt.Fprintln(os.Stderr, t.Render())
+}
+
+// Whether sqlite failed to take the lock of the vlr db. The handlers wrap the errors by their message, so the message is
+// checked when the sqlite error is lost
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy
+	}
+
+	return strings.Contains(err.Error(), "database is locked")
+}
+
+// Run scrape again while it fails because another worker holds the write lock of the vlr db. A deferred transaction
+// which has read the db fails right away when it can't take the lock, as waiting could deadlock
+func (a *app) retryBusy(matchId int, scrape func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := scrape()
+		if err == nil || !isBusy(err) || attempt == busyAttempts {
+			return err
+		}
+
+		logrus.Debugf("Vlr db is locked, scraping match %d again (attempt %d)", matchId, attempt+1)
+		time.Sleep(time.Duration(attempt) * busyBackoff)
+	}
+}
+
+// Scrape the match and everything linked to it in one transaction, a zero date is parsed from the match page.
+// With a dry run, the match is written as a document instead
+func (a *app) scrapeMatch(matchId int, fullUrl string, date time.Time) error {
+	logrus.Debugf("Scraping from: %s", fullUrl)
+
+	combined, err := fetchMatch(fullUrl)
+	if err != nil {
+		return err
+	}
+
+	return a.retryBusy(matchId, func() error {
+		vetoReport := &banpicklog.Report{}
+		pipe := a.matchPipe(&models.MatchSchema{Id: matchId, Url: fullUrl, Date: date}, combined, vetoReport)
+
+		if a.config.DryRun {
+			// The match is saved to an empty memory so its teams, tournament and players are scraped as well.
+			// The reference data added to the vlr db while scraping is rolled back, the dry run only takes the write
+			// lock of the vlr db when reference data is discovered
+			doc := sinks.NewMemory()
+
+			if err := a.vlrDb.Transaction(func(tx *gorm.DB) error {
+				if err := pipe(tx, doc); err != nil {
+					return err
+				}
+
+				return errDryRunRollback
+			}); err != errDryRunRollback {
+				return err
+			}
+
+			if err := a.writeDocument(matchId, doc); err != nil {
+				return err
+			}
+
+			a.vetoReport.Merge(vetoReport)
+			return nil
+		}
+
+		if err := a.transaction(pipe); err != nil {
+			return err
+		}
+
+		a.vetoReport.Merge(vetoReport)
+		return nil
+	})
+}
+
+// Scrape the saved match again, its rows are replaced in one transaction and the changes are returned
+func (a *app) rescrapeMatch(rescraper sinks.Rescraper, matchId int, fullUrl string) ([]models.MatchChangeSchema, error) {
+	logrus.Debugf("Scraping again from: %s", fullUrl)
+
+	combined, err := fetchMatch(fullUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	vetoReport := &banpicklog.Report{}
+	pipe := a.matchPipe(&models.MatchSchema{Id: matchId, Url: fullUrl}, combined, vetoReport)
+
+	changes, err := rescraper.Rescrape(matchId, func(sink sinks.Sink) error {
+		return a.withReferenceTx(sink, pipe)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a.vetoReport.Merge(vetoReport)
+	return changes, nil
+}
+
+// Scrape the match which isn't played yet to the scheduled matches, a scheduled match scraped again is replaced
+func (a *app) scrapeScheduledMatch(matchId int, fullUrl string) error {
+	logrus.Debugf("Scraping scheduled match from: %s", fullUrl)
+
+	doc, err := fetchMatchTab(fullUrl, "overview")
+	if err != nil {
+		return err
+	}
+
+	scheduledMatch := models.ScheduledMatchSchema{Id: matchId, Url: fullUrl}
+
+	return a.transaction(func(tx *gorm.DB, sink sinks.Sink) error {
+		ctx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), "scheduledMatch", &scheduledMatch), "tx", tx), sink)
+
+		if err := a.scraper.Pipe("scheduledMatch", ctx, doc.Selection); err != nil {
+			return fmt.Errorf("Error scraping scheduled match %d: %s", matchId, err.Error())
+		}
+
+		return nil
+	})
+}
+
+// Scrape the queued match, it is removed from the queue if it is scraped or already exists and marked as failed otherwise.
+// A dry run scrape the match even if it exists and leave the queue as is
+func (a *app) scrapeQueuedMatch(matchToBeScraped crawler.MatchToBeScraped) error {
+	if a.config.DryRun {
+		urlInfo, err := urlinfo.ExtractUrlInfo(matchToBeScraped.Url)
+		if err != nil || !urlInfo.IsMatch() {
+			return fmt.Errorf("Unable to extract match information from url '%s'", matchToBeScraped.Url)
+		}
+
+		return a.scrapeMatch(urlInfo.Id, vlrBaseUrl+matchToBeScraped.Url, matchToBeScraped.Date)
+	}
+
+	urlInfo, err := urlinfo.ExtractUrlInfo(matchToBeScraped.Url)
+	if err != nil || !urlInfo.IsMatch() {
+		err = fmt.Errorf("Unable to extract match information from url '%s'", matchToBeScraped.Url)
+		if failErr := a.queue.Fail(matchToBeScraped.Url, err); failErr != nil {
+			logrus.Errorf("Error marking match as failed: '%s'", failErr.Error())
+		}
+
+		return err
+	}
+
+	exists, err := a.sink.Exists(sinks.Matches, urlInfo.Id)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		logrus.Debugf("Match %d exists, continue", urlInfo.Id)
+	} else if err = a.scrapeMatch(urlInfo.Id, vlrBaseUrl+matchToBeScraped.Url, matchToBeScraped.Date); err != nil {
+		if failErr := a.queue.Fail(matchToBeScraped.Url, err); failErr != nil {
+			logrus.Errorf("Error marking match as failed: '%s'", failErr.Error())
+		}
+
+		return err
+	}
+
+	if err = a.queue.Done(matchToBeScraped.Url); err != nil {
+		logrus.Errorf("Error deleting match from cache: '%s'", err.Error())
+	}
+
+	return nil
+}
+
+// Scrape the queued matches with config.Concurrency workers and show the progress.
+// It return error if any of the matches failed, the failed matches stay in the queue to be retried
+func (a *app) scrapeQueue(matchesToBeScraped []crawler.MatchToBeScraped) error {
+	if len(matchesToBeScraped) == 0 {
+		logrus.Info("No match to scrape")
+		return nil
+	}
+
+	// The progress bar would be mixed with the documents written to stdout
+	showProgress := !a.config.DryRun || a.config.OutDir != ""
+
+	pb := progressbar.NewPBar()
+	if showProgress {
+		defer pb.CleanUp()
+
+		pb.SignalHandler()
+		pb.SetHeaderText("Matches scraped (0 fails)")
+		pb.SetTotalCount(len(matchesToBeScraped))
+		pb.RenderPBar(0)
+	}
+
+	jobs := make(chan crawler.MatchToBeScraped)
+	results := make(chan error)
+
+	var wg sync.WaitGroup
+	for range a.config.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for matchToBeScraped := range jobs {
+				results <- a.scrapeQueuedMatch(matchToBeScraped)
+			}
+		}()
+	}
+
+	go func() {
+		for i, matchToBeScraped := range matchesToBeScraped {
+			if i%pauseEvery == 0 && i > 0 {
+				logrus.Debugf("Pause for %s", pauseDuration)
+				time.Sleep(pauseDuration)
+			}
+
+			jobs <- matchToBeScraped
+		}
+
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	var scrapedMatch, failedMatch int
+
+	for err := range results {
+		scrapedMatch++
+
+		if err != nil {
+			failedMatch++
+			pb.SetHeaderText(fmt.Sprintf("Matches scraped (%d fails)", failedMatch))
+			logrus.Error(err)
+		}
+
+		if showProgress {
+			pb.RenderPBar(scrapedMatch)
+		}
+	}
+
+	if errs := a.scraper.Errors(); len(errs) > 0 {
+		fmt.Println("=========================== ERROR ===========================")
+		for pattern, err := range errs {
+			logrus.Error(fmt.Errorf("Error from '%s': %s", pattern, err.Error()))
+		}
+	}
+
+	if failedMatch > 0 {
+		return fmt.Errorf("%d of %d matches failed, run retry-failed to scrape them again", failedMatch, scrapedMatch)
+	}
+
+	return nil
+}
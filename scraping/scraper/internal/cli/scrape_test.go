@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/migrations"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/banpicklog"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// The overview tab of the match page, Drift isn't a map of the vlr db so it is discovered while scraping
+const dryRunTestContent = `<html><body>
+	<div class="match-header-note">PRX ban Ascent; FNC ban Drift; PRX pick Haven; FNC pick Lotus; Split remains</div>
+</body></html>`
+
+// Stand in for the match handler, which would also fetch the pages of the teams, the players and the tournament:
+// the match is saved and the veto note is piped to the ban pick log handler
+func dryRunMatchHandler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection) error {
+	matchSchema, ok := ctx.Value("matchSchema").(*models.MatchSchema)
+	if !ok {
+		return fmt.Errorf("Unable to find match schema")
+	}
+
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = sink.SaveMatch(matchSchema); err != nil {
+		return err
+	}
+
+	data := banpicklog.Data{MatchId: matchSchema.Id, Team1Id: 624, Team2Id: 2593, Team1Shorthand: "PRX", Team2Shorthand: "FNC"}
+
+	return sc.Pipe("banPickLog", context.WithValue(ctx, "banPickData", &data), selection.Eq(0).Find("div.match-header-note"))
+}
+
+func TestScrapeMatchDryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("tab") == "overview" {
+			fmt.Fprint(w, dryRunTestContent)
+			return
+		}
+
+		fmt.Fprint(w, "<html><body></body></html>")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	vlrDb, err := gorm.Open(sqlite.Open(path.Join(dir, "vlr.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = migrations.Up(vlrDb); err != nil {
+		t.Fatal(err)
+	}
+
+	sc := piper.NewScraper(nil, nil)
+	sc.Handle(regexp.MustCompile("^"+regexp.QuoteMeta(server.URL)+`/[0-9]+/`), dryRunMatchHandler)
+	sc.Handle(regexp.MustCompile(`^banPickLog$`), banpicklog.Handler)
+
+	outDir := path.Join(dir, "out")
+	if err = os.Mkdir(outDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &app{
+		config:     Config{DryRun: true, OutDir: outDir, Sink: sqliteSink},
+		vlrDb:      vlrDb,
+		sink:       sinks.NewSQLite(vlrDb),
+		scraper:    sc,
+		vetoReport: &banpicklog.Report{},
+	}
+
+	date := time.Date(2025, 6, 22, 0, 0, 0, 0, time.UTC)
+	if err = a.scrapeMatch(498628, server.URL+"/498628/prx-vs-fnc", date); err != nil {
+		t.Fatal(err)
+	}
+
+	dat, err := os.ReadFile(path.Join(outDir, "498628.json"))
+	if err != nil {
+		t.Fatalf("Want the document of the match, get %s", err.Error())
+	}
+
+	var doc sinks.Memory
+	if err = json.Unmarshal(dat, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Matches) != 1 || doc.Matches[0].Id != 498628 || !doc.Matches[0].Date.Equal(date) {
+		t.Errorf("Want match 498628 in the document, get %+v", doc.Matches)
+	}
+
+	if len(doc.BanPickLog) != 5 || doc.BanPickLog[1].MapId == 0 {
+		t.Errorf("Want the 5 turns of the veto in the document, get %+v", doc.BanPickLog)
+	}
+
+	for _, query := range []string{
+		"SELECT count(*) FROM matches",
+		"SELECT count(*) FROM ban_pick_log",
+		"SELECT count(*) FROM maps WHERE name = 'Drift'",
+	} {
+		var count int64
+		if err = vlrDb.Raw(query).Scan(&count).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if count != 0 {
+			t.Errorf("Dry run should not write to the vlr db, '%s' return %d", query, count)
+		}
+	}
+}
+
+func TestIsBusy(t *testing.T) {
+	for _, test := range []struct {
+		err  error
+		want bool
+	}{
+		{sqlite3.Error{Code: sqlite3.ErrBusy}, true},
+		{fmt.Errorf("Error scraping match 498628: %s", sqlite3.Error{Code: sqlite3.ErrBusy}.Error()), true},
+		{sqlite3.Error{Code: sqlite3.ErrConstraint}, false},
+		{fmt.Errorf("Error scraping match 498628: No handler match the pattern: 'banPickLog'"), false},
+	} {
+		if got := isBusy(test.err); got != test.want {
+			t.Errorf("isBusy(%q) want %t, get %t", test.err.Error(), test.want, got)
+		}
+	}
+}
@@ -65,7 +65,9 @@ func createCacheDb(tmpCacheDbPath string) (*sql.DB, error) {
 		return nil, err
 	}
 
-	_, err = db.Exec(initializeScript)
+	if _, err = db.Exec(initializeScript); err != nil {
+		return nil, err
+	}
 
 	return db, nil
 }
@@ -251,70 +253,60 @@ func getDateLimit(tmpCacheDb, vlrDb *sql.DB) (time.Time, error) {
 	return dateLimit, nil
 }
 
-func CrawlMatches(tmpCacheDbPath, vlrDbPath string) ([]MatchToBeScraped, error) {
-	var err error
-
-	logrus.Debug("Connecting to cache db")
-	tmpCacheDb, err := createCacheDb(tmpCacheDbPath)
-	if err != nil {
-		return nil, err
-	}
-	defer tmpCacheDb.Close()
-
-	var matchesToBeScraped []MatchToBeScraped
+// Crawl the results pages for the matches played after the date limit and add them to the queue.
+// The date limit is the latest date of the queue, or of the vlr db if the queue is empty, or DATE_LIMIT if both are.
+// A non zero from replace the date limit and the matches played after a non zero to are skipped
+func FillQueue(queue *Queue, vlrDbPath string, from, to time.Time) ([]MatchToBeScraped, error) {
+	dateLimit := from
 
-	logrus.Debug("Retrieve matches from caches")
-	rows, err := tmpCacheDb.Query("SELECT urL, date FROM matches_to_be_scraped ORDER BY date DESC")
-	if err != nil {
-		return nil, err
-	}
-
-	for rows.Next() {
-		var matchToBeScraped MatchToBeScraped
-		var dateStr string
-
-		if err := rows.Scan(&matchToBeScraped.Url, &dateStr); err != nil {
+	if dateLimit.IsZero() {
+		logrus.Debug("Connecting to vlr db")
+		vlrDb, err := sql.Open("sqlite3", vlrDbPath)
+		if err != nil {
 			return nil, err
 		}
+		defer vlrDb.Close()
 
-		matchToBeScraped.Date, err = time.Parse("2006-01-02 15:04:05+00:00", dateStr)
-		if err != nil {
+		logrus.Debug("Determine the limit date where matches haven't been scraped")
+		if dateLimit, err = getDateLimit(queue.db, vlrDb); err != nil {
 			return nil, err
 		}
-
-		matchesToBeScraped = append(matchesToBeScraped, matchToBeScraped)
 	}
 
-	logrus.Debug("Connecting to vlr db")
-	vlrDb, err := sql.Open("sqlite3", vlrDbPath)
+	logrus.Debugf("Start scraping matches after %s", dateLimit.Format(dateLayout))
+	crawledMatches, err := crawlMatchesUpToDate(dateLimit)
 	if err != nil {
 		return nil, err
 	}
-	defer vlrDb.Close()
 
-	logrus.Debug("Determine the limit date where matches haven't been scraped")
-	dateLimit, err := getDateLimit(tmpCacheDb, vlrDb)
-	if err != nil {
+	var newMatchesToBeScraped []MatchToBeScraped
+	for _, matchToBeScraped := range crawledMatches {
+		if inDateRange(matchToBeScraped.Date, from, to) {
+			newMatchesToBeScraped = append(newMatchesToBeScraped, matchToBeScraped)
+		}
+	}
+
+	logrus.Debug("Insert the matches to cache db")
+	if err = queue.Add(newMatchesToBeScraped); err != nil {
 		return nil, err
 	}
 
-	logrus.Debugf("Start scraping matches after %s", dateLimit.Format(dateLayout))
-	newMatchesToBeScraped, err := crawlMatchesUpToDate(dateLimit)
+	return newMatchesToBeScraped, nil
+}
+
+// Crawl the new matches into the queue and return all the pending matches of the queue
+func CrawlMatches(tmpCacheDbPath, vlrDbPath string) ([]MatchToBeScraped, error) {
+	logrus.Debug("Connecting to cache db")
+	queue, err := OpenQueue(tmpCacheDbPath)
 	if err != nil {
 		return nil, err
 	}
+	defer queue.Close()
 
-	logrus.Debug("Insert the matches to cache db")
-	for _, matchToBeScraped := range newMatchesToBeScraped {
-		if _, err = tmpCacheDb.Exec(
-			"INSERT INTO matches_to_be_scraped(url, date) VALUES(?,?)",
-			matchToBeScraped.Url, matchToBeScraped.Date,
-		); err != nil {
-			return nil, err
-		}
+	if _, err = FillQueue(queue, vlrDbPath, time.Time{}, time.Time{}); err != nil {
+		return nil, err
 	}
 
-	matchesToBeScraped = append(matchesToBeScraped, newMatchesToBeScraped...)
-
-	return matchesToBeScraped, nil
+	logrus.Debug("Retrieve matches from caches")
+	return queue.Pending(time.Time{}, time.Time{})
 }
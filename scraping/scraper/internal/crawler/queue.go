@@ -0,0 +1,192 @@
+package crawler
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Layout of the dates written by the sqlite driver
+const queueDateLayout = "2006-01-02 15:04:05.999999999-07:00"
+
+// Queue is the list of matches waiting to be scraped, stored in the matches_to_be_scraped table of the cache db.
+// Matches which failed to be scraped stay in the queue with their error until they are retried
+type Queue struct {
+	db *sql.DB
+}
+
+// QueueStatus hold the number of matches in the queue
+type QueueStatus struct {
+	Pending int
+	Failed  int
+}
+
+// Open the queue stored in the cache db, the db is created if it doesn't exist
+func OpenQueue(tmpCacheDbPath string) (*Queue, error) {
+	db, err := createCacheDb(tmpCacheDbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Queue{db: db}, nil
+}
+
+func (q *Queue) Close() error {
+	return q.db.Close()
+}
+
+func (q *Queue) queryMatches(failed bool, from, to time.Time) ([]MatchToBeScraped, error) {
+	rows, err := q.db.Query(
+		"SELECT url, date FROM matches_to_be_scraped WHERE failed = ? ORDER BY date DESC",
+		failed,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matchesToBeScraped []MatchToBeScraped
+
+	for rows.Next() {
+		var matchToBeScraped MatchToBeScraped
+		var dateStr string
+
+		if err := rows.Scan(&matchToBeScraped.Url, &dateStr); err != nil {
+			return nil, err
+		}
+
+		matchToBeScraped.Date, err = time.Parse(queueDateLayout, dateStr)
+		if err != nil {
+			return nil, err
+		}
+
+		if !inDateRange(matchToBeScraped.Date, from, to) {
+			continue
+		}
+
+		matchesToBeScraped = append(matchesToBeScraped, matchToBeScraped)
+	}
+
+	return matchesToBeScraped, rows.Err()
+}
+
+// Return the matches waiting to be scraped played between from and to, zero dates are not checked
+func (q *Queue) Pending(from, to time.Time) ([]MatchToBeScraped, error) {
+	return q.queryMatches(false, from, to)
+}
+
+// Return the matches which failed to be scraped played between from and to, zero dates are not checked
+func (q *Queue) Failed(from, to time.Time) ([]MatchToBeScraped, error) {
+	return q.queryMatches(true, from, to)
+}
+
+// Return the queued match with the url, nil if it is not in the queue
+func (q *Queue) Find(url string) (*MatchToBeScraped, error) {
+	var dateStr string
+
+	if err := q.db.QueryRow("SELECT date FROM matches_to_be_scraped WHERE url = ?", url).Scan(&dateStr); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	date, err := time.Parse(queueDateLayout, dateStr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MatchToBeScraped{Url: url, Date: date}, nil
+}
+
+// Add the matches to the queue, matches already queued are ignored
+func (q *Queue) Add(matchesToBeScraped []MatchToBeScraped) error {
+	for _, matchToBeScraped := range matchesToBeScraped {
+		if _, err := q.db.Exec(
+			"INSERT OR IGNORE INTO matches_to_be_scraped(url, date) VALUES(?,?)",
+			matchToBeScraped.Url, matchToBeScraped.Date,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Remove the scraped match from the queue
+func (q *Queue) Done(url string) error {
+	_, err := q.db.Exec("DELETE FROM matches_to_be_scraped WHERE url = ?", url)
+	return err
+}
+
+// Mark the match as failed with the error, it is skipped until retried
+func (q *Queue) Fail(url string, scrapeErr error) error {
+	_, err := q.db.Exec("UPDATE matches_to_be_scraped SET failed = 1, error = ? WHERE url = ?", scrapeErr.Error(), url)
+	return err
+}
+
+// Put the failed matches played between from and to back to pending and return their number
+func (q *Queue) ResetFailed(from, to time.Time) (int, error) {
+	failedMatches, err := q.Failed(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, failedMatch := range failedMatches {
+		if _, err = q.db.Exec(
+			"UPDATE matches_to_be_scraped SET failed = 0, error = NULL WHERE url = ?",
+			failedMatch.Url,
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(failedMatches), nil
+}
+
+// Return the number of pending and failed matches
+func (q *Queue) Status() (QueueStatus, error) {
+	var status QueueStatus
+
+	if err := q.db.QueryRow(
+		"SELECT COUNT(*) FILTER (WHERE failed = 0), COUNT(*) FILTER (WHERE failed = 1) FROM matches_to_be_scraped",
+	).Scan(&status.Pending, &status.Failed); err != nil {
+		return status, err
+	}
+
+	return status, nil
+}
+
+// Return the errors of the failed matches by url
+func (q *Queue) Errors() (map[string]string, error) {
+	rows, err := q.db.Query("SELECT url, COALESCE(error, '') FROM matches_to_be_scraped WHERE failed = 1")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	errs := map[string]string{}
+
+	for rows.Next() {
+		var url, errStr string
+		if err := rows.Scan(&url, &errStr); err != nil {
+			return nil, err
+		}
+
+		errs[url] = errStr
+	}
+
+	return errs, rows.Err()
+}
+
+func inDateRange(date, from, to time.Time) bool {
+	if !from.IsZero() && date.Before(from) {
+		return false
+	}
+
+	if !to.IsZero() && date.After(to) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,78 @@
+package crawler
+
+import (
+	"errors"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	queue, err := OpenQueue(path.Join(t.TempDir(), "vlr_cache.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queue.Close()
+
+	jan := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+
+	if err = queue.Add([]MatchToBeScraped{
+		{Url: "/1/a-vs-b", Date: jan},
+		{Url: "/2/c-vs-d", Date: feb},
+		{Url: "/1/a-vs-b", Date: jan},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := queue.Pending(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 2 || pending[0].Url != "/2/c-vs-d" || !pending[0].Date.Equal(feb) {
+		t.Errorf("Wrong pending matches, get %+v", pending)
+	}
+
+	if pending, err = queue.Pending(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), time.Time{}); err != nil || len(pending) != 1 {
+		t.Errorf("Wrong pending matches from february, get %+v, %v", pending, err)
+	}
+
+	if err = queue.Fail("/1/a-vs-b", errors.New("timeout")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = queue.Done("/2/c-vs-d"); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := queue.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status != (QueueStatus{Pending: 0, Failed: 1}) {
+		t.Errorf("Wrong status, get %+v", status)
+	}
+
+	if errs, err := queue.Errors(); err != nil || errs["/1/a-vs-b"] != "timeout" {
+		t.Errorf("Wrong errors, get %v, %v", errs, err)
+	}
+
+	match, err := queue.Find("/1/a-vs-b")
+	if err != nil || match == nil || !match.Date.Equal(jan) {
+		t.Errorf("Wrong queued match, get %+v, %v", match, err)
+	}
+
+	if match, err = queue.Find("/3/e-vs-f"); err != nil || match != nil {
+		t.Errorf("Match not in the queue should be nil, get %+v, %v", match, err)
+	}
+
+	if n, err := queue.ResetFailed(time.Time{}, time.Time{}); err != nil || n != 1 {
+		t.Errorf("Wrong number of reset matches, get %d, %v", n, err)
+	}
+
+	if status, _ = queue.Status(); status != (QueueStatus{Pending: 1, Failed: 0}) {
+		t.Errorf("Wrong status after reset, get %+v", status)
+	}
+}
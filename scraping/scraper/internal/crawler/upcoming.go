@@ -0,0 +1,92 @@
+package crawler
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/customerrors"
+	"github.com/sirupsen/logrus"
+)
+
+const upcomingPageUrl = "https://www.vlr.gg/matches/?page=%d"
+
+// Return the matches listed on a page of /matches or /matches/results, a page without date label has no matches
+func matchesOnPage(doc *goquery.Document) ([]MatchToBeScraped, error) {
+	var matches []MatchToBeScraped
+
+	for _, dateNode := range doc.Find(dateSelector).EachIter() {
+		// The label of the current and next days end with "Today" or "Tomorrow" in a child node
+		dateStr := strings.TrimSpace(dateNode.Clone().Children().Remove().End().Text())
+		matchDate, err := time.Parse(dateLayout, dateStr)
+		if err != nil {
+			return nil, err
+		}
+
+		matchesContainer := dateNode.Next()
+		if matchesContainer.Length() == 0 {
+			return nil, customerrors.ErrMissingHTMLSelection{Doc: dateNode.Parent()}
+		}
+
+		dateMatches, err := crawlMatchesPerDate(matchesContainer, matchDate)
+		if err != nil {
+			return nil, err
+		}
+
+		matches = append(matches, dateMatches...)
+	}
+
+	return matches, nil
+}
+
+// Crawl the matches which are not played yet from /matches, up to maxPages pages
+func CrawlUpcoming(maxPages int) ([]MatchToBeScraped, error) {
+	var upcoming []MatchToBeScraped
+	var crawlErr error
+	pageMatches := 0
+
+	crawler := colly.NewCollector(colly.AllowedDomains("www.vlr.gg"))
+
+	crawler.OnRequest(func(req *colly.Request) {
+		logrus.Debugf("Crawler visiting: %s", req.URL.String())
+	})
+
+	crawler.OnResponse(func(res *colly.Response) {
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(res.Body))
+		if err != nil {
+			crawlErr = err
+			return
+		}
+
+		matches, err := matchesOnPage(doc)
+		if err != nil {
+			crawlErr = err
+			return
+		}
+
+		pageMatches = len(matches)
+		upcoming = append(upcoming, matches...)
+	})
+
+	for page := 1; page <= maxPages; page++ {
+		pageMatches = 0
+
+		if err := crawler.Visit(fmt.Sprintf(upcomingPageUrl, page)); err != nil {
+			return nil, err
+		}
+
+		if crawlErr != nil {
+			return nil, crawlErr
+		}
+
+		if pageMatches == 0 {
+			break
+		}
+	}
+
+	logrus.Infof("Number of upcoming matches: %d", len(upcoming))
+	return upcoming, nil
+}
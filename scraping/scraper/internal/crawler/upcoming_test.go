@@ -0,0 +1,58 @@
+package crawler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const upcomingPageHtml = `<div id="wrapper"><div class="col-container"><div>
+	<div class="wf-label mod-large">Sun, October 5, 2025 <span class="wf-tag mod-today">Today</span></div>
+	<div class="wf-card">
+		<a href="/542194/paper-rex-vs-fnatic-valorant-champions-2025-gf" class="wf-module-item match-item">PRX vs FNC</a>
+	</div>
+	<div class="wf-label mod-large">Mon, October 6, 2025</div>
+	<div class="wf-card">
+		<a href="/542195/tbd-vs-tbd" class="wf-module-item match-item">TBD vs TBD</a>
+		<a href="/542196/tbd-vs-tbd" class="wf-module-item match-item">TBD vs TBD</a>
+	</div>
+</div></div></div>`
+
+func TestMatchesOnPage(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(upcomingPageHtml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matches, err := matchesOnPage(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []MatchToBeScraped{
+		{"/542194/paper-rex-vs-fnatic-valorant-champions-2025-gf", time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)},
+		{"/542195/tbd-vs-tbd", time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC)},
+		{"/542196/tbd-vs-tbd", time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC)},
+	}
+
+	if len(matches) != len(want) {
+		t.Fatalf("Want %d matches, get %d", len(want), len(matches))
+	}
+
+	for i := range want {
+		if matches[i].Url != want[i].Url || !matches[i].Date.Equal(want[i].Date) {
+			t.Errorf("Want %+v, get %+v", want[i], matches[i])
+		}
+	}
+
+	empty, err := goquery.NewDocumentFromReader(strings.NewReader(`<div id="wrapper"></div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if matches, err = matchesOnPage(empty); err != nil || len(matches) != 0 {
+		t.Errorf("Page without date label should have no matches, get %v, %v", matches, err)
+	}
+}
@@ -1,14 +1,22 @@
 package customparsers
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
-	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/discovery"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/utils/urlinfo"
 	"gorm.io/gorm"
 )
 
+// Register the parsers those don't depend on the scraping context, so they can be used by name in every model
+func init() {
+	htmlx.RegisterParser("idParser", IdParser)
+	htmlx.RegisterFormatter("idParser", IdFormatter)
+	htmlx.RegisterParser("optionalIdParser", OptionalIdParser)
+}
+
 func IdParser(rawVal string) (any, error) {
 	url := strings.TrimSpace(rawVal)
 	vlrUrlInfo, err := urlinfo.ExtractUrlInfo(url)
@@ -19,16 +27,36 @@ func IdParser(rawVal string) (any, error) {
 	return vlrUrlInfo.Id, nil
 }
 
+// Return nil instead of an error if there is no url, e.g for the teams of a scheduled match which are not decided yet
+func OptionalIdParser(rawVal string) (any, error) {
+	if strings.TrimSpace(rawVal) == "" {
+		return nil, nil
+	}
+
+	return IdParser(rawVal)
+}
+
+// Format the id as the shortest url IdParser accept, used to render models with htmlx.Marshal
+func IdFormatter(val any) (string, error) {
+	id, ok := val.(int)
+	if !ok {
+		return "", fmt.Errorf("Id %v is not an int", val)
+	}
+
+	return fmt.Sprintf("/%d/", id), nil
+}
+
+// Return the parser of the id of the map with the name, an unknown map is discovered, see [discovery.MapId]
 func MapIdParser(tx *gorm.DB) htmlx.Parser {
 	return func(rawVal string) (any, error) {
-		mapName := strings.TrimSpace(rawVal)
-		var vlrMap models.MapSchema
-
-		rs := tx.Table("maps").Where("name = ?", mapName).First(&vlrMap)
-		if rs.Error != nil {
-			return -1, rs.Error
-		}
+		return discovery.MapId(tx, rawVal)
+	}
+}
 
-		return vlrMap.Id, nil
+// Return the parser of the id of the agent with the name, e.g "Jett". An unknown agent is discovered, see
+// [discovery.AgentId]
+func AgentIdParser(tx *gorm.DB) htmlx.Parser {
+	return func(rawVal string) (any, error) {
+		return discovery.AgentId(tx, rawVal)
 	}
 }
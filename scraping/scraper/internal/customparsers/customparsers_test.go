@@ -0,0 +1,149 @@
+package customparsers
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+)
+
+func ptr[T any](val T) *T {
+	return &val
+}
+
+// Return the parsers and the formatters of the raw values of every parser tag, they stand in for the parsers of the
+// scrapers which look up the database or need the ids of the teams of the match
+func fixedValues(parserValues map[string]map[string]any) []htmlx.Option {
+	parsers := map[string]htmlx.Parser{}
+	formatters := map[string]htmlx.Formatter{}
+
+	for parserTag, values := range parserValues {
+		parsers[parserTag] = func(rawVal string) (any, error) {
+			val, ok := values[rawVal]
+			if !ok {
+				return nil, fmt.Errorf("unknown raw value '%s' for %s", rawVal, parserTag)
+			}
+
+			return val, nil
+		}
+
+		formatters[parserTag] = func(val any) (string, error) {
+			for rawVal, v := range values {
+				if reflect.DeepEqual(v, val) {
+					return rawVal, nil
+				}
+			}
+
+			return "", fmt.Errorf("unknown value %v for %s", val, parserTag)
+		}
+	}
+
+	return []htmlx.Option{htmlx.SetParsers(parsers), htmlx.SetFormatters(formatters)}
+}
+
+func testRoundTrip(t *testing.T, name string, s any, opts ...htmlx.Option) {
+	content, err := htmlx.Marshal(s, opts...)
+	if err != nil {
+		t.Errorf("%s: %s", name, err.Error())
+		return
+	}
+
+	parsed := reflect.New(reflect.TypeOf(s).Elem())
+	if err = htmlx.ParseFromString(parsed.Interface(), content, opts...); err != nil {
+		t.Errorf("%s: %s\nhtml %s", name, err.Error(), content)
+		return
+	}
+
+	if !reflect.DeepEqual(s, parsed.Interface()) {
+		t.Errorf("%s: round trip changed the value\nwant %+v\nget  %+v\nhtml %s", name, s, parsed.Interface(), content)
+	}
+}
+
+func TestPlayerOverviewStatRoundTrip(t *testing.T) {
+	stat := models.PlayerOverviewStatSchema{
+		PlayerId:    9,
+		AgentId:     3,
+		Rating:      ptr(1.31),
+		Acs:         ptr(251.0),
+		Kills:       ptr(22),
+		Deaths:      ptr(15),
+		Assists:     ptr(4),
+		Kast:        ptr(76.0),
+		Adr:         ptr(160.4),
+		Hs:          ptr(28.0),
+		FirstKills:  ptr(5),
+		FirstDeaths: ptr(2),
+	}
+
+	// Agents are looked up in the database by the scraper, a fixed agent is used instead
+	testRoundTrip(t, "player overview stat", &stat, fixedValues(map[string]map[string]any{"agentParser": {"Sova": 3}})...)
+}
+
+// The models which can't round trip are not tested:
+//   - ScheduledMatchSchema, the selector of ScheduledAt require the data-utc-ts attribute, which Marshal create empty
+//     before the value is rendered to it
+//   - TeamRankingSchema, Wins and Losses are read by two regexes from the same text, which Marshal render only once
+//   - MatchMapSchema, TeamDefFirst is the class of the second score span, which is the span of the score rendered
+//     second rather than the span of the side defending first
+func TestModelsRoundTrip(t *testing.T) {
+	testRoundTrip(t, "player", &models.PlayerSchema{
+		Name:      "TenZ",
+		RealName:  ptr("Tyson Ngo"),
+		ImgUrl:    ptr("//owcdn.net/img/tenz.png"),
+		CountryId: ptr(5),
+	}, fixedValues(map[string]map[string]any{"countryIdParser": {"Canada": 5}})...)
+
+	testRoundTrip(t, "team", &models.TeamSchema{Name: "Sentinels", ShorthandName: ptr("SEN"), ImgUrl: ptr("//owcdn.net/img/sen.png")})
+
+	testRoundTrip(t, "team roster", &models.TeamRosterSchema{PlayerId: 9, Tag: ptr("stand-in")})
+
+	testRoundTrip(t, "tournament", &models.TournamentSchema{Name: "Champions Seoul", PrizePool: 2250000, Location: ptr("Seoul")},
+		fixedValues(map[string]map[string]any{"moneyParser": {"$2,250,000 USD": 2250000}})...)
+
+	testRoundTrip(t, "player agent stat", &models.PlayerAgentStatSchema{
+		AgentId:      3,
+		RoundsPlayed: ptr(1234),
+		Rating:       ptr(1.08),
+		Kast:         ptr(72.0),
+		Hs:           ptr(25.5),
+	}, fixedValues(map[string]map[string]any{"agentParser": {"Sova": 3}})...)
+
+	testRoundTrip(t, "duel kills", &models.DuelKills{Team1PlayerKillsVsTeam2Player: 3})
+	testRoundTrip(t, "duel first kills", &models.DuelFirstKills{Team1PlayerFirstKillsVsTeam2Player: 1, Team2PlayerFirstKillsVsTeam1Player: 2})
+	testRoundTrip(t, "duel op kills", &models.DuelOpKills{Team2PlayerOpKillsVsTeam1Player: 1})
+
+	testRoundTrip(t, "round overview", &models.RoundOverviewSchema{RoundNo: 3, TeamWon: 1, TeamDef: 2, WonMethod: models.Eliminate},
+		fixedValues(map[string]map[string]any{
+			"teamWonParser":   {"rnd-sq mod-win mod-t": 1},
+			"teamDefParser":   {"rnd-sq mod-win mod-t": 2},
+			"wonMethodParser": {"/img/vlr/game/round/elim.webp": models.Eliminate},
+		})...)
+
+	testRoundTrip(t, "round economy", &models.RoundEconomySchema{
+		Team1BuyType: models.Eco,
+		Team2BuyType: models.FullBuy,
+		Team1Bank:    1200,
+		Team2Bank:    12300,
+	}, fixedValues(map[string]map[string]any{"buyTypeParser": {"": models.Eco, "$$$": models.FullBuy}})...)
+
+	// The stage and the event stage and round are parsed from the same text
+	testRoundTrip(t, "match", &models.MatchSchema{
+		TournamentId: 2283,
+		Stage:        models.GrandFinal,
+		Team1Id:      2,
+		Team2Id:      624,
+		Team1Score:   3,
+		Team2Score:   2,
+		Team1Rating:  1850,
+		Team2Rating:  1790,
+		EventStage:   ptr("Playoffs"),
+		EventRound:   ptr("Grand Final"),
+	}, fixedValues(map[string]map[string]any{
+		"stageParser":      {"Playoffs: Grand Final": models.GrandFinal},
+		"eventStageParser": {"Playoffs: Grand Final": "Playoffs"},
+		"eventRoundParser": {"Playoffs: Grand Final": "Grand Final"},
+		"ratingParser":     {"[1850]": 1850, "[1790]": 1790},
+	})...)
+}
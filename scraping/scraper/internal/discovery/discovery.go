@@ -0,0 +1,171 @@
+// Package discovery look up the maps and the agents of the vlr db by name. The ones the game added since the
+// migrations are created as pending with the day they were first seen, so the match they are played in is not lost
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	mapsTable       = "maps"
+	agentsTable     = "agents"
+	agentRolesTable = "agent_roles"
+
+	dateLayout = "2006-01-02"
+)
+
+// Return the day of the discovery, it is also the release date of the discovered map or agent until it is confirmed
+var today = func() string {
+	return time.Now().UTC().Format(dateLayout)
+}
+
+// Create the discovered map or agent, the name is the name of the row
+func create(tx *gorm.DB, table, name string, row any) error {
+	if err := tx.Table(table).Create(row).Error; err != nil {
+		return fmt.Errorf("Error creating %s '%s': %s", strings.TrimSuffix(table, "s"), name, err.Error())
+	}
+
+	logrus.Warnf("Discovered %s '%s', it is saved as pending until confirmed", strings.TrimSuffix(table, "s"), name)
+	return nil
+}
+
+// Return the id of the map with the name, e.g "Corrode". An unknown map is created as pending
+func MapId(tx *gorm.DB, name string) (int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return -1, fmt.Errorf("Unable to look up a map without name")
+	}
+
+	var maps []models.MapSchema
+	if err := tx.Table(mapsTable).Where("name = ?", name).Limit(1).Find(&maps).Error; err != nil {
+		return -1, err
+	}
+
+	if len(maps) > 0 {
+		return maps[0].Id, nil
+	}
+
+	firstSeen := today()
+	vlrMap := models.MapSchema{Name: name, ReleaseDate: firstSeen, Status: models.PendingReference, FirstSeen: &firstSeen}
+
+	if err := create(tx, mapsTable, name, &vlrMap); err != nil {
+		return -1, err
+	}
+
+	return vlrMap.Id, nil
+}
+
+// Return the id of the agent with the name, e.g "Waylay". An unknown agent is created as pending with its role in
+// the agent roles, or the unknown type if it isn't there
+func AgentId(tx *gorm.DB, name string) (int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return -1, fmt.Errorf("Unable to look up an agent without name")
+	}
+
+	var agents []models.AgentSchema
+	if err := tx.Table(agentsTable).Where("name = ?", name).Limit(1).Find(&agents).Error; err != nil {
+		return -1, err
+	}
+
+	if len(agents) > 0 {
+		return agents[0].Id, nil
+	}
+
+	agentType, err := agentRole(tx, name)
+	if err != nil {
+		return -1, err
+	}
+
+	firstSeen := today()
+	agent := models.AgentSchema{Name: name, AgentType: agentType, ReleaseDate: firstSeen, Status: models.PendingReference, FirstSeen: &firstSeen}
+
+	if err := create(tx, agentsTable, name, &agent); err != nil {
+		return -1, err
+	}
+
+	return agent.Id, nil
+}
+
+// Return the role of the agent from the agent roles, the unknown type if it isn't there
+func agentRole(tx *gorm.DB, name string) (models.AgentType, error) {
+	var role models.AgentRoleSchema
+
+	if err := tx.Table(agentRolesTable).Where("name = ? COLLATE NOCASE", name).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.UnknownAgentType, nil
+		}
+
+		return "", fmt.Errorf("Error looking up role of agent '%s': %s", name, err.Error())
+	}
+
+	return role.AgentType, nil
+}
+
+// Return the pending maps and agents, ordered by the day they were first seen
+func Pending(tx *gorm.DB) ([]models.MapSchema, []models.AgentSchema, error) {
+	var maps []models.MapSchema
+	if err := tx.Table(mapsTable).Where("status = ?", models.PendingReference).Order("first_seen, id").Find(&maps).Error; err != nil {
+		return nil, nil, fmt.Errorf("Error loading pending maps: %s", err.Error())
+	}
+
+	var agents []models.AgentSchema
+	if err := tx.Table(agentsTable).Where("status = ?", models.PendingReference).Order("first_seen, id").Find(&agents).Error; err != nil {
+		return nil, nil, fmt.Errorf("Error loading pending agents: %s", err.Error())
+	}
+
+	return maps, agents, nil
+}
+
+// Confirm the pending map or agent, kind is "map" or "agent". The release date replace the day it was first seen
+// if it isn't empty, e.g "2025-06-24". The role of a confirmed agent is read again from the agent roles, so that an
+// agent discovered before its role was added is given its role
+func Confirm(tx *gorm.DB, kind, name, releaseDate string) error {
+	updates := map[string]any{"status": models.ConfirmedReference}
+
+	var table string
+
+	switch kind {
+	case "map":
+		table = mapsTable
+	case "agent":
+		table = agentsTable
+
+		agentType, err := agentRole(tx, name)
+		if err != nil {
+			return err
+		}
+
+		if agentType != models.UnknownAgentType {
+			updates["agent_type"] = agentType
+		}
+	default:
+		return fmt.Errorf("Unable to confirm '%s', want map or agent", kind)
+	}
+
+	if releaseDate != "" {
+		if _, err := time.Parse(dateLayout, releaseDate); err != nil {
+			return fmt.Errorf("Invalid release date '%s', want YYYY-MM-DD", releaseDate)
+		}
+
+		updates["release_date"] = releaseDate
+	}
+
+	rs := tx.Table(table).Where("name = ? AND status = ?", name, models.PendingReference).Updates(updates)
+	if rs.Error != nil {
+		return fmt.Errorf("Error confirming %s '%s': %s", kind, name, rs.Error.Error())
+	}
+
+	if rs.RowsAffected == 0 {
+		return fmt.Errorf("No pending %s named '%s'", kind, name)
+	}
+
+	return nil
+}
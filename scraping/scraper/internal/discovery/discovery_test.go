@@ -0,0 +1,163 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func openReferenceDb(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.Table(mapsTable).AutoMigrate(&models.MapSchema{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.Table(agentsTable).AutoMigrate(&models.AgentSchema{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.Table(agentRolesTable).AutoMigrate(&models.AgentRoleSchema{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.Table(mapsTable).Create(&models.MapSchema{Name: "Ascent", ReleaseDate: "2020-06-02"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.Table(agentsTable).Create(&models.AgentSchema{Name: "Jett", AgentType: models.Duelist, ReleaseDate: "2020-04-07"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.Table(agentRolesTable).Create(&models.AgentRoleSchema{Name: "Veto", AgentType: models.Sentinel}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	originalToday := today
+	today = func() string { return "2025-10-19" }
+	t.Cleanup(func() { today = originalToday })
+
+	return db
+}
+
+func TestKnownReference(t *testing.T) {
+	db := openReferenceDb(t)
+
+	if mapId, err := MapId(db, " Ascent "); err != nil || mapId != 1 {
+		t.Errorf("Want map 1, get %d, %v", mapId, err)
+	}
+
+	if agentId, err := AgentId(db, "Jett"); err != nil || agentId != 1 {
+		t.Errorf("Want agent 1, get %d, %v", agentId, err)
+	}
+
+	maps, agents, err := Pending(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(maps) != 0 || len(agents) != 0 {
+		t.Errorf("Want nothing pending, get %+v, %+v", maps, agents)
+	}
+}
+
+func TestDiscoverReference(t *testing.T) {
+	db := openReferenceDb(t)
+
+	mapId, err := MapId(db, "Corrode")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The discovered map is found again instead of being created twice
+	if againId, err := MapId(db, "Corrode"); err != nil || againId != mapId {
+		t.Errorf("Want map %d again, get %d, %v", mapId, againId, err)
+	}
+
+	if _, err = AgentId(db, "veto"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = AgentId(db, "Newcomer"); err != nil {
+		t.Fatal(err)
+	}
+
+	maps, agents, err := Pending(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(maps) != 1 || maps[0].Id != mapId || maps[0].Name != "Corrode" || maps[0].Status != models.PendingReference ||
+		maps[0].ReleaseDate != "2025-10-19" || maps[0].FirstSeen == nil || *maps[0].FirstSeen != "2025-10-19" {
+		t.Errorf("Want Corrode pending, get %+v", maps)
+	}
+
+	if len(agents) != 2 || agents[0].Name != "veto" || agents[0].AgentType != models.Sentinel ||
+		agents[1].Name != "Newcomer" || agents[1].AgentType != models.UnknownAgentType {
+		t.Errorf("Want veto as sentinel and Newcomer as unknown pending, get %+v", agents)
+	}
+
+	if _, err = MapId(db, "  "); err == nil {
+		t.Error("Want an error for a map without name")
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	db := openReferenceDb(t)
+
+	if _, err := MapId(db, "Corrode"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := AgentId(db, "Newcomer"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Confirm(db, "map", "Corrode", "2025-06-24"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The role added after the discovery is given to the agent when it is confirmed
+	if err := db.Table(agentRolesTable).Create(&models.AgentRoleSchema{Name: "Newcomer", AgentType: models.Controller}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Confirm(db, "agent", "Newcomer", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var corrode models.MapSchema
+	if err := db.Table(mapsTable).Where("name = ?", "Corrode").First(&corrode).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if corrode.Status != models.ConfirmedReference || corrode.ReleaseDate != "2025-06-24" {
+		t.Errorf("Want Corrode confirmed with its release date, get %+v", corrode)
+	}
+
+	var newcomer models.AgentSchema
+	if err := db.Table(agentsTable).Where("name = ?", "Newcomer").First(&newcomer).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if newcomer.Status != models.ConfirmedReference || newcomer.AgentType != models.Controller || newcomer.ReleaseDate != "2025-10-19" {
+		t.Errorf("Want Newcomer confirmed as controller, get %+v", newcomer)
+	}
+
+	if err := Confirm(db, "map", "Corrode", ""); err == nil {
+		t.Error("Want an error for a map which is not pending")
+	}
+
+	if err := Confirm(db, "map", "Ascent", "June 2020"); err == nil {
+		t.Error("Want an error for an invalid release date")
+	}
+
+	if err := Confirm(db, "weapon", "Vandal", ""); err == nil {
+		t.Error("Want an error for an invalid kind")
+	}
+}
@@ -0,0 +1,158 @@
+package mappool
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const day = 24 * time.Hour
+
+// Options of the inference of the pools, see [Infer]
+type Options struct {
+	// A map not played for longer than the gap left the pool
+	Gap time.Duration
+	// The maps played fewer times in a stay are not in the pool, e.g a showmatch on a map out of the pool
+	MinPlays int
+}
+
+var DefaultOptions = Options{Gap: 60 * day, MinPlays: 5}
+
+// A map played in a match on the day
+type Play struct {
+	MapId int
+	Date  time.Time
+}
+
+// The days a map is in the pool, from the first to the last day it is played
+type stay struct {
+	mapId       int
+	first, last time.Time
+	plays       int
+	// The day the map left the pool, zero if it is still in the pool
+	left time.Time
+}
+
+// Load the maps played in the scraped matches, sorted by date
+func loadPlays(tx *gorm.DB) ([]Play, error) {
+	var rows []struct {
+		MapId int
+		Date  string
+	}
+
+	if err := tx.Table("match_maps").
+		Select("match_maps.map_id AS map_id, date(matches.date) AS date").
+		Joins("JOIN matches ON matches.id = match_maps.match_id").
+		Order("date").
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("Error loading played maps: %s", err.Error())
+	}
+
+	plays := make([]Play, 0, len(rows))
+	for _, row := range rows {
+		date, err := time.Parse(dateLayout, row.Date)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid date '%s' of played map %d: %s", row.Date, row.MapId, err.Error())
+		}
+
+		plays = append(plays, Play{MapId: row.MapId, Date: date})
+	}
+
+	return plays, nil
+}
+
+// Return the stays of the maps, a map not played for longer than the gap start a new stay when it is played again
+func stays(plays []Play, opts Options) []stay {
+	playsByMap := map[int][]time.Time{}
+	for _, play := range plays {
+		playsByMap[play.MapId] = append(playsByMap[play.MapId], play.Date)
+	}
+
+	var mapStays []stay
+	for mapId, dates := range playsByMap {
+		slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+
+		current := stay{mapId: mapId, first: dates[0], last: dates[0], plays: 1}
+		for _, date := range dates[1:] {
+			if date.Sub(current.last) > opts.Gap {
+				mapStays = append(mapStays, current)
+				current = stay{mapId: mapId, first: date, last: date}
+			}
+
+			current.last = date
+			current.plays++
+		}
+
+		mapStays = append(mapStays, current)
+	}
+
+	return slices.DeleteFunc(mapStays, func(s stay) bool { return s.plays < opts.MinPlays })
+}
+
+// Infer the pools from the played maps, sorted by date. A map is in the pool from the first to the last day it is
+// played, a map not played for longer than the gap left the pool and is back when it is played again. The pool
+// change on the days a map is played for the first time in a stay. A map leaving the pool is replaced by the next
+// map played for the first time within the gap, or left the day after it was last played if no map replaced it
+func Infer(plays []Play, opts Options) []Pool {
+	mapStays := stays(plays, opts)
+	if len(mapStays) == 0 {
+		return nil
+	}
+
+	var end time.Time
+	firsts := make([]time.Time, 0, len(mapStays))
+	for _, s := range mapStays {
+		if s.last.After(end) {
+			end = s.last
+		}
+
+		firsts = append(firsts, s.first)
+	}
+
+	slices.SortFunc(firsts, func(a, b time.Time) int { return a.Compare(b) })
+
+	changes := slices.Clone(firsts)
+	for i, s := range mapStays {
+		// The map is still in the pool if it was played within the gap of the last match
+		if end.Sub(s.last) <= opts.Gap {
+			continue
+		}
+
+		mapStays[i].left = s.last.Add(day)
+
+		j := sort.Search(len(firsts), func(j int) bool { return firsts[j].After(s.last) })
+		if j < len(firsts) && firsts[j].Sub(s.last) <= opts.Gap {
+			mapStays[i].left = firsts[j]
+		}
+
+		changes = append(changes, mapStays[i].left)
+	}
+
+	slices.SortFunc(changes, func(a, b time.Time) int { return a.Compare(b) })
+	changes = slices.CompactFunc(changes, func(a, b time.Time) bool { return a.Equal(b) })
+
+	var pools []Pool
+	for _, date := range changes {
+		pool := Pool{Date: date, PatchNo: InferredPatch}
+
+		for _, s := range mapStays {
+			if !s.first.After(date) && (s.left.IsZero() || s.left.After(date)) {
+				pool.MapIds = append(pool.MapIds, s.mapId)
+			}
+		}
+
+		slices.Sort(pool.MapIds)
+		pool.MapIds = slices.Compact(pool.MapIds)
+
+		if len(pools) > 0 && slices.Equal(pools[len(pools)-1].MapIds, pool.MapIds) {
+			continue
+		}
+
+		pools = append(pools, pool)
+	}
+
+	return pools
+}
@@ -0,0 +1,75 @@
+package mappool
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func date(dateStr string) time.Time {
+	d, err := time.Parse(dateLayout, dateStr)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// Return the plays of the map, once a week from the first to the last day
+func weekly(mapId int, first, last string) []Play {
+	var plays []Play
+	for d := date(first); !d.After(date(last)); d = d.Add(7 * day) {
+		plays = append(plays, Play{MapId: mapId, Date: d})
+	}
+
+	return plays
+}
+
+func TestInfer(t *testing.T) {
+	var plays []Play
+	// Map 1 leave the pool without replacement the day after its last play
+	plays = append(plays, weekly(1, "2025-01-08", "2025-06-20")...)
+	plays = append(plays, weekly(2, "2025-01-08", "2025-09-30")...)
+	// Map 3 is replaced by map 4
+	plays = append(plays, weekly(3, "2025-01-08", "2025-04-20")...)
+	plays = append(plays, weekly(4, "2025-05-02", "2025-09-30")...)
+	// A showmatch on map 5 which isn't in the pool
+	plays = append(plays, Play{MapId: 5, Date: date("2025-03-01")})
+
+	pools := Infer(plays, Options{Gap: 60 * day, MinPlays: 3})
+
+	wants := []Pool{
+		{Date: date("2025-01-08"), MapIds: []int{1, 2, 3}},
+		{Date: date("2025-05-02"), MapIds: []int{1, 2, 4}},
+		{Date: date("2025-06-19"), MapIds: []int{2, 4}},
+	}
+
+	if len(pools) != len(wants) {
+		t.Fatalf("Want %d pools, get %+v", len(wants), pools)
+	}
+
+	for i, pool := range pools {
+		if !pool.Date.Equal(wants[i].Date) || pool.PatchNo != InferredPatch || !slices.Equal(pool.MapIds, wants[i].MapIds) {
+			t.Errorf("Want pool %+v, get %+v", wants[i], pool)
+		}
+	}
+}
+
+func TestInferReturningMap(t *testing.T) {
+	var plays []Play
+	plays = append(plays, weekly(1, "2024-01-09", "2025-09-30")...)
+	// Map 2 is out of the pool between its stays
+	plays = append(plays, weekly(2, "2024-01-09", "2024-05-31")...)
+	plays = append(plays, weekly(2, "2025-01-08", "2025-09-30")...)
+
+	pools := Infer(plays, Options{Gap: 60 * day, MinPlays: 3})
+
+	if len(pools) != 3 || !slices.Equal(pools[0].MapIds, []int{1, 2}) || !pools[1].Date.Equal(date("2024-05-29")) ||
+		!slices.Equal(pools[1].MapIds, []int{1}) || !pools[2].Date.Equal(date("2025-01-08")) || !slices.Equal(pools[2].MapIds, []int{1, 2}) {
+		t.Errorf("Want map 2 to leave and come back, get %+v", pools)
+	}
+
+	if pools := Infer(nil, DefaultOptions); pools != nil {
+		t.Errorf("Want no pool without plays, get %+v", pools)
+	}
+}
@@ -0,0 +1,205 @@
+// Package mappool maintain the timeline of the competitive map pool of the vlr db. The pools are inferred from the
+// maps of the scraped matches and corrected by the overrides, see [Update]
+package mappool
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	mapsTable     = "maps"
+	mapsPoolTable = "maps_pool"
+	dateLayout    = "2006-01-02"
+
+	// The patch of the pools inferred from the matches, an override give the patch of the pool
+	InferredPatch = "inferred"
+)
+
+// The map pool from the date until the date of the next pool
+type Pool struct {
+	Date    time.Time
+	PatchNo string
+	// The ids of the maps, sorted
+	MapIds []int
+}
+
+// Return true if the pool has the map
+func (p Pool) Has(mapId int) bool {
+	_, found := slices.BinarySearch(p.MapIds, mapId)
+	return found
+}
+
+// Timeline is the map pools sorted by date
+type Timeline struct {
+	pools []Pool
+}
+
+// Create the timeline from the rows of the maps pool, the rows of a date are the pool of the date
+func NewTimeline(rows []models.MapPoolSchema) (*Timeline, error) {
+	poolsByDate := map[string]*Pool{}
+
+	for _, row := range rows {
+		pool, ok := poolsByDate[row.Date]
+		if !ok {
+			date, err := time.Parse(dateLayout, row.Date)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid map pool date '%s': %s", row.Date, err.Error())
+			}
+
+			pool = &Pool{Date: date, PatchNo: row.PatchNo}
+			poolsByDate[row.Date] = pool
+		}
+
+		pool.MapIds = append(pool.MapIds, row.MapId)
+	}
+
+	t := &Timeline{}
+	for _, pool := range poolsByDate {
+		slices.Sort(pool.MapIds)
+		pool.MapIds = slices.Compact(pool.MapIds)
+		t.pools = append(t.pools, *pool)
+	}
+
+	sort.Slice(t.pools, func(i, j int) bool { return t.pools[i].Date.Before(t.pools[j].Date) })
+
+	return t, nil
+}
+
+// Load the timeline from the maps pool of the db
+func Load(tx *gorm.DB) (*Timeline, error) {
+	var rows []models.MapPoolSchema
+
+	if err := tx.Table(mapsPoolTable).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("Error loading maps pool: %s", err.Error())
+	}
+
+	return NewTimeline(rows)
+}
+
+// Return the pools of the timeline, sorted by date
+func (t *Timeline) Pools() []Pool {
+	return slices.Clone(t.pools)
+}
+
+// Return the pool in effect on the date, false if the date is before the first pool
+func (t *Timeline) At(date time.Time) (Pool, bool) {
+	i := sort.Search(len(t.pools), func(i int) bool { return t.pools[i].Date.After(date) })
+	if i == 0 {
+		return Pool{}, false
+	}
+
+	return t.pools[i-1], true
+}
+
+// Return the ids of the maps of the pool in effect on the date, nil if the date is before the first pool
+func MapPoolAt(tx *gorm.DB, date time.Time) ([]int, error) {
+	t, err := Load(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, _ := t.At(date)
+	return pool.MapIds, nil
+}
+
+// Save the pool, replacing the pool of the same date
+func save(tx *gorm.DB, pool Pool) error {
+	date := pool.Date.Format(dateLayout)
+
+	if err := tx.Table(mapsPoolTable).Where("date = ?", date).Delete(&models.MapPoolSchema{}).Error; err != nil {
+		return fmt.Errorf("Error deleting map pool of %s: %s", date, err.Error())
+	}
+
+	rows := make([]models.MapPoolSchema, 0, len(pool.MapIds))
+	for _, mapId := range pool.MapIds {
+		rows = append(rows, models.MapPoolSchema{Date: date, PatchNo: pool.PatchNo, MapId: mapId})
+	}
+
+	if err := tx.Table(mapsPoolTable).Create(&rows).Error; err != nil {
+		return fmt.Errorf("Error saving map pool of %s: %s", date, err.Error())
+	}
+
+	return nil
+}
+
+// Update the maps pool of the db with the overrides and the pools inferred from the matches played after the last
+// pool, see [Infer]. An inferred pool is skipped if it is the pool in effect on its date, or if an override has the
+// same maps within the gap of the options. Return the saved pools, sorted by date
+func Update(tx *gorm.DB, overrides []Pool, opts Options) ([]Pool, error) {
+	t, err := Load(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var last time.Time
+	if len(t.pools) > 0 {
+		last = t.pools[len(t.pools)-1].Date
+	}
+
+	plays, err := loadPlays(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &Timeline{pools: t.pools}
+	saved := &Timeline{}
+
+	for _, override := range overrides {
+		merged.put(override)
+		saved.put(override)
+	}
+
+	for _, pool := range Infer(plays, opts) {
+		if !pool.Date.After(last) || overridden(pool, overrides, opts.Gap) {
+			continue
+		}
+
+		if current, ok := merged.At(pool.Date); ok && slices.Equal(current.MapIds, pool.MapIds) {
+			continue
+		}
+
+		merged.put(pool)
+		saved.put(pool)
+	}
+
+	for _, pool := range saved.pools {
+		if err := save(tx, pool); err != nil {
+			return nil, err
+		}
+	}
+
+	return saved.pools, nil
+}
+
+// Put the pool in the timeline, replacing the pool of the same date
+func (t *Timeline) put(pool Pool) {
+	i := sort.Search(len(t.pools), func(i int) bool { return !t.pools[i].Date.Before(pool.Date) })
+	if i < len(t.pools) && t.pools[i].Date.Equal(pool.Date) {
+		t.pools[i] = pool
+		return
+	}
+
+	t.pools = slices.Insert(slices.Clone(t.pools), i, pool)
+}
+
+// Return true if an override has the maps of the inferred pool within the gap of its date
+func overridden(pool Pool, overrides []Pool, gap time.Duration) bool {
+	for _, override := range overrides {
+		diff := pool.Date.Sub(override.Date)
+		if diff < 0 {
+			diff = -diff
+		}
+
+		if diff <= gap && slices.Equal(override.MapIds, pool.MapIds) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,158 @@
+package mappool
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func openPoolDb(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, stmt := range []string{
+		"CREATE TABLE maps (id INTEGER PRIMARY KEY, name TEXT UNIQUE NOT NULL, release_date TEXT NOT NULL, status TEXT NOT NULL DEFAULT 'confirmed', first_seen TEXT)",
+		"CREATE TABLE maps_pool (date TEXT NOT NULL, patch_no TEXT NOT NULL, map_id INTEGER NOT NULL)",
+		"CREATE TABLE matches (id INTEGER PRIMARY KEY, date TEXT NOT NULL)",
+		"CREATE TABLE match_maps (match_id INTEGER NOT NULL, map_id INTEGER NOT NULL)",
+		"INSERT INTO maps (id, name, release_date) VALUES (1, 'Ascent', '2020-06-02'), (2, 'Bind', '2020-06-02'), (3, 'Haven', '2020-06-02'), (4, 'Corrode', '2025-06-24')",
+		"INSERT INTO maps_pool (date, patch_no, map_id) VALUES ('2025-01-08', '10.00', 1), ('2025-01-08', '10.00', 2), ('2025-01-08', '10.00', 3)",
+	} {
+		if err = db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return db
+}
+
+func TestTimelineAt(t *testing.T) {
+	timeline, err := NewTimeline([]models.MapPoolSchema{
+		{Date: "2025-03-04", PatchNo: "10.04", MapId: 2},
+		{Date: "2025-01-08", PatchNo: "10.00", MapId: 3},
+		{Date: "2025-01-08", PatchNo: "10.00", MapId: 1},
+		{Date: "2025-03-04", PatchNo: "10.04", MapId: 4},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := timeline.At(date("2025-01-07")); ok {
+		t.Error("Want no pool before the first pool")
+	}
+
+	if pool, ok := timeline.At(date("2025-03-03")); !ok || pool.PatchNo != "10.00" || !slices.Equal(pool.MapIds, []int{1, 3}) {
+		t.Errorf("Want the pool of 10.00, get %+v", pool)
+	}
+
+	if pool, ok := timeline.At(date("2025-03-04")); !ok || pool.PatchNo != "10.04" || !pool.Has(4) || pool.Has(1) {
+		t.Errorf("Want the pool of 10.04 from its date, get %+v", pool)
+	}
+
+	if _, err = NewTimeline([]models.MapPoolSchema{{Date: "March 2025", MapId: 1}}); err == nil {
+		t.Error("Want an error for an invalid date")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	db := openPoolDb(t)
+
+	// Corrode replace Haven, the matches before the last pool are not inferred again
+	matchId := 0
+	for _, play := range append(append(weekly(1, "2024-10-01", "2025-09-30"), weekly(3, "2024-10-01", "2025-06-20")...), weekly(4, "2025-07-01", "2025-09-30")...) {
+		matchId++
+		if err := db.Exec("INSERT INTO matches (id, date) VALUES (?, ?)", matchId, play.Date.Format("2006-01-02 15:04:05+00:00")).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if err := db.Exec("INSERT INTO match_maps (match_id, map_id) VALUES (?, ?)", matchId, play.MapId).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	saved, err := Update(db, nil, Options{Gap: 60 * day, MinPlays: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(saved) != 1 || !saved[0].Date.Equal(date("2025-07-01")) || saved[0].PatchNo != InferredPatch || !slices.Equal(saved[0].MapIds, []int{1, 4}) {
+		t.Fatalf("Want the rotation of Corrode, get %+v", saved)
+	}
+
+	// The override of the rotation replace the inferred pool
+	overrides, err := ResolveOverrides(db, []Override{{Date: "2025-06-24", Patch: "11.00", Maps: []string{"Ascent", "Corrode"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.Exec("DELETE FROM maps_pool WHERE date = '2025-07-01'").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if saved, err = Update(db, overrides, Options{Gap: 60 * day, MinPlays: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(saved) != 1 || saved[0].PatchNo != "11.00" {
+		t.Errorf("Want only the override, get %+v", saved)
+	}
+
+	mapIds, err := MapPoolAt(db, date("2025-08-01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(mapIds, []int{1, 4}) {
+		t.Errorf("Want Ascent and Corrode in the pool, get %v", mapIds)
+	}
+}
+
+func TestReadOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+	if err := os.WriteFile(path, []byte("- date: 2025-06-24\n  patch: \"11.00\"\n  maps: [Ascent, Corrode]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	overrides, err := ReadOverrides(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(overrides) != 1 || overrides[0].Date != "2025-06-24" || overrides[0].Patch != "11.00" || !slices.Equal(overrides[0].Maps, []string{"Ascent", "Corrode"}) {
+		t.Errorf("Wrong overrides %+v", overrides)
+	}
+
+	if _, err = ResolveOverrides(openPoolDb(t), []Override{{Date: "2025-06-24", Maps: []string{"Ascent"}}}); err == nil {
+		t.Error("Want an error for an override without patch")
+	}
+}
+
+// The maps of the overrides are not discovered, a typo or a pending map fail
+func TestResolveUnknownMaps(t *testing.T) {
+	db := openPoolDb(t)
+	if err := db.Exec("INSERT INTO maps (id, name, release_date, status) VALUES (5, 'Drift', '2025-06-24', 'pending')").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mapName := range []string{"Corode", "Drift"} {
+		if _, err := ResolveOverrides(db, []Override{{Date: "2025-06-24", Patch: "11.00", Maps: []string{"Ascent", mapName}}}); err == nil {
+			t.Errorf("Want an error for the override with map '%s'", mapName)
+		}
+	}
+
+	var count int64
+	if err := db.Raw("SELECT count(*) FROM maps").Scan(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 5 {
+		t.Errorf("Resolving the overrides should not create maps, get %d maps", count)
+	}
+}
@@ -0,0 +1,92 @@
+package mappool
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// A pool of the overrides file, the maps are the names of the whole pool from the date, e.g
+//
+//	# Corrode replace Pearl and Split
+//	- date: 2025-06-24
+//	  patch: "11.00"
+//	  maps: [Ascent, Bind, Corrode, Haven, Icebox, Lotus, Sunset]
+type Override struct {
+	Date  string   `yaml:"date"`
+	Patch string   `yaml:"patch"`
+	Maps  []string `yaml:"maps"`
+}
+
+// Read the overrides of the yaml file
+func ReadOverrides(path string) ([]Override, error) {
+	dat, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading map pool overrides: %s", err.Error())
+	}
+
+	var overrides []Override
+	if err = yaml.Unmarshal(dat, &overrides); err != nil {
+		return nil, fmt.Errorf("Error parsing map pool overrides: %s", err.Error())
+	}
+
+	return overrides, nil
+}
+
+// Return the id of the confirmed map with the name. A typo of the overrides would otherwise be discovered as a new map,
+// a pending map is confirmed with the confirm command first
+func confirmedMapId(tx *gorm.DB, name string) (int, error) {
+	var maps []models.MapSchema
+	if err := tx.Table(mapsTable).Where("name = ?", strings.TrimSpace(name)).Limit(1).Find(&maps).Error; err != nil {
+		return -1, err
+	}
+
+	if len(maps) == 0 {
+		return -1, fmt.Errorf("Unknown map")
+	}
+
+	if maps[0].Status != models.ConfirmedReference {
+		return -1, fmt.Errorf("Map is %s, confirm it first", maps[0].Status)
+	}
+
+	return maps[0].Id, nil
+}
+
+// Return the pools of the overrides, the maps are looked up by name and must be confirmed maps of the db
+func ResolveOverrides(tx *gorm.DB, overrides []Override) ([]Pool, error) {
+	pools := make([]Pool, 0, len(overrides))
+
+	for _, override := range overrides {
+		date, err := time.Parse(dateLayout, override.Date)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid map pool override date '%s', want YYYY-MM-DD", override.Date)
+		}
+
+		if override.Patch == "" || len(override.Maps) == 0 {
+			return nil, fmt.Errorf("Map pool override of %s needs the patch and the maps", override.Date)
+		}
+
+		pool := Pool{Date: date, PatchNo: override.Patch}
+		for _, mapName := range override.Maps {
+			mapId, err := confirmedMapId(tx, mapName)
+			if err != nil {
+				return nil, fmt.Errorf("Error looking up map '%s' of map pool override of %s: %s", mapName, override.Date, err.Error())
+			}
+
+			pool.MapIds = append(pool.MapIds, mapId)
+		}
+
+		slices.Sort(pool.MapIds)
+		pool.MapIds = slices.Compact(pool.MapIds)
+
+		pools = append(pools, pool)
+	}
+
+	return pools, nil
+}
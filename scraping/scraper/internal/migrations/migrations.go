@@ -0,0 +1,251 @@
+// Package migrations apply the versioned migrations of the vlr db schema, they are embedded sql files named after
+// their version, e.g "0002_match_changes.sql". The migrations are forward only and each is applied in a transaction
+// and recorded in the schema migrations table
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const schemaMigrationsTable = "schema_migrations"
+
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+var (
+	fileNameRegex    = regexp.MustCompile(`^([0-9]+)_([a-z0-9_]+)\.sql$`)
+	createTableRegex = regexp.MustCompile(`(?i)CREATE TABLE IF NOT EXISTS (\w+)`)
+	addColumnRegex   = regexp.MustCompile(`(?i)ALTER TABLE (\w+) ADD COLUMN (\w+)`)
+)
+
+// A migration of the schema
+type Migration struct {
+	Version int
+	Name    string
+	Sql     string
+}
+
+// A migration recorded in the schema migrations table
+type SchemaMigrationSchema struct {
+	Version   int       `gorm:"column:version;primaryKey"`
+	Name      string    `gorm:"column:name"`
+	AppliedAt time.Time `gorm:"column:applied_at;type:datetime"`
+	// The migration was found in the schema of a database created before the migrations and isn't applied
+	Baseline bool `gorm:"column:baseline"`
+}
+
+// Return the embedded migrations, sorted by version
+func All() ([]Migration, error) {
+	entries, err := sqlFiles.ReadDir("sql")
+	if err != nil {
+		return nil, err
+	}
+
+	var all []Migration
+	for _, entry := range entries {
+		matches := fileNameRegex.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("Invalid migration file name '%s', want <version>_<name>.sql", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(matches[1])
+
+		dat, err := sqlFiles.ReadFile(path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, Migration{Version: version, Name: matches[2], Sql: string(dat)})
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+
+	for i := 1; i < len(all); i++ {
+		if all[i].Version == all[i-1].Version {
+			return nil, fmt.Errorf("Migrations %s and %s have the same version %d", all[i-1].Name, all[i].Name, all[i].Version)
+		}
+	}
+
+	return all, nil
+}
+
+func hasTable(tx *gorm.DB, table string) (bool, error) {
+	var count int64
+	err := tx.Raw("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&count).Error
+	return count > 0, err
+}
+
+func hasColumn(tx *gorm.DB, table, column string) (bool, error) {
+	var count int64
+	err := tx.Raw("SELECT count(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count).Error
+	return count > 0, err
+}
+
+// Return true if the tables and the columns the migration create are in the schema
+func (m Migration) inSchema(tx *gorm.DB) (bool, error) {
+	for _, matches := range createTableRegex.FindAllStringSubmatch(m.Sql, -1) {
+		if ok, err := hasTable(tx, matches[1]); err != nil || !ok {
+			return false, err
+		}
+	}
+
+	for _, matches := range addColumnRegex.FindAllStringSubmatch(m.Sql, -1) {
+		if ok, err := hasColumn(tx, matches[1], matches[2]); err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Return the migrations of the schema of a database without the schema migrations table, the database was created
+// by the old setup.sql. They are the migrations in order whose tables and columns are all in the schema
+func detectBaseline(tx *gorm.DB, all []Migration) ([]Migration, error) {
+	var baseline []Migration
+
+	for _, m := range all {
+		ok, err := m.inSchema(tx)
+		if err != nil {
+			return nil, fmt.Errorf("Error detecting migration %d: %s", m.Version, err.Error())
+		}
+
+		if !ok {
+			break
+		}
+
+		baseline = append(baseline, m)
+	}
+
+	return baseline, nil
+}
+
+// Return the recorded migrations, sorted by version. A database without the schema migrations table has the
+// migrations detected in its schema, they are not recorded
+func Applied(tx *gorm.DB) ([]SchemaMigrationSchema, error) {
+	ok, err := hasTable(tx, schemaMigrationsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		var applied []SchemaMigrationSchema
+		if err := tx.Table(schemaMigrationsTable).Order("version").Find(&applied).Error; err != nil {
+			return nil, fmt.Errorf("Error loading schema migrations: %s", err.Error())
+		}
+
+		return applied, nil
+	}
+
+	all, err := All()
+	if err != nil {
+		return nil, err
+	}
+
+	baseline, err := detectBaseline(tx, all)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]SchemaMigrationSchema, 0, len(baseline))
+	for _, m := range baseline {
+		applied = append(applied, SchemaMigrationSchema{Version: m.Version, Name: m.Name, Baseline: true})
+	}
+
+	return applied, nil
+}
+
+// Return the migrations which are not applied, sorted by version
+func Pending(tx *gorm.DB) ([]Migration, error) {
+	all, err := All()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := Applied(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := map[int]bool{}
+	for _, m := range applied {
+		versions[m.Version] = true
+	}
+
+	var pending []Migration
+	for _, m := range all {
+		if !versions[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+
+	return pending, nil
+}
+
+// Create the schema migrations table, the migrations detected in the schema of the database are recorded as the
+// baseline
+func setup(db *gorm.DB) error {
+	ok, err := hasTable(db, schemaMigrationsTable)
+	if err != nil || ok {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		baseline, err := Applied(tx)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`CREATE TABLE schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at DATETIME NOT NULL,
+    baseline INTEGER NOT NULL DEFAULT 0
+)`).Error; err != nil {
+			return fmt.Errorf("Error creating schema migrations table: %s", err.Error())
+		}
+
+		now := time.Now().UTC()
+		for i := range baseline {
+			baseline[i].AppliedAt = now
+			if err := tx.Table(schemaMigrationsTable).Create(&baseline[i]).Error; err != nil {
+				return fmt.Errorf("Error recording baseline migration %d: %s", baseline[i].Version, err.Error())
+			}
+		}
+
+		return nil
+	})
+}
+
+// Apply the pending migrations in order, each in a transaction. Return the applied migrations
+func Up(db *gorm.DB) ([]Migration, error) {
+	if err := setup(db); err != nil {
+		return nil, err
+	}
+
+	pending, err := Pending(db)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, m := range pending {
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(m.Sql).Error; err != nil {
+				return err
+			}
+
+			return tx.Table(schemaMigrationsTable).Create(&SchemaMigrationSchema{Version: m.Version, Name: m.Name, AppliedAt: time.Now().UTC()}).Error
+		}); err != nil {
+			return pending[:i], fmt.Errorf("Error applying migration %d %s: %s", m.Version, m.Name, err.Error())
+		}
+	}
+
+	return pending, nil
+}
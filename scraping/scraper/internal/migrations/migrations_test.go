@@ -0,0 +1,161 @@
+package migrations
+
+import (
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func openDb(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every connection to :memory: open another database
+	sqlDb, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDb.SetMaxOpenConns(1)
+
+	return db
+}
+
+// Create the schema of the old setup.sql of the migrations up to the version, without the schema migrations table
+func execUpTo(t *testing.T, db *gorm.DB, version int) {
+	all, err := All()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range all {
+		if m.Version > version {
+			break
+		}
+
+		if err := db.Exec(m.Sql).Error; err != nil {
+			t.Fatalf("Error executing migration %d: %s", m.Version, err.Error())
+		}
+	}
+}
+
+func TestAll(t *testing.T) {
+	all, err := All()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(all) == 0 || all[0].Version != 1 || all[0].Name != "baseline" {
+		t.Fatalf("Want the baseline first, get %+v", all)
+	}
+
+	for i, m := range all {
+		if m.Version != i+1 {
+			t.Errorf("Want migration %d, get %d %s", i+1, m.Version, m.Name)
+		}
+	}
+}
+
+func TestUpEmptyDb(t *testing.T) {
+	db := openDb(t)
+
+	all, err := All()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := Up(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(applied) != len(all) {
+		t.Fatalf("Want every migration applied, get %d of %d", len(applied), len(all))
+	}
+
+	if applied, err = Up(db); err != nil || len(applied) != 0 {
+		t.Errorf("Want nothing to apply, get %+v, %v", applied, err)
+	}
+
+	recorded, err := Applied(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorded) != len(all) || recorded[0].Baseline || recorded[0].AppliedAt.IsZero() {
+		t.Errorf("Want every migration recorded as applied, get %+v", recorded)
+	}
+
+	if ok, err := hasTable(db, "agent_roles"); err != nil || !ok {
+		t.Errorf("Want the agent roles table, get %v, %v", ok, err)
+	}
+}
+
+func TestUpBaselineDb(t *testing.T) {
+	db := openDb(t)
+	execUpTo(t, db, 1)
+
+	if err := db.Exec("INSERT INTO tournaments (id, name, url, prize_pool, tier_1) VALUES (1, 'Champions', '/event/1/', 1000000, 1), (2, 'Cup', '/event/2/', 0, 0)").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := Pending(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) == 0 || pending[0].Version != 2 {
+		t.Fatalf("Want the migrations after the baseline pending, get %+v", pending)
+	}
+
+	if _, err = Up(db); err != nil {
+		t.Fatal(err)
+	}
+
+	recorded, err := Applied(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !recorded[0].Baseline || recorded[1].Baseline {
+		t.Errorf("Want only the baseline detected, get %+v", recorded)
+	}
+
+	var tiers []string
+	if err = db.Raw("SELECT tier FROM tournaments ORDER BY id").Scan(&tiers).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tiers) != 2 || tiers[0] != "tier_1" || tiers[1] != "unclassified" {
+		t.Errorf("Want the tier_1 flag migrated to the tier, get %v", tiers)
+	}
+
+	var agents int64
+	if err = db.Raw("SELECT count(*) FROM agents WHERE status = 'confirmed'").Scan(&agents).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if agents == 0 {
+		t.Error("Want the agents kept when the table is rebuilt")
+	}
+
+	if err = db.Exec("INSERT INTO agents (name, agent_type, release_date, status) VALUES ('Newcomer', 'unknown', '2025-10-19', 'pending')").Error; err != nil {
+		t.Errorf("Want an agent of unknown type, get %s", err.Error())
+	}
+}
+
+func TestDetectBaseline(t *testing.T) {
+	db := openDb(t)
+	execUpTo(t, db, 5)
+
+	applied, err := Applied(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(applied) != 5 || applied[4].Version != 5 || !applied[4].Baseline {
+		t.Errorf("Want the migrations up to 5 detected, get %+v", applied)
+	}
+}
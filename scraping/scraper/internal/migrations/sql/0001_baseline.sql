@@ -1,4 +1,5 @@
-DROP TABLE IF EXISTS matches;
+-- The schema and the reference data the vlr db started with, the databases created by the old setup.sql are
+-- detected as this baseline
 
 
 CREATE TABLE IF NOT EXISTS matches (
@@ -21,9 +22,6 @@ CREATE TABLE IF NOT EXISTS matches (
 );
 
 
-DROP TABLE IF EXISTS match_maps;
-
-
 CREATE TABLE IF NOT EXISTS match_maps (
     match_id INTEGER NOT NULL,
     map_id INTEGER NOT NULL,
@@ -47,9 +45,6 @@ CREATE TABLE IF NOT EXISTS match_maps (
 );
 
 
-DROP TABLE IF EXISTS round_stats;
-
-
 CREATE TABLE IF NOT EXISTS round_stats (
     match_id INTEGER NOT NULL,
     map_id INTEGER NOT NULL,
@@ -95,9 +90,6 @@ CREATE TABLE IF NOT EXISTS round_stats (
 );
 
 
-DROP TABLE IF EXISTS players_duel_stats;
-
-
 CREATE TABLE IF NOT EXISTS players_duel_stats (
     match_id INTEGER NOT NULL,
     map_id INTEGER NOT NULL,
@@ -116,9 +108,6 @@ CREATE TABLE IF NOT EXISTS players_duel_stats (
 );
 
 
-DROP TABLE IF EXISTS player_overview_stats;
-
-
 CREATE TABLE IF NOT EXISTS player_overview_stats (
     match_id INTEGER NOT NULL,
     map_id INTEGER NOT NULL,
@@ -150,9 +139,6 @@ CREATE TABLE IF NOT EXISTS player_overview_stats (
 );
 
 
-DROP TABLE IF EXISTS player_highlights;
-
-
 CREATE TABLE IF NOT EXISTS player_highlights (
     match_id INTEGER NOT NULL,
     map_id INTEGER NOT NULL,
@@ -180,9 +166,6 @@ CREATE TABLE IF NOT EXISTS player_highlights (
 );
 
 
-DROP TABLE IF EXISTS ban_pick_log;
-
-
 CREATE TABLE IF NOT EXISTS ban_pick_log (
     match_id INTEGER NOT NULL,
     team_id INTEGER,
@@ -196,9 +179,6 @@ CREATE TABLE IF NOT EXISTS ban_pick_log (
 );
 
 
-DROP TABLE IF EXISTS tournaments;
-
-
 CREATE TABLE IF NOT EXISTS tournaments (
     id INTEGER PRIMARY KEY,
     name TEXT NOT NULL,
@@ -208,9 +188,6 @@ CREATE TABLE IF NOT EXISTS tournaments (
 );
 
 
-DROP TABLE IF EXISTS players;
-
-
 CREATE TABLE IF NOT EXISTS players (
     id INTEGER PRIMARY KEY,
     name TEXT NOT NULL,
@@ -222,9 +199,6 @@ CREATE TABLE IF NOT EXISTS players (
 );
 
 
-DROP TABLE IF EXISTS teams;
-
-
 CREATE TABLE IF NOT EXISTS teams (
     id INTEGER PRIMARY KEY,
     name TEXT NOT NULL,
@@ -237,9 +211,6 @@ CREATE TABLE IF NOT EXISTS teams (
 );
 
 
-DROP TABLE IF EXISTS maps;
-
-
 CREATE TABLE IF NOT EXISTS maps (
     id INTEGER PRIMARY KEY,
     name TEXT UNIQUE NOT NULL,
@@ -247,9 +218,6 @@ CREATE TABLE IF NOT EXISTS maps (
 );
 
 
-DROP TABLE IF EXISTS maps_pool;
-
-
 CREATE TABLE IF NOT EXISTS maps_pool (
     date TEXT NOT NULL,
     patch_no TEXT NOT NULL,
@@ -258,9 +226,6 @@ CREATE TABLE IF NOT EXISTS maps_pool (
 );
 
 
-DROP TABLE IF EXISTS countries;
-
-
 CREATE TABLE IF NOT EXISTS countries (
     id INTEGER PRIMARY KEY,
     name TEXT UNIQUE NOT NULL,
@@ -269,15 +234,9 @@ CREATE TABLE IF NOT EXISTS countries (
 );
 
 
-DROP TABLE IF EXISTS regions;
-
-
 CREATE TABLE IF NOT EXISTS regions (id INTEGER PRIMARY KEY, name TEXT UNIQUE NOT NULL);
 
 
-DROP TABLE IF EXISTS agents;
-
-
 CREATE TABLE IF NOT EXISTS agents (
     id INTEGER PRIMARY KEY,
     name TEXT UNIQUE NOT NULL,
@@ -1841,29 +1800,3 @@ VALUES
     ('Yoru', 'duelist', '2021-01-12'),
     ('Tejo', 'initiator', '2025-01-08'),
     ('Waylay', 'duelist', '2025-03-04');
-
-
-SELECT
-    *
-FROM
-    maps;
-
-
-SELECT
-    *
-FROM
-    agents;
-
-
-SELECT
-    *
-FROM
-    regions;
-
-
-SELECT
-    *
-FROM
-    maps_pool
-ORDER BY
-    date;
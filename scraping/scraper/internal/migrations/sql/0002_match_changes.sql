@@ -0,0 +1,12 @@
+-- The changes of the rows of the rescraped matches
+CREATE TABLE IF NOT EXISTS match_changes (
+    match_id INTEGER NOT NULL,
+    table_name TEXT NOT NULL,
+    row_key TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('added', 'removed', 'updated')),
+    field TEXT,
+    old_value TEXT,
+    new_value TEXT,
+    changed_at TEXT NOT NULL,
+    FOREIGN KEY (match_id) REFERENCES matches (id)
+);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS scheduled_matches (
+    id INTEGER PRIMARY KEY,
+    url TEXT UNIQUE NOT NULL,
+    scheduled_at TEXT NOT NULL,
+    tournament_id INTEGER NOT NULL,
+    stage TEXT CHECK (
+        stage IN ('group_stage', 'playoff', 'grand_final')
+    ),
+    team_1_id INTEGER,
+    team_2_id INTEGER,
+    best_of INTEGER CHECK (best_of > 0),
+    map_pool TEXT,
+    veto_note TEXT,
+    scraped_at TEXT NOT NULL,
+    match_id INTEGER,
+    FOREIGN KEY (tournament_id) REFERENCES tournaments (id),
+    FOREIGN KEY (team_1_id) REFERENCES teams (id),
+    FOREIGN KEY (team_2_id) REFERENCES teams (id),
+    FOREIGN KEY (match_id) REFERENCES matches (id)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS team_rosters (
+    team_id INTEGER NOT NULL,
+    player_id INTEGER NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('player', 'staff')),
+    tag TEXT,
+    status TEXT NOT NULL CHECK (status IN ('active', 'inactive', 'former')),
+    first_seen TEXT NOT NULL,
+    last_seen TEXT NOT NULL,
+    PRIMARY KEY (team_id, player_id, role),
+    FOREIGN KEY (team_id) REFERENCES teams (id),
+    FOREIGN KEY (player_id) REFERENCES players (id)
+);
@@ -0,0 +1,39 @@
+ALTER TABLE matches ADD COLUMN event_stage TEXT;
+
+
+ALTER TABLE matches ADD COLUMN event_round TEXT;
+
+
+ALTER TABLE tournaments ADD COLUMN start_date TEXT;
+
+
+ALTER TABLE tournaments ADD COLUMN end_date TEXT;
+
+
+ALTER TABLE tournaments ADD COLUMN location TEXT;
+
+
+CREATE TABLE IF NOT EXISTS tournament_stages (
+    tournament_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    stage_order INTEGER NOT NULL CHECK (stage_order > 0),
+    format TEXT CHECK (format IN ('groups', 'swiss', 'bracket')),
+    start_date TEXT,
+    end_date TEXT,
+    PRIMARY KEY (tournament_id, name),
+    FOREIGN KEY (tournament_id) REFERENCES tournaments (id)
+);
+
+
+CREATE TABLE IF NOT EXISTS tournament_rounds (
+    tournament_id INTEGER NOT NULL,
+    stage_name TEXT NOT NULL,
+    name TEXT NOT NULL,
+    round_order INTEGER NOT NULL CHECK (round_order > 0),
+    bracket TEXT CHECK (bracket IN ('upper', 'lower')),
+    elimination INTEGER NOT NULL,
+    start_date TEXT,
+    end_date TEXT,
+    PRIMARY KEY (tournament_id, stage_name, name),
+    FOREIGN KEY (tournament_id, stage_name) REFERENCES tournament_stages (tournament_id, name)
+);
@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS tournament_series (url TEXT PRIMARY KEY, name TEXT NOT NULL);
+
+
+-- The tier of a tournament is the tier of the first rule by priority whose conditions all match, the patterns are
+-- case insensitive regexes matched against the tournament name and the series name or url
+CREATE TABLE IF NOT EXISTS tier_rules (
+    id INTEGER PRIMARY KEY,
+    priority INTEGER NOT NULL,
+    name_pattern TEXT,
+    series_pattern TEXT,
+    min_prize_pool INTEGER,
+    tier TEXT NOT NULL
+);
+
+
+-- The tier_1 flag is replaced by the tier, run the reclassify command of the scraper to classify the other events
+ALTER TABLE tournaments ADD COLUMN tier TEXT NOT NULL DEFAULT 'unclassified';
+
+
+UPDATE tournaments
+SET
+    tier = 'tier_1'
+WHERE
+    tier_1 = 1;
+
+
+ALTER TABLE tournaments DROP COLUMN tier_1;
+
+
+ALTER TABLE tournaments ADD COLUMN region_id INTEGER REFERENCES regions (id);
+
+
+ALTER TABLE tournaments ADD COLUMN lan INTEGER;
+
+
+ALTER TABLE tournaments ADD COLUMN series_url TEXT REFERENCES tournament_series (url);
+
+
+INSERT OR IGNORE INTO
+    tier_rules (id, priority, name_pattern, series_pattern, min_prize_pool, tier)
+VALUES
+    (1, 1, 'game changers', NULL, NULL, 'game_changers'),
+    (2, 2, 'challengers|ascension', NULL, NULL, 'tier_2'),
+    (3, 3, NULL, 'vct-20[0-9]{2}|champions tour', NULL, 'tier_1'),
+    (4, 4, 'valorant (champions|masters)', NULL, NULL, 'tier_1'),
+    (5, 5, NULL, NULL, 500000, 'tier_1'),
+    (6, 6, NULL, NULL, NULL, 'tier_3');
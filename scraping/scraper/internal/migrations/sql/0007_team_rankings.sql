@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS team_rankings (
+    snapshot_date TEXT NOT NULL,
+    region TEXT NOT NULL,
+    team_id INTEGER NOT NULL,
+    rank INTEGER NOT NULL CHECK (rank > 0),
+    rating INTEGER,
+    wins INTEGER,
+    losses INTEGER,
+    streak INTEGER,
+    PRIMARY KEY (snapshot_date, region, team_id),
+    FOREIGN KEY (team_id) REFERENCES teams (id)
+);
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS player_agent_stats (
+    player_id INTEGER NOT NULL,
+    snapshot_date TEXT NOT NULL,
+    timespan TEXT NOT NULL CHECK (timespan IN ('30d', '60d', '90d', 'all')),
+    agent_id INTEGER NOT NULL,
+    rounds_played INTEGER,
+    rating REAL,
+    acs REAL,
+    kill_death_ratio REAL,
+    adr REAL,
+    kast REAL,
+    kpr REAL,
+    apr REAL,
+    fkpr REAL,
+    fdpr REAL,
+    hs REAL,
+    PRIMARY KEY (player_id, snapshot_date, timespan, agent_id),
+    FOREIGN KEY (player_id) REFERENCES players (id),
+    FOREIGN KEY (agent_id) REFERENCES agents (id)
+);
+
+
+CREATE TABLE IF NOT EXISTS player_teams (
+    player_id INTEGER NOT NULL,
+    snapshot_date TEXT NOT NULL,
+    team_id INTEGER NOT NULL,
+    current INTEGER NOT NULL,
+    joined_at TEXT,
+    left_at TEXT,
+    PRIMARY KEY (player_id, snapshot_date, team_id),
+    FOREIGN KEY (player_id) REFERENCES players (id)
+);
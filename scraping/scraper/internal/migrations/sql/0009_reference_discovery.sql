@@ -0,0 +1,52 @@
+-- The maps discovered while scraping are pending until confirmed, their release date is the day they were first seen
+ALTER TABLE maps ADD COLUMN status TEXT NOT NULL DEFAULT 'confirmed' CHECK (status IN ('confirmed', 'pending'));
+
+
+ALTER TABLE maps ADD COLUMN first_seen TEXT;
+
+
+-- The agents discovered while scraping are pending until confirmed, their release date is the day they were first
+-- seen and their type is read from agent_roles, or unknown if it isn't there. The agents table is rebuilt for the
+-- unknown type to pass its check
+CREATE TABLE agents_new (
+    id INTEGER PRIMARY KEY,
+    name TEXT UNIQUE NOT NULL,
+    agent_type TEXT NOT NULL CHECK (
+        agent_type IN ('duelist', 'controller', 'sentinel', 'initiator', 'unknown')
+    ),
+    release_date TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'confirmed' CHECK (status IN ('confirmed', 'pending')),
+    first_seen TEXT
+);
+
+
+INSERT INTO
+    agents_new (id, name, agent_type, release_date)
+SELECT
+    id,
+    name,
+    agent_type,
+    release_date
+FROM
+    agents;
+
+
+DROP TABLE agents;
+
+
+ALTER TABLE agents_new RENAME TO agents;
+
+
+-- The roles of the agents which are not in agents yet, add a row before a new agent is released
+CREATE TABLE IF NOT EXISTS agent_roles (
+    name TEXT PRIMARY KEY,
+    agent_type TEXT NOT NULL CHECK (
+        agent_type IN ('duelist', 'controller', 'sentinel', 'initiator')
+    )
+);
+
+
+INSERT OR IGNORE INTO
+    agent_roles (name, agent_type)
+VALUES
+    ('Veto', 'sentinel');
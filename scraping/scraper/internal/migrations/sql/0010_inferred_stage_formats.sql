@@ -0,0 +1,6 @@
+-- The format of the stages and the bracket side and elimination of the rounds are inferred from their names on the
+-- event matches page, the bracket page isn't scraped. The rows saved before are inferred as well
+ALTER TABLE tournament_stages ADD COLUMN inferred INTEGER NOT NULL DEFAULT 1;
+
+
+ALTER TABLE tournament_rounds ADD COLUMN inferred INTEGER NOT NULL DEFAULT 1;
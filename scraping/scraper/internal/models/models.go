@@ -9,6 +9,14 @@ type VetoAction string
 type HighlightType string
 type BuyType string
 type WonMethod string
+type ChangeKind string
+type RosterRole string
+type RosterStatus string
+type StageFormat string
+type BracketSide string
+type Tier string
+type StatsTimespan string
+type ReferenceStatus string
 
 const (
 	Def Side = "def"
@@ -16,8 +24,10 @@ const (
 
 	Duelist    AgentType = "duelist"
 	Controller AgentType = "controller"
-	Sentinel   AgentType = "sentinal"
+	Sentinel   AgentType = "sentinel"
 	Initiator  AgentType = "initiator"
+	// The type of the discovered agents whose role isn't in the agent roles
+	UnknownAgentType AgentType = "unknown"
 
 	GroupStage Stage = "group_stage"
 	Playoff    Stage = "playoff"
@@ -25,7 +35,7 @@ const (
 
 	BanMap    VetoAction = "ban"
 	PickMap   VetoAction = "pick"
-	RemainMap VetoAction = "remains"
+	RemainMap VetoAction = "remain"
 
 	P2k  HighlightType = "2k"
 	P3k  HighlightType = "3k"
@@ -47,6 +57,37 @@ const (
 	SpikeExplode WonMethod = "spike_explode"
 	Defuse       WonMethod = "defuse"
 	OutOfTime    WonMethod = "out_of_time"
+
+	RowAdded   ChangeKind = "added"
+	RowRemoved ChangeKind = "removed"
+	RowUpdated ChangeKind = "updated"
+
+	RosterPlayer RosterRole = "player"
+	RosterStaff  RosterRole = "staff"
+
+	ActiveMember   RosterStatus = "active"
+	InactiveMember RosterStatus = "inactive"
+	FormerMember   RosterStatus = "former"
+
+	GroupsFormat  StageFormat = "groups"
+	SwissFormat   StageFormat = "swiss"
+	BracketFormat StageFormat = "bracket"
+
+	UpperBracket BracketSide = "upper"
+	LowerBracket BracketSide = "lower"
+
+	// The maps and agents of the migrations are confirmed, the ones discovered while scraping are pending until confirmed
+	ConfirmedReference ReferenceStatus = "confirmed"
+	PendingReference   ReferenceStatus = "pending"
+
+	// The timespans of the agent stats of the player page
+	Timespan30Days StatsTimespan = "30d"
+	Timespan60Days StatsTimespan = "60d"
+	Timespan90Days StatsTimespan = "90d"
+	TimespanAll    StatsTimespan = "all"
+
+	// The tier of the tournaments no tier rule match, the other tiers are defined by the tier rules
+	UnclassifiedTier Tier = "unclassified"
 )
 
 type CountrySchema struct {
@@ -61,30 +102,70 @@ type RegionSchema struct {
 }
 
 type AgentSchema struct {
-	Id          int       `gorm:"column:id;primaryKey;autoIncrement"`
-	Name        string    `gorm:"column:name"`
-	AgentType   AgentType `gorm:"column:agent_type"`
-	ReleaseDate string    `gorm:"column:release_date"`
+	Id          int             `gorm:"column:id;primaryKey;autoIncrement"`
+	Name        string          `gorm:"column:name"`
+	AgentType   AgentType       `gorm:"column:agent_type"`
+	ReleaseDate string          `gorm:"column:release_date"`
+	Status      ReferenceStatus `gorm:"column:status;default:confirmed"`
+	// The day the agent was discovered while scraping, nil for the agents of the migrations
+	FirstSeen *string `gorm:"column:first_seen"`
+}
+
+// The role of an agent which is given to the agent when it is discovered, see [AgentSchema]
+type AgentRoleSchema struct {
+	Name      string    `gorm:"column:name;primaryKey"`
+	AgentType AgentType `gorm:"column:agent_type"`
 }
 
 type MapSchema struct {
-	Id          int    `gorm:"column:id;primaryKey;autoIncrement"`
-	Name        string `gorm:"column:name"`
-	ReleaseDate string `gorm:"column:release_date"`
+	Id          int             `gorm:"column:id;primaryKey;autoIncrement"`
+	Name        string          `gorm:"column:name"`
+	ReleaseDate string          `gorm:"column:release_date"`
+	Status      ReferenceStatus `gorm:"column:status;default:confirmed"`
+	// The day the map was discovered while scraping, nil for the maps of the migrations
+	FirstSeen *string `gorm:"column:first_seen"`
+}
+
+// A map of the competitive map pool from the date until the date of the next pool, the pool of a date is every row
+// of the date
+type MapPoolSchema struct {
+	Date    string `gorm:"column:date"`
+	PatchNo string `gorm:"column:patch_no"`
+	MapId   int    `gorm:"column:map_id"`
 }
 
 type MatchSchema struct {
 	Id           int
 	Url          string
 	Date         time.Time `gorm:"type:datetime"`
-	TournamentId int       `                            selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-super > div:nth-child(1) > a"                                                        source:"attr=href" parser:"idParser"`
-	Stage        Stage     `                            selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-super > div:nth-child(1) > a > div > div.match-header-event-series"                                    parser:"stageParser"`
-	Team1Id      int       `gorm:"column:team_1_id"     selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-vs > a.match-header-link.wf-link-hover.mod-1"                                        source:"attr=href" parser:"idParser"`
-	Team2Id      int       `gorm:"column:team_2_id"     selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-vs > a.match-header-link.wf-link-hover.mod-2"                                        source:"attr=href" parser:"idParser"`
-	Team1Score   int       `gorm:"column:team_1_score"  selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-vs > div > div.match-header-vs-score > div:nth-child(1) > span:nth-child(1)"`
-	Team2Score   int       `gorm:"column:team_2_score"  selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-vs > div > div.match-header-vs-score > div:nth-child(1) > span:nth-child(3)"`
-	Team1Rating  int       `gorm:"column:team_1_rating" selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-vs > a.match-header-link.wf-link-hover.mod-1 > div > div.match-header-link-name-elo"                    parser:"ratingParser"`
-	Team2Rating  int       `gorm:"column:team_2_rating" selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-vs > a.match-header-link.wf-link-hover.mod-2 > div > div.match-header-link-name-elo"                    parser:"ratingParser"`
+	TournamentId int       `                            selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-super > div:nth-child(1) > a || div.match-header-super a.match-header-event" required:"true"                                                        source:"attr=href" parser:"idParser"`
+	Stage        Stage     `                            selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-super > div:nth-child(1) > a > div > div.match-header-event-series || a.match-header-event div.match-header-event-series"                                    parser:"stageParser"`
+	Team1Id      int       `gorm:"column:team_1_id"     selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-vs > a.match-header-link.wf-link-hover.mod-1 || div.match-header-vs a.match-header-link.mod-1" required:"true"                                        source:"attr=href" parser:"idParser"`
+	Team2Id      int       `gorm:"column:team_2_id"     selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-vs > a.match-header-link.wf-link-hover.mod-2 || div.match-header-vs a.match-header-link.mod-2" required:"true"                                        source:"attr=href" parser:"idParser"`
+	Team1Score   int       `gorm:"column:team_1_score"  selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-vs > div > div.match-header-vs-score > div:nth-child(1) > span:nth-child(1) || div.match-header-vs-score > div:nth-child(1) > span:nth-child(1)" required:"true"`
+	Team2Score   int       `gorm:"column:team_2_score"  selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-vs > div > div.match-header-vs-score > div:nth-child(1) > span:nth-child(3) || div.match-header-vs-score > div:nth-child(1) > span:nth-child(3)" required:"true"`
+	Team1Rating  int       `gorm:"column:team_1_rating" selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-vs > a.match-header-link.wf-link-hover.mod-1 > div > div.match-header-link-name-elo || a.match-header-link.mod-1 div.match-header-link-name-elo"                    parser:"ratingParser"`
+	Team2Rating  int       `gorm:"column:team_2_rating" selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-vs > a.match-header-link.wf-link-hover.mod-2 > div > div.match-header-link-name-elo || a.match-header-link.mod-2 div.match-header-link-name-elo"                    parser:"ratingParser"`
+	// The stage and the round of the event the match belong to, e.g "Playoffs" and "Lower Final", see [TournamentRoundSchema]
+	EventStage *string `selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-super > div:nth-child(1) > a > div > div.match-header-event-series || a.match-header-event div.match-header-event-series" parser:"eventStageParser"`
+	EventRound *string `selector:"#wrapper > div.col-container > div.col.mod-3 > div.wf-card.match-header > div.match-header-super > div:nth-child(1) > a > div > div.match-header-event-series || a.match-header-event div.match-header-event-series" parser:"eventRoundParser"`
+}
+
+// A match which isn't played yet, it is linked to its result by MatchId once the result is scraped
+type ScheduledMatchSchema struct {
+	Id           int
+	Url          string
+	ScheduledAt  time.Time `gorm:"type:datetime"    selector:"div.match-header-date > div.moment-tz-convert[data-utc-ts]" source:"attr=data-utc-ts" required:"true"`
+	TournamentId int       `                        selector:"div.match-header-super a.match-header-event"                source:"attr=href"        required:"true" parser:"idParser"`
+	Stage        Stage     `                        selector:"a.match-header-event div.match-header-event-series"                                                   parser:"stageParser"`
+	Team1Id      *int      `gorm:"column:team_1_id" selector:"div.match-header-vs a.match-header-link.mod-1"              source:"attr=href"                        parser:"optionalIdParser"`
+	Team2Id      *int      `gorm:"column:team_2_id" selector:"div.match-header-vs a.match-header-link.mod-2"              source:"attr=href"                        parser:"optionalIdParser"`
+	BestOf       *int      `                        selector:"div.match-header-vs-note"                                   source:"text"                             regex:"Bo([0-9]+)"`
+	// Names of the maps of the announced veto, separated by commas
+	MapPool   *string
+	VetoNote  *string
+	ScrapedAt time.Time `gorm:"type:datetime"`
+	MatchId   *int
 }
 
 type BanPickLogSchema struct {
@@ -93,6 +174,8 @@ type BanPickLogSchema struct {
 	VetoOrder  int
 	MapId      int
 	VetoAction VetoAction
+	// Order of the turn among the turns with the same action, e.g 2 for the second ban
+	BanPickOrder int
 }
 
 type MatchMapSchema struct {
@@ -120,38 +203,69 @@ type PlayerSchema struct {
 	CountryId *int    `selector:"#wrapper > div.col-container > div > div.wf-card.mod-header.mod-full > div.player-header > div:nth-child(2) > div.ge-text-light"                       parser:"countryIdParser"`
 }
 
+// The stats of a player on an agent over the timespan, as listed by the agents table of the player page on the day
+// of the snapshot
+type PlayerAgentStatSchema struct {
+	PlayerId       int           `gorm:"primaryKey;autoIncrement:false"`
+	SnapshotDate   time.Time     `gorm:"primaryKey;type:datetime"`
+	Timespan       StatsTimespan `gorm:"primaryKey"`
+	AgentId        int           `gorm:"primaryKey;autoIncrement:false" selector:"td > img" source:"attr=title" required:"true" parser:"agentParser"`
+	RoundsPlayed   *int          `column:"RND||Rounds Played"                source:"text" parser:"number(en)"`
+	Rating         *float64      `column:"Rating 2.0||R2.0||Rating||R"       source:"text" parser:"number(en)"`
+	Acs            *float64      `column:"ACS||Average Combat Score"         source:"text" parser:"number(en)"`
+	KillDeathRatio *float64      `column:"K:D||KD||Kills / Deaths"           source:"text" parser:"number(en)"`
+	Adr            *float64      `column:"ADR||Average Damage per Round"     source:"text" parser:"number(en)"`
+	Kast           *float64      `column:"KAST"                              source:"text" parser:"number(en)"`
+	Kpr            *float64      `column:"KPR||Kills Per Round"              source:"text" parser:"number(en)"`
+	Apr            *float64      `column:"APR||Assists Per Round"            source:"text" parser:"number(en)"`
+	Fkpr           *float64      `column:"FKPR||First Kills Per Round"       source:"text" parser:"number(en)"`
+	Fdpr           *float64      `column:"FDPR||First Deaths Per Round"      source:"text" parser:"number(en)"`
+	Hs             *float64      `column:"HS%||Headshot %"                   source:"text" parser:"number(en)"`
+}
+
+// A team listed in the current or the past teams of the player page on the day of the snapshot. The dates are the
+// first day of the month the player joined and left the team, as far as the page tell
+type PlayerTeamSchema struct {
+	PlayerId     int       `gorm:"primaryKey;autoIncrement:false"`
+	SnapshotDate time.Time `gorm:"primaryKey;type:datetime"`
+	TeamId       int       `gorm:"primaryKey;autoIncrement:false"`
+	Current      bool
+	JoinedAt     *time.Time `gorm:"type:datetime"`
+	LeftAt       *time.Time `gorm:"type:datetime"`
+}
+
 type PlayerOverviewStatSchema struct {
 	MatchId     int
 	MapId       int
 	TeamId      int
 	Side        Side
-	PlayerId    int      `selector:"td.mod-player > div > a"                           source:"attr=href"  parser:"playerIdParser"`
-	AgentId     int      `selector:"td.mod-agents > div > span > img"                  source:"attr=title" parser:"agentParser"`
-	Rating      *float64 `selector:"td:nth-child(3) > span > span"`
-	Acs         *float64 `selector:"td:nth-child(4) > span > span"`
-	Kills       *int     `selector:"td:nth-child(5) > span > span"`
-	Deaths      *int     `selector:"td:nth-child(6) > span > span:nth-child(2) > span"`
-	Assists     *int     `selector:"td:nth-child(7) > span > span"`
-	Kast        *float64 `selector:"td:nth-child(9) > span > span"`
-	Adr         *float64 `selector:"td:nth-child(10) > span > span"`
-	Hs          *float64 `selector:"td:nth-child(11) > span > span"`
-	FirstKills  *int     `selector:"td:nth-child(12) > span > span"`
-	FirstDeaths *int     `selector:"td:nth-child(13) > span > span"`
+	PlayerId    int      `selector:"td.mod-player > div > a"          source:"attr=href"  parser:"idParser"`
+	AgentId     int      `selector:"td.mod-agents > div > span > img" source:"attr=title" parser:"agentParser"`
+	Rating      *float64 `column:"Rating 2.0||R2.0||Rating||R"       selector:"td > span > span"`
+	Acs         *float64 `column:"ACS||Average Combat Score"         selector:"td > span > span"`
+	Kills       *int     `column:"K||Kills"                          selector:"td > span > span"`
+	Deaths      *int     `column:"D||Deaths"                         selector:"td > span > span:nth-child(2) > span"`
+	Assists     *int     `column:"A||Assists"                        selector:"td > span > span"`
+	Kast        *float64 `column:"KAST"                              selector:"td > span > span"`
+	Adr         *float64 `column:"ADR||Average Damage per Round"     selector:"td > span > span"`
+	Hs          *float64 `column:"HS%||Headshot %"                   selector:"td > span > span"`
+	FirstKills  *int     `column:"FK||First Kills"                   selector:"td > span > span"`
+	FirstDeaths *int     `column:"FD||First Deaths"                  selector:"td > span > span"`
 }
 
 type DuelKills struct {
-	Team1PlayerKillsVsTeam2Player int `selector:"div:nth-child(1)" parser:"duelParser" gorm:"column:team_1_player_kills_vs_team_2_player"`
-	Team2PlayerKillsVsTeam1Player int `selector:"div:nth-child(2)" parser:"duelParser" gorm:"column:team_2_player_kills_vs_team_1_player"`
+	Team1PlayerKillsVsTeam2Player int `selector:"div:nth-child(1)" parser:"default(0)|int" gorm:"column:team_1_player_kills_vs_team_2_player"`
+	Team2PlayerKillsVsTeam1Player int `selector:"div:nth-child(2)" parser:"default(0)|int" gorm:"column:team_2_player_kills_vs_team_1_player"`
 }
 
 type DuelFirstKills struct {
-	Team1PlayerFirstKillsVsTeam2Player int `selector:"div:nth-child(1)" parser:"duelParser" gorm:"column:team_1_player_first_kills_vs_team_2_player"`
-	Team2PlayerFirstKillsVsTeam1Player int `selector:"div:nth-child(2)" parser:"duelParser" gorm:"column:team_2_player_first_kills_vs_team_1_player"`
+	Team1PlayerFirstKillsVsTeam2Player int `selector:"div:nth-child(1)" parser:"default(0)|int" gorm:"column:team_1_player_first_kills_vs_team_2_player"`
+	Team2PlayerFirstKillsVsTeam1Player int `selector:"div:nth-child(2)" parser:"default(0)|int" gorm:"column:team_2_player_first_kills_vs_team_1_player"`
 }
 
 type DuelOpKills struct {
-	Team1PlayerOpKillsVsTeam2Player int `selector:"div:nth-child(1)" parser:"duelParser" gorm:"column:team_1_player_op_kills_vs_team_2_player"`
-	Team2PlayerOpKillsVsTeam1Player int `selector:"div:nth-child(2)" parser:"duelParser" gorm:"column:team_2_player_op_kills_vs_team_1_player"`
+	Team1PlayerOpKillsVsTeam2Player int `selector:"div:nth-child(1)" parser:"default(0)|int" gorm:"column:team_1_player_op_kills_vs_team_2_player"`
+	Team2PlayerOpKillsVsTeam1Player int `selector:"div:nth-child(2)" parser:"default(0)|int" gorm:"column:team_2_player_op_kills_vs_team_1_player"`
 }
 
 type PlayerDuelStatSchema struct {
@@ -183,8 +297,8 @@ type RoundOverviewSchema struct {
 type RoundEconomySchema struct {
 	Team1BuyType BuyType `selector:"div.rnd-sq:nth-child(3)" parser:"buyTypeParser" gorm:"column:team_1_buy_type"`
 	Team2BuyType BuyType `selector:"div.rnd-sq:nth-child(4)" parser:"buyTypeParser" gorm:"column:team_2_buy_type"`
-	Team1Bank    int     `selector:"div.bank:nth-child(2)"   parser:"balanceParser" gorm:"column:team_1_bank"`
-	Team2Bank    int     `selector:"div.bank:nth-child(5)"   parser:"balanceParser" gorm:"column:team_2_bank"`
+	Team1Bank    int     `selector:"div.bank:nth-child(2)"   parser:"number(en)"    gorm:"column:team_1_bank"`
+	Team2Bank    int     `selector:"div.bank:nth-child(5)"   parser:"number(en)"    gorm:"column:team_2_bank"`
 }
 
 type RoundStatSchema struct {
@@ -206,10 +320,107 @@ type TeamSchema struct {
 	RegionId      *int
 }
 
+// A player or staff member of a team, first seen and last seen on the roster of the team page. A member who is no longer
+// on the roster is a former member, LastSeen is the last time they were on it
+type TeamRosterSchema struct {
+	TeamId   int        `gorm:"primaryKey;autoIncrement:false"`
+	PlayerId int        `gorm:"primaryKey;autoIncrement:false" selector:"a" source:"attr=href" required:"true" parser:"idParser"`
+	Role     RosterRole `gorm:"primaryKey"`
+	// Tag next to the name, e.g "inactive", "stand-in" or "head coach"
+	Tag       *string `selector:"div.team-roster-item-name-role"`
+	Status    RosterStatus
+	FirstSeen time.Time `gorm:"type:datetime"`
+	LastSeen  time.Time `gorm:"type:datetime"`
+}
+
+// The rank of a team on a rankings page of vlr.gg on the day of the snapshot. Region is the slug of the rankings
+// page, e.g "europe" for /rankings/europe
+type TeamRankingSchema struct {
+	SnapshotDate time.Time `gorm:"primaryKey;type:datetime"`
+	Region       string    `gorm:"primaryKey"`
+	TeamId       int       `gorm:"primaryKey;autoIncrement:false" selector:"div.rank-item-team a" source:"attr=href" required:"true" parser:"idParser"`
+	Rank         int       `                                      selector:"div.rank-item-rank"   source:"text"      required:"true" regex:"([0-9]+)"`
+	Rating       *int      `                                      selector:"div.rank-item-rating" source:"text"                      regex:"([0-9]+)"`
+	Wins         *int      `                                      selector:"div.rank-item-record" source:"text"                      regex:"([0-9]+)[^0-9]+[0-9]+"`
+	Losses       *int      `                                      selector:"div.rank-item-record" source:"text"                      regex:"[0-9]+[^0-9]+([0-9]+)"`
+	// Positive for a win streak and negative for a loss streak, e.g -2 for "2L"
+	Streak *int `selector:"div.rank-item-streak" source:"text" parser:"streakParser"`
+}
+
 type TournamentSchema struct {
 	Id        int
 	Name      string `selector:"#wrapper > div.col-container > div > div.wf-card.mod-event.mod-header.mod-full > div.event-header > div.event-desc > div > h1"`
 	Url       string
-	PrizePool int  `selector:"#wrapper > div.col-container > div > div.wf-card.mod-event.mod-header.mod-full > div.event-header > div.event-desc > div > div.event-desc-items > div:nth-child(2) > div.event-desc-item-value" parser:"moneyParser"`
-	Tier1     bool `                                                                                                                                                                                                                               gorm:"column:tier_1"`
+	PrizePool int `selector:"#wrapper > div.col-container > div > div.wf-card.mod-event.mod-header.mod-full > div.event-header > div.event-desc > div > div.event-desc-items > div:nth-child(2) > div.event-desc-item-value" parser:"moneyParser"`
+	// The tier given by the tier rules, see [TierRuleSchema]
+	Tier      Tier
+	StartDate *time.Time `gorm:"type:datetime"`
+	EndDate   *time.Time `gorm:"type:datetime"`
+	Location  *string    `selector:"#wrapper > div.col-container > div > div.wf-card.mod-event.mod-header.mod-full > div.event-header > div.event-desc > div > div.event-desc-items > div:nth-child(3) > div.event-desc-item-value"`
+	RegionId  *int
+	// True if the event is played on LAN, false if it is online, nil if it can't be told from the location
+	Lan       *bool
+	SeriesUrl *string
+}
+
+// The series or circuit an event belongs to, e.g "VCT 2025" or "Challengers 2025", identified by the url of its page
+type TournamentSeriesSchema struct {
+	Url  string `gorm:"primaryKey"`
+	Name string
+}
+
+// A rule of the tier classification of the tournaments, a tournament get the tier of the first matching rule by
+// priority. A rule match if every condition it has match, the patterns are case insensitive regular expressions and
+// the series pattern is matched against the name and the url of the series
+type TierRuleSchema struct {
+	Id            int `gorm:"primaryKey"`
+	Priority      int
+	NamePattern   *string
+	SeriesPattern *string
+	MinPrizePool  *int
+	Tier          Tier
+}
+
+// A stage of a tournament, e.g the group stage or the playoffs, with the dates of its first and last match
+type TournamentStageSchema struct {
+	TournamentId int    `gorm:"primaryKey;autoIncrement:false"`
+	Name         string `gorm:"primaryKey"`
+	StageOrder   int
+	// Nil if the format can't be told from the names of the rounds
+	Format *StageFormat
+	// The format is guessed from the names of the stage and its rounds rather than read from the bracket
+	Inferred  bool
+	StartDate *time.Time `gorm:"type:datetime"`
+	EndDate   *time.Time `gorm:"type:datetime"`
+}
+
+// A round of a stage of a tournament, e.g "Upper Round 1", "Group A" or "Grand Final"
+type TournamentRoundSchema struct {
+	TournamentId int    `gorm:"primaryKey;autoIncrement:false"`
+	StageName    string `gorm:"primaryKey"`
+	Name         string `gorm:"primaryKey"`
+	RoundOrder   int
+	// The side of the bracket, nil if the round isn't in a double elimination bracket
+	Bracket *BracketSide
+	// A team losing a match of the round is eliminated from the tournament
+	Elimination bool
+	// The bracket and the elimination are guessed from the names of the round and the rounds of its stage rather than
+	// read from the bracket
+	Inferred  bool
+	StartDate *time.Time `gorm:"type:datetime"`
+	EndDate   *time.Time `gorm:"type:datetime"`
+}
+
+// A difference between the rows of a match before and after it was scraped again
+type MatchChangeSchema struct {
+	MatchId int
+	// Table of the row, the row is identified in the table by the key, e.g "map_id=1,round_no=3"
+	TableName string
+	RowKey    string
+	Kind      ChangeKind
+	// The field whose value differ, nil if the row is added or removed
+	Field     *string
+	OldValue  *string
+	NewValue  *string
+	ChangedAt time.Time `gorm:"type:datetime"`
 }
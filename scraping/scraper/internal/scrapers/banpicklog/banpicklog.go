@@ -1,22 +1,38 @@
 package banpicklog
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/PuerkitoBio/goquery"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/discovery"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/mappool"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
+const remainsAction = "remains"
+
+var vetoNoteRegex = regexp.MustCompile(`\S+ (ban|pick) \S+`)
+
 type Data struct {
 	MatchId        int
 	Team1Id        int
 	Team2Id        int
 	Team1Shorthand string
 	Team2Shorthand string
-	Turns          []models.BanPickLogSchema
+	// The date of the match, the maps are checked against the map pool of the date if it isn't zero
+	Date  time.Time
+	Turns []models.BanPickLogSchema
 }
 
 type BanPickLogScraper struct {
@@ -44,14 +60,7 @@ func NewBanPickLogScraper(
 }
 
 func (b *BanPickLogScraper) getMapId(mapName string) (int, error) {
-	var vlrMap models.MapSchema
-
-	rs := b.Tx.Table("maps").Where("name = ?", mapName).First(&vlrMap)
-	if rs.Error != nil {
-		return -1, rs.Error
-	}
-
-	return vlrMap.Id, nil
+	return discovery.MapId(b.Tx, mapName)
 }
 
 func (b *BanPickLogScraper) parseToTurn(
@@ -59,10 +68,11 @@ func (b *BanPickLogScraper) parseToTurn(
 ) (banPickLog models.BanPickLogSchema, err error) {
 	banPickLog.MatchId = b.Data.MatchId
 
+	// The team is the shorthand, or the name of a team without shorthand which can have more than one space
 	switch teamShorthand {
-	case b.Data.Team1Shorthand:
+	case normalizeSpaces(b.Data.Team1Shorthand):
 		banPickLog.TeamId = &b.Data.Team1Id
-	case b.Data.Team2Shorthand:
+	case normalizeSpaces(b.Data.Team2Shorthand):
 		banPickLog.TeamId = &b.Data.Team2Id
 	default:
 		err = fmt.Errorf("Unrecognizable team shorthand: %s", teamShorthand)
@@ -95,7 +105,8 @@ func (b *BanPickLogScraper) parseToFinalTurn(
 		return
 	}
 
-	if models.VetoAction(action) != models.RemainMap {
+	// The note say "MAP remains", the action is saved as "remain"
+	if action != remainsAction {
 		err = fmt.Errorf("Unable to recognize action %s", action)
 		return
 	}
@@ -119,27 +130,186 @@ func (b *BanPickLogScraper) PrettyPrint() error {
 
 func (b *BanPickLogScraper) Scrape() error {
 	turnStrs := strings.Split(b.BanPickNote, ";")
+	actionCounts := map[models.VetoAction]int{}
 
 	logrus.Debug("Getting ban pick log info")
-	for i, turnStr := range turnStrs {
-		words := strings.Fields(strings.TrimSpace(turnStr))
-		switch len(words) {
-		case 3:
-			if turn, err := b.parseToTurn(words[0], words[1], words[2]); err != nil {
-				return err
-			} else {
-				turn.VetoOrder = i + 1
-				b.Data.Turns = append(b.Data.Turns, turn)
-			}
-		case 2:
-			if turn, err := b.parseToFinalTurn(words[0], words[1]); err != nil {
-				return err
-			} else {
-				turn.VetoOrder = i + 1
-				b.Data.Turns = append(b.Data.Turns, turn)
-			}
-		default:
-			return fmt.Errorf("Unable to determine the turn from this string: %s", turnStr)
+	for _, turnStr := range turnStrs {
+		if strings.TrimSpace(turnStr) == "" {
+			continue
+		}
+
+		var turn models.BanPickLogSchema
+		var err error
+
+		words := strings.Fields(turnStr)
+		if team, action, mapName, ok := splitBanPickTurn(words); ok {
+			turn, err = b.parseToTurn(team, action, mapName)
+		} else if len(words) == 2 {
+			turn, err = b.parseToFinalTurn(words[0], words[1])
+		} else {
+			err = fmt.Errorf("Unable to determine the turn from this string: %s", turnStr)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		actionCounts[turn.VetoAction]++
+
+		turn.VetoOrder = len(b.Data.Turns) + 1
+		turn.BanPickOrder = actionCounts[turn.VetoAction]
+		b.Data.Turns = append(b.Data.Turns, turn)
+	}
+
+	return nil
+}
+
+// Return true if the match header note is a veto note, e.g "PRX ban Haven; FNC pick Icebox; Split remains"
+func IsVetoNote(note string) bool {
+	return vetoNoteRegex.MatchString(note)
+}
+
+// Return the names of the maps of the veto note in the order of the turns, the map is the last word of a ban or pick
+// turn and the first word of the remaining map turn. The team before the action can be more than one word, e.g the
+// full team name of the upcoming matches
+func MapPool(note string) []string {
+	var mapNames []string
+
+	for _, turnStr := range strings.Split(note, ";") {
+		words := strings.Fields(turnStr)
+		if len(words) < 2 {
+			continue
+		}
+
+		if _, _, mapName, ok := splitBanPickTurn(words); ok {
+			mapNames = append(mapNames, mapName)
+		} else if words[len(words)-1] == remainsAction {
+			mapNames = append(mapNames, words[0])
+		}
+	}
+
+	return mapNames
+}
+
+func isBanPickAction(word string) bool {
+	return models.VetoAction(word) == models.BanMap || models.VetoAction(word) == models.PickMap
+}
+
+func normalizeSpaces(str string) string {
+	return strings.Join(strings.Fields(str), " ")
+}
+
+// Split the words of a ban or pick turn into the team, the action and the map. The team is every word before the
+// action, e.g "Team Heretics" for the teams without shorthand, and the map is the last word
+func splitBanPickTurn(words []string) (team, action, mapName string, ok bool) {
+	if len(words) < 3 {
+		return
+	}
+
+	i := slices.IndexFunc(words[1:len(words)-1], isBanPickAction) + 1
+	if i == 0 {
+		return
+	}
+
+	return strings.Join(words[:i], " "), words[i], words[len(words)-1], true
+}
+
+// Report collect the matches scraped without a veto note. The handler add the match to the report of the match, which
+// is merged into the report of the whole run once the match is saved, see [Report.Merge]
+type Report struct {
+	mu       sync.Mutex
+	matchIds []int
+}
+
+func (r *Report) Add(matchId int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.matchIds = append(r.matchIds, matchId)
+}
+
+// Add the matches of the other report
+func (r *Report) Merge(other *Report) {
+	matchIds := other.MatchIds()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.matchIds = append(r.matchIds, matchIds...)
+}
+
+// Return the ids of the matches without a veto note, sorted
+func (r *Report) MatchIds() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	matchIds := slices.Clone(r.matchIds)
+	slices.Sort(matchIds)
+
+	return matchIds
+}
+
+// Warn about the maps of the ban pick log which are not in the map pool of the match date, the map pool timeline
+// may be outdated, see [mappool.Update]
+func checkMapPool(timeline *mappool.Timeline, data Data) {
+	if data.Date.IsZero() {
+		return
+	}
+
+	pool, ok := timeline.At(data.Date)
+	if !ok {
+		return
+	}
+
+	for _, turn := range data.Turns {
+		if !pool.Has(turn.MapId) {
+			logrus.Warnf("Map %d of match %d is not in the map pool of %s, the map pool may be outdated", turn.MapId, data.MatchId, data.Date.Format("2006-01-02"))
+		}
+	}
+}
+
+// Handler scrape the ban pick log of the match from the match header note, the team shorthands are given by the
+// data. A match without a veto note is added to the report instead of failing. The maps are checked against the map
+// pool timeline of the context, if there is one
+func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection) error {
+	data, ok := ctx.Value("banPickData").(*Data)
+	if !ok {
+		return fmt.Errorf("Unable to find ban pick data")
+	}
+
+	tx, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("Unable to find the transaction")
+	}
+
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	note := strings.TrimSpace(selection.Text())
+	if !IsVetoNote(note) {
+		logrus.Warnf("Match %d has no veto note, continue without ban pick log", data.MatchId)
+		if report, ok := ctx.Value("banPickReport").(*Report); ok {
+			report.Add(data.MatchId)
+		}
+
+		return nil
+	}
+
+	b := NewBanPickLogScraper(tx, data.MatchId, data.Team1Id, data.Team2Id, data.Team1Shorthand, data.Team2Shorthand, note)
+	if err = b.Scrape(); err != nil {
+		return fmt.Errorf("Error scraping veto note '%s': %s", note, err.Error())
+	}
+
+	if timeline, ok := ctx.Value("mapPool").(*mappool.Timeline); ok && timeline != nil {
+		checkMapPool(timeline, b.Data)
+	}
+
+	logrus.Debug("Saving ban pick log")
+	for i := range b.Data.Turns {
+		if err = sink.SaveBanPickLog(&b.Data.Turns[i]); err != nil {
+			return err
 		}
 	}
 
@@ -1,10 +1,17 @@
 package banpicklog
 
 import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/joho/godotenv"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	_ "github.com/mattn/go-sqlite3"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -114,3 +121,144 @@ func TestBanPickLog(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestBanPickLogHandler(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.Table("maps").AutoMigrate(&models.MapSchema{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, mapName := range []string{"Haven", "Ascent", "Sunset", "Icebox", "Pearl", "Lotus", "Split"} {
+		if err = db.Table("maps").Create(&models.MapSchema{Id: i + 1, Name: mapName}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sc := piper.NewScraper(nil, nil)
+	sc.Handle(regexp.MustCompile(`^banPickLog$`), Handler)
+
+	report := &Report{}
+
+	pipe := func(matchId int, html string) *sinks.Memory {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		memory := sinks.NewMemory()
+		data := Data{MatchId: matchId, Team1Id: 624, Team2Id: 2593, Team1Shorthand: "PRX", Team2Shorthand: "FNC"}
+
+		ctx := sinks.WithSink(
+			context.WithValue(context.WithValue(context.WithValue(context.Background(), "banPickData", &data), "tx", db), "banPickReport", report),
+			memory,
+		)
+
+		if err := sc.Pipe("banPickLog", ctx, doc.Find("div.match-header-note")); err != nil {
+			t.Fatal(err)
+		}
+
+		return memory
+	}
+
+	memory := pipe(498628, `<div class="match-header-note">
+		PRX ban Haven; PRX ban Ascent; PRX pick Sunset; FNC pick Icebox; PRX pick Pearl; FNC pick Lotus; Split remains
+	</div>`)
+
+	if len(memory.BanPickLog) != 7 {
+		t.Fatalf("Wrong number of turns, want 7, get %d", len(memory.BanPickLog))
+	}
+
+	for i, want := range []struct {
+		action       models.VetoAction
+		banPickOrder int
+	}{
+		{models.BanMap, 1}, {models.BanMap, 2}, {models.PickMap, 1}, {models.PickMap, 2},
+		{models.PickMap, 3}, {models.PickMap, 4}, {models.RemainMap, 1},
+	} {
+		turn := memory.BanPickLog[i]
+		if turn.VetoAction != want.action || turn.BanPickOrder != want.banPickOrder || turn.VetoOrder != i+1 || turn.MapId != i+1 {
+			t.Errorf("Wrong turn %d: %+v", i+1, turn)
+		}
+	}
+
+	pipe(1, `<div class="match-header-note">Forfeit</div>`)
+	pipe(2, `<div></div>`)
+
+	if matchIds := report.MatchIds(); len(matchIds) != 2 || matchIds[0] != 1 || matchIds[1] != 2 {
+		t.Errorf("Want matches 1 and 2 in report, get %v", matchIds)
+	}
+
+	runReport := &Report{}
+	runReport.Add(3)
+	runReport.Merge(report)
+
+	if matchIds := runReport.MatchIds(); !slices.Equal(matchIds, []int{1, 2, 3}) {
+		t.Errorf("Want matches 1, 2 and 3 in merged report, get %v", matchIds)
+	}
+}
+
+func TestBanPickLogTeamName(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.Table("maps").AutoMigrate(&models.MapSchema{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, mapName := range []string{"Bind", "Haven", "Lotus", "Sunset", "Split"} {
+		if err = db.Table("maps").Create(&models.MapSchema{Id: i + 1, Name: mapName}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Team Heretics has no shorthand, the note use its name
+	b := NewBanPickLogScraper(
+		db, 1, 1001, 624, "Team Heretics", "PRX",
+		"Team Heretics ban Bind; PRX ban Haven; Team Heretics pick Lotus; PRX pick Sunset; Split remains",
+	)
+	if err = b.Scrape(); err != nil {
+		t.Fatal(err)
+	}
+
+	teamIds := []*int{intPtr(1001), intPtr(624), intPtr(1001), intPtr(624), nil}
+	actions := []models.VetoAction{models.BanMap, models.BanMap, models.PickMap, models.PickMap, models.RemainMap}
+
+	if len(b.Data.Turns) != len(actions) {
+		t.Fatalf("Want %d turns, get %+v", len(actions), b.Data.Turns)
+	}
+
+	for i, mapName := range []string{"Bind", "Haven", "Lotus", "Sunset", "Split"} {
+		validateTurn(t, db, b.Data.Turns[i], 1, teamIds[i], mapName, actions[i], i+1)
+	}
+
+	b = NewBanPickLogScraper(db, 2, 1001, 624, "Team Heretics", "PRX", "Team Liquid ban Bind; Split remains")
+	if err = b.Scrape(); err == nil {
+		t.Error("Unknown team should return error")
+	}
+}
+
+func TestMapPool(t *testing.T) {
+	tests := map[string][]string{
+		"PRX ban Haven; FNC ban Ascent; PRX pick Sunset; FNC pick Icebox; PRX ban Pearl; FNC ban Lotus; Split remains": {
+			"Haven", "Ascent", "Sunset", "Icebox", "Pearl", "Lotus", "Split",
+		},
+		// The upcoming matches may use the full team names
+		"Team Heretics ban Bind; Paper Rex ban Haven; Team Heretics pick Lotus; Paper Rex pick Sunset; Split remains": {
+			"Bind", "Haven", "Lotus", "Sunset", "Split",
+		},
+		"Forfeit":  nil,
+		"TBD; ; x": nil,
+	}
+
+	for note, want := range tests {
+		if get := MapPool(note); !slices.Equal(get, want) {
+			t.Errorf("Wrong map pool of '%s', want %v, get %v", note, want, get)
+		}
+	}
+}
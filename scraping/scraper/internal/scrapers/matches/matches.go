@@ -2,19 +2,22 @@ package matches
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
-	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/customparsers"
+	_ "github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/customparsers" // Register idParser
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/helpers"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/banpicklog"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/tournaments"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -22,8 +25,15 @@ import (
 const (
 	matchMapSelector        = `#wrapper > div.col-container > div.col.mod-3 > div:nth-child(6) > div > div.vm-stats-container > div[data-game-id="%s"]:has(div+div)`
 	matchMapGenericSelector = `#wrapper > div.col-container > div.col.mod-3 > div:nth-child(6) > div > div.vm-stats-container > div[data-game-id!="all"]:has(div+div)`
+	matchDateLayout         = "2006-01-02 15:04:05"
+	vetoNoteSelector        = "div.match-header-note"
 )
 
+// Date of the match shown in the match header, used when the match is not scraped from the results pages
+type matchHeaderDate struct {
+	Date time.Time `selector:"div.match-header-date > div.moment-tz-convert[data-utc-ts]" source:"attr=data-utc-ts" required:"true"`
+}
+
 func stageParser(rawVal string) (any, error) {
 	matchHeader := helpers.ToSnakeCase(strings.TrimSpace(rawVal))
 	if strings.Contains(matchHeader, "grand_final") {
@@ -37,6 +47,31 @@ func stageParser(rawVal string) (any, error) {
 	return models.GroupStage, nil
 }
 
+// Split the series of the match header, e.g "Playoffs: Lower Final", into the stage and the round of the event
+func splitEventSeries(rawVal string) (stage, round string) {
+	series := strings.Join(strings.Fields(rawVal), " ")
+	stage, round, _ = strings.Cut(series, ":")
+	return strings.TrimSpace(stage), strings.TrimSpace(round)
+}
+
+func eventStageParser(rawVal string) (any, error) {
+	stage, _ := splitEventSeries(rawVal)
+	if stage == "" {
+		return nil, nil
+	}
+
+	return &stage, nil
+}
+
+func eventRoundParser(rawVal string) (any, error) {
+	_, round := splitEventSeries(rawVal)
+	if round == "" {
+		return nil, nil
+	}
+
+	return &round, nil
+}
+
 func ratingParser(rawVal string) (any, error) {
 	ratingStr := strings.TrimSpace(rawVal)
 	if ratingStr == "" {
@@ -52,6 +87,107 @@ func ratingParser(rawVal string) (any, error) {
 	return rating, nil
 }
 
+// Scrape the teams and the tournament of the match which are not saved yet
+func scrapeMissingEntities(sc *piper.Scraper, tx *gorm.DB, sink sinks.Sink, teamIds []int, tournamentId int) error {
+	for _, teamId := range teamIds {
+		logrus.Debugf("Checking if team with id %d already exists", teamId)
+		exists, err := sink.Exists(sinks.Teams, teamId)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			logrus.Warnf("Team with id %d exists, continue", teamId)
+			continue
+		}
+
+		logrus.Debugf("Scraping team %d", teamId)
+		teamSchema := models.TeamSchema{Id: teamId, Url: fmt.Sprintf("https://www.vlr.gg/team/%d/", teamId)}
+
+		teamCtx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), "teamSchema", &teamSchema), "tx", tx), sink)
+
+		if err := sc.Get(teamSchema.Url, teamCtx, nil); err != nil {
+			return err
+		}
+	}
+
+	logrus.Debugf("Check if tournament with id %d already exists", tournamentId)
+	tournamentExists, err := sink.Exists(sinks.Tournaments, tournamentId)
+	if err != nil {
+		return err
+	}
+
+	if tournamentExists {
+		logrus.Warnf("tournament with id %d already exists, continue", tournamentId)
+		return nil
+	}
+
+	logrus.Debugf("Scraping tournament %d", tournamentId)
+	tournamentUrl := fmt.Sprintf("https://www.vlr.gg/event/%d/", tournamentId)
+
+	tournamentSchema := models.TournamentSchema{Id: tournamentId, Url: tournamentUrl}
+
+	tournamentCtx := sinks.WithSink(
+		context.WithValue(context.WithValue(context.Background(), "tournamentSchema", &tournamentSchema), "tx", tx),
+		sink,
+	)
+
+	return sc.Get(tournamentUrl, tournamentCtx, nil)
+}
+
+// Scrape the stages of the tournament again from its event page if the round of the match isn't saved, e.g the
+// tournament was scraped before the playoffs were drawn
+func refreshEventStages(sc *piper.Scraper, sink sinks.Sink, matchSchema *models.MatchSchema) error {
+	if matchSchema.EventStage == nil || matchSchema.EventRound == nil {
+		return nil
+	}
+
+	rounds, err := sink.TournamentRounds(matchSchema.TournamentId)
+	if err != nil {
+		return err
+	}
+
+	for _, round := range rounds {
+		if round.StageName == *matchSchema.EventStage && round.Name == *matchSchema.EventRound {
+			return nil
+		}
+	}
+
+	logrus.Debugf("Round '%s: %s' isn't saved, scraping the stages of the tournament", *matchSchema.EventStage, *matchSchema.EventRound)
+	tournamentSchema := models.TournamentSchema{Id: matchSchema.TournamentId}
+	ctx := sinks.WithSink(context.WithValue(context.Background(), "tournamentSchema", &tournamentSchema), sink)
+
+	return sc.Get(tournaments.EventBracketUrl(matchSchema.TournamentId), ctx, nil)
+}
+
+// Return the data of the ban pick log scraper, the shorthands of the teams are resolved from the saved teams
+func newBanPickData(sink sinks.Sink, matchSchema *models.MatchSchema) (*banpicklog.Data, error) {
+	data := banpicklog.Data{MatchId: matchSchema.Id, Team1Id: matchSchema.Team1Id, Team2Id: matchSchema.Team2Id, Date: matchSchema.Date}
+
+	for _, team := range []struct {
+		id        int
+		shorthand *string
+	}{{matchSchema.Team1Id, &data.Team1Shorthand}, {matchSchema.Team2Id, &data.Team2Shorthand}} {
+		teamSchema, err := sink.Team(team.id)
+		if err != nil {
+			return nil, err
+		}
+
+		if teamSchema == nil {
+			return nil, fmt.Errorf("Unable to find team %d", team.id)
+		}
+
+		// Fall back to the team name for the teams without shorthand
+		if teamSchema.ShorthandName != nil && *teamSchema.ShorthandName != "" {
+			*team.shorthand = *teamSchema.ShorthandName
+		} else {
+			*team.shorthand = teamSchema.Name
+		}
+	}
+
+	return &data, nil
+}
+
 func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection) error {
 	matchSchema, ok := ctx.Value("matchSchema").(*models.MatchSchema)
 	if !ok {
@@ -63,78 +199,93 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 		return fmt.Errorf("Unable to find gorm transaction")
 	}
 
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
 	overviewContent := selection.Eq(0)
 	performanceContent := selection.Eq(1)
 	economyContent := selection.Eq(2)
 
 	parsers := map[string]htmlx.Parser{
-		"idParser":     customparsers.IdParser,
-		"stageParser":  stageParser,
-		"ratingParser": ratingParser,
+		"stageParser":      stageParser,
+		"ratingParser":     ratingParser,
+		"eventStageParser": eventStageParser,
+		"eventRoundParser": eventRoundParser,
 	}
 
+	selectorReport := htmlx.NewSelectorReport()
+	// Only set when the match is scraped with -trace
+	trace, _ := ctx.Value("htmlxTrace").(*htmlx.Trace)
+
 	logrus.Debug("Parsing information from html onto match schema")
-	if err := htmlx.ParseFromSelection(matchSchema, overviewContent, htmlx.SetParsers(parsers)); err != nil {
+	if err := htmlx.ParseFromSelection(
+		matchSchema,
+		overviewContent,
+		htmlx.SetParsers(parsers),
+		htmlx.SetSelectorReport(selectorReport),
+		htmlx.SetTrace(trace),
+	); err != nil {
 		return err
 	}
 
-	jsonDat, err := json.MarshalIndent(*matchSchema, "", "	")
-	if err != nil {
-		return err
+	if matchSchema.Date.IsZero() {
+		logrus.Debug("Match date is not set, parsing it from match header")
+		var headerDate matchHeaderDate
+		if err := htmlx.ParseFromSelection(&headerDate, overviewContent, htmlx.SetDateFormat(matchDateLayout)); err != nil {
+			return err
+		}
+
+		matchSchema.Date = headerDate.Date
 	}
 
-	fmt.Println(string(jsonDat))
+	for _, fallback := range selectorReport.Fallbacks() {
+		logrus.Warnf(
+			"Primary selector of match schema field '%s' is stale, matched fallback selector '%s'",
+			fallback.Field,
+			fallback.Selector,
+		)
+	}
 
 	logrus.Debug("Saving match to db")
-	if err := tx.Table("matches").Create(matchSchema).Error; err != nil {
+	if err := sink.SaveMatch(matchSchema); err != nil {
 		return err
 	}
 
-	for _, teamId := range [2]int{matchSchema.Team1Id, matchSchema.Team2Id} {
-		logrus.Debugf("Checking if team with id %d already exists", teamId)
-		var exists bool
-		if err := tx.Table("teams").Select("count(*) > 0").Where("id = ?", teamId).Find(&exists).Error; err != nil {
-			return err
-		}
-
-		if exists {
-			logrus.Warnf("Team with id %d exists, continue", teamId)
-			continue
-		}
-
-		logrus.Debugf("Scraping team %d", teamId)
-		teamSchema := models.TeamSchema{Id: teamId, Url: fmt.Sprintf("https://www.vlr.gg/team/%d/", teamId)}
-
-		teamCtx := context.WithValue(context.WithValue(context.Background(), "teamSchema", &teamSchema), "tx", tx)
-
-		if err := sc.Get(teamSchema.Url, teamCtx, nil); err != nil {
-			return err
-		}
+	if err := scrapeMissingEntities(sc, tx, sink, []int{matchSchema.Team1Id, matchSchema.Team2Id}, matchSchema.TournamentId); err != nil {
+		return err
 	}
 
-	logrus.Debugf("Check if tournament with id %d already exists", matchSchema.TournamentId)
-	var tournamentExists bool
-	if err := tx.Table("tournaments").Select("count(*) > 0").Where("id = ?", matchSchema.TournamentId).Find(&tournamentExists).Error; err != nil {
+	if err := refreshEventStages(sc, sink, matchSchema); err != nil {
 		return err
 	}
 
-	if !tournamentExists {
-		logrus.Debugf("Scraping tournament %d", matchSchema.TournamentId)
-		tournamentUrl := fmt.Sprintf("https://www.vlr.gg/event/%d/", matchSchema.TournamentId)
-
-		tournamentSchema := models.TournamentSchema{Id: matchSchema.TournamentId, Url: tournamentUrl}
+	if err := sink.LinkScheduledMatch(matchSchema.Id); err != nil {
+		return err
+	}
 
-		tournamentCtx := context.WithValue(
-			context.WithValue(context.Background(), "tournamentSchema", &tournamentSchema),
-			"tx",
-			tx,
-		)
+	logrus.Debug("Scraping ban pick log")
+	banPickData, err := newBanPickData(sink, matchSchema)
+	if err != nil {
+		return err
+	}
 
-		if err := sc.Get(tournamentUrl, tournamentCtx, nil); err != nil {
-			return err
-		}
-	} else {
-		logrus.Warnf("tournament with id %d already exists, continue", matchSchema.TournamentId)
+	banPickCtx := sinks.WithSink(
+		context.WithValue(
+			context.WithValue(
+				context.WithValue(context.WithValue(context.Background(), "banPickData", banPickData), "tx", tx),
+				"banPickReport",
+				ctx.Value("banPickReport"),
+			),
+			"mapPool",
+			ctx.Value("mapPool"),
+		),
+		sink,
+	)
+
+	if err := sc.Pipe("banPickLog", banPickCtx, overviewContent.Find(vetoNoteSelector)); err != nil {
+		return err
 	}
 
 	logrus.Debug("Locating maps nodes")
@@ -149,9 +300,9 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 
 			gameId, exists := mapOverviewNode.Attr("data-game-id")
 			if !exists {
-				errChan <- fmt.Errorf("Unable to find game id")
 				html, _ := mapOverviewNode.Html()
-				fmt.Println(html)
+				logrus.Tracef("Map node without game id:\n%s", html)
+				errChan <- fmt.Errorf("Unable to find game id")
 				return
 			}
 
@@ -166,7 +317,7 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 				Team2Id: matchSchema.Team2Id,
 			}
 
-			ctx := context.WithValue(context.WithValue(context.Background(), "matchMapSchema", &matchMap), "tx", tx)
+			ctx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), "matchMapSchema", &matchMap), "tx", tx), sink)
 
 			if err := sc.Pipe("matchMaps", ctx, combined); err != nil {
 				errChan <- err
@@ -11,6 +11,8 @@ import (
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/helpers"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/tournaments"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -101,7 +103,9 @@ func TestMatchScraper(t *testing.T) {
 	backend := piper.NewPiperBackend(&http.Client{})
 
 	sc := piper.NewScraper(backend, cache)
-	sc.Handle(regexp.MustCompile(`match`), Handler)
+	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/event\/matches\/[0-9]+\/\?series_id=all$`), tournaments.MatchesHandler)
+	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/event\/[0-9]+\/\?bracket$`), tournaments.BracketHandler)
+	sc.Handle(regexp.MustCompile(`^match$`), Handler)
 
 	for _, testMatch := range testMatches {
 		res, err := http.Get(testMatch.Url)
@@ -121,12 +125,15 @@ func TestMatchScraper(t *testing.T) {
 		}
 
 		ctx := context.WithValue(context.Background(), "matchSchema", &m)
-		ctx2 := context.WithValue(ctx, "tx", tx)
+		ctx2 := sinks.WithSink(context.WithValue(ctx, "tx", tx), sinks.NewSQLite(tx))
 
 		if err := sc.Pipe("match", ctx2, doc.Selection); err != nil {
 			t.Fatal(err)
 		}
 
+		// The stage and the round of the event are checked by TestEventSeriesParsers
+		m.EventStage, m.EventRound = nil, nil
+
 		if err := helpers.CompareStructs(testMatch, m); err != nil {
 			t.Error(err)
 		}
@@ -134,3 +141,28 @@ func TestMatchScraper(t *testing.T) {
 
 	tx.Rollback()
 }
+
+func TestEventSeriesParsers(t *testing.T) {
+	for series, want := range map[string][2]string{
+		"Playoffs: Lower Final":                   {"Playoffs", "Lower Final"},
+		"\n\t\tGroup Stage:\n\t\tDecider (A)\n\t": {"Group Stage", "Decider (A)"},
+		"Showmatch": {"Showmatch", ""},
+		"":          {"", ""},
+	} {
+		for i, parser := range []func(string) (any, error){eventStageParser, eventRoundParser} {
+			val, err := parser(series)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := ""
+			if str, ok := val.(*string); ok {
+				got = *str
+			}
+
+			if got != want[i] {
+				t.Errorf("Series %q: want %q, get %q", series, want[i], got)
+			}
+		}
+	}
+}
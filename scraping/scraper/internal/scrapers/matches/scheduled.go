@@ -0,0 +1,69 @@
+package matches
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/banpicklog"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ScheduledHandler scrape the page of a match which isn't played yet. The teams which are already decided and the
+// tournament are scraped if they are not saved
+func ScheduledHandler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection) error {
+	scheduledMatch, ok := ctx.Value("scheduledMatch").(*models.ScheduledMatchSchema)
+	if !ok {
+		return fmt.Errorf("Unable to find scheduled match schema")
+	}
+
+	tx, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("Unable to find gorm transaction")
+	}
+
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	logrus.Debug("Parsing information from html onto scheduled match schema")
+	if err := htmlx.ParseFromSelection(
+		scheduledMatch,
+		selection,
+		htmlx.SetParsers(map[string]htmlx.Parser{"stageParser": stageParser}),
+		htmlx.SetDateFormat(matchDateLayout),
+	); err != nil {
+		return err
+	}
+
+	if note := strings.TrimSpace(selection.Find(vetoNoteSelector).Text()); banpicklog.IsVetoNote(note) {
+		mapPool := strings.Join(banpicklog.MapPool(note), ",")
+
+		scheduledMatch.VetoNote = &note
+		scheduledMatch.MapPool = &mapPool
+	}
+
+	var teamIds []int
+	for _, teamId := range []*int{scheduledMatch.Team1Id, scheduledMatch.Team2Id} {
+		if teamId != nil {
+			teamIds = append(teamIds, *teamId)
+		}
+	}
+
+	if err := scrapeMissingEntities(sc, tx, sink, teamIds, scheduledMatch.TournamentId); err != nil {
+		return err
+	}
+
+	scheduledMatch.ScrapedAt = time.Now().UTC()
+
+	logrus.Debug("Saving scheduled match")
+	return sink.SaveScheduledMatch(scheduledMatch)
+}
@@ -0,0 +1,109 @@
+package matches
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const scheduledMatchHtml = `<div class="wf-card match-header">
+	<div class="match-header-super">
+		<a class="match-header-event" href="/event/2283/valorant-champions-2025">
+			<div>
+				<div style="font-weight: 700;">Valorant Champions 2025</div>
+				<div class="match-header-event-series">Playoffs: Grand Final</div>
+			</div>
+		</a>
+		<div class="match-header-date">
+			<div class="moment-tz-convert" data-utc-ts="2025-10-05 18:00:00">Sunday, October 5th</div>
+		</div>
+	</div>
+	<div class="match-header-vs">
+		<a class="match-header-link wf-link-hover mod-1" href="/team/624/paper-rex">Paper Rex</a>
+		<div class="match-header-vs-score">
+			<div class="match-header-vs-note">upcoming</div>
+			<div class="match-header-vs-note">Bo5</div>
+		</div>
+		<div class="match-header-link wf-link-hover mod-2">TBD</div>
+	</div>
+	<div class="match-header-note">PRX ban Haven; TBD ban Ascent; PRX pick Sunset; TBD pick Icebox; Split remains</div>
+</div>`
+
+func TestScheduledHandler(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(scheduledMatchHtml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The team and the tournament are saved so that they are not scraped
+	memory := sinks.NewMemory()
+	if err = memory.SaveTeam(&models.TeamSchema{Id: 624}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = memory.SaveTournament(&models.TournamentSchema{Id: 2283}); err != nil {
+		t.Fatal(err)
+	}
+
+	sc := piper.NewScraper(nil, nil)
+	sc.Handle(regexp.MustCompile(`^scheduledMatch$`), ScheduledHandler)
+
+	scheduledMatch := models.ScheduledMatchSchema{Id: 542194, Url: "https://www.vlr.gg/542194/paper-rex-vs-tbd"}
+	ctx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), "scheduledMatch", &scheduledMatch), "tx", db), memory)
+
+	if err = sc.Pipe("scheduledMatch", ctx, doc.Selection); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(memory.ScheduledMatches) != 1 {
+		t.Fatalf("Want 1 scheduled match, get %d", len(memory.ScheduledMatches))
+	}
+
+	saved := memory.ScheduledMatches[0]
+
+	if !saved.ScheduledAt.Equal(time.Date(2025, 10, 5, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("Wrong scheduled time %s", saved.ScheduledAt)
+	}
+
+	if saved.TournamentId != 2283 || saved.Stage != models.GrandFinal {
+		t.Errorf("Wrong tournament %d or stage %s", saved.TournamentId, saved.Stage)
+	}
+
+	if saved.Team1Id == nil || *saved.Team1Id != 624 || saved.Team2Id != nil {
+		t.Errorf("Want team 1 624 and no team 2, get %v and %v", saved.Team1Id, saved.Team2Id)
+	}
+
+	if saved.BestOf == nil || *saved.BestOf != 5 {
+		t.Errorf("Want best of 5, get %v", saved.BestOf)
+	}
+
+	if saved.MapPool == nil || *saved.MapPool != "Haven,Ascent,Sunset,Icebox,Split" {
+		t.Errorf("Wrong map pool %v", saved.MapPool)
+	}
+
+	if saved.ScrapedAt.IsZero() || saved.MatchId != nil {
+		t.Errorf("Scheduled match should be scraped and not linked")
+	}
+
+	if err = memory.LinkScheduledMatch(542194); err != nil {
+		t.Fatal(err)
+	}
+
+	if matchId := memory.ScheduledMatches[0].MatchId; matchId == nil || *matchId != 542194 {
+		t.Errorf("Scheduled match should be linked to 542194, get %v", matchId)
+	}
+}
@@ -2,7 +2,6 @@ package matchmaps
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -12,6 +11,7 @@ import (
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/customparsers"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/helpers"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -75,6 +75,11 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 		return fmt.Errorf("Unable to find gorm transaction")
 	}
 
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
 	parsers := map[string]htmlx.Parser{
 		"defFirstParser": defFirstParser(matchMapSchema.Team1Id, matchMapSchema.Team2Id),
 		"durationParser": durationParser,
@@ -91,41 +96,33 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 		return err
 	}
 
-	jsonDat, err := json.MarshalIndent(*matchMapSchema, "", "	")
-	if err != nil {
-		return err
-	}
-
-	fmt.Println(string(jsonDat))
-
-	logrus.Debug("Saving match map to db")
-	if err := tx.Table("match_maps").Create(matchMapSchema).Error; err != nil {
+	logrus.Debug("Saving match map")
+	if err := sink.SaveMatchMap(matchMapSchema); err != nil {
 		return err
 	}
 
 	logrus.Debug("Scraping players overview stats")
-	t1Hashmap, t2Hashmap, err := scrapePlayersStats(tx, sc, *matchMapSchema, mapOverviewNode)
+	t1Hashmap, t2Hashmap, err := scrapePlayersStats(tx, sink, sc, *matchMapSchema, mapOverviewNode)
 	if err != nil {
 		return err
 	}
 
 	logrus.Debug("Scraping rounds stats")
-	if err := scrapeRoundsStats(tx, sc, *matchMapSchema, mapOverviewNode, mapEconomyNode); err != nil {
+	if err := scrapeRoundsStats(tx, sink, sc, *matchMapSchema, mapOverviewNode, mapEconomyNode); err != nil {
 		logrus.Errorf("Error extracting round stats: %s, rounds stats of this map won't be uploaded", err.Error())
 	}
 
 	logrus.Debug("Scraping players duel stats")
-	if err := scrapePlayerDuelStats(tx, sc, *matchMapSchema, mapPerformanceNode, t1Hashmap, t2Hashmap); err != nil {
+	if err := scrapePlayerDuelStats(tx, sink, sc, *matchMapSchema, mapPerformanceNode, t1Hashmap, t2Hashmap); err != nil {
 		logrus.Errorf("Error extracting players duel stats: %s, player duel stats of this map won't be uploaded", err.Error())
 	}
 
 	logrus.Debug("Scraping players highlights")
-	if err := scrapePlayersHighlights(tx, sc, *matchMapSchema, mapPerformanceNode, t1Hashmap, t2Hashmap); err != nil {
+	if err := scrapePlayersHighlights(tx, sink, sc, *matchMapSchema, mapPerformanceNode, t1Hashmap, t2Hashmap); err != nil {
 		logrus.Errorf("Error extracting players highlights: %s, players highlights of this map won't be uploaded", err.Error())
 	}
 
-	fmt.Println(t1Hashmap)
-	fmt.Println(t2Hashmap)
+	logrus.Tracef("Team 1 players: %v, team 2 players: %v", t1Hashmap, t2Hashmap)
 
 	return nil
 }
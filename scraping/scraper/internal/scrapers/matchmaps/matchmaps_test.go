@@ -11,6 +11,7 @@ import (
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/helpers"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -123,7 +124,7 @@ func TestMatchMapScraper(t *testing.T) {
 		}
 
 		ctx := context.WithValue(context.Background(), "matchMapSchema", &m)
-		ctx2 := context.WithValue(ctx, "tx", tx)
+		ctx2 := sinks.WithSink(context.WithValue(ctx, "tx", tx), sinks.NewSQLite(tx))
 
 		if err := sc.Pipe("matchMap", ctx2, mapNode); err != nil {
 			t.Fatal(err)
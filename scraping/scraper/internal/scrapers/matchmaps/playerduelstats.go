@@ -3,78 +3,108 @@ package matchmaps
 import (
 	"context"
 	"fmt"
-	"os"
-	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/jedib0t/go-pretty/table"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 const (
-	duelKillsTableSelector      = `div:nth-child(1) > table.wf-table-inset.mod-matrix.mod-normal > tbody`
-	duelFirstKillsTableSelector = `div:nth-child(1) > table.wf-table-inset.mod-matrix.mod-fkfd > tbody`
-	duelOpKillsTableSelector    = `div:nth-child(1) > table.wf-table-inset.mod-matrix.mod-op > tbody`
-	nodeSelector                = `tr:nth-child(%d) > td:nth-child(%d)`
-	playerNameSelector          = `div.team > div`
+	duelKillsTableSelector      = `div:nth-child(1) > table.wf-table-inset.mod-matrix.mod-normal`
+	duelFirstKillsTableSelector = `div:nth-child(1) > table.wf-table-inset.mod-matrix.mod-fkfd`
+	duelOpKillsTableSelector    = `div:nth-child(1) > table.wf-table-inset.mod-matrix.mod-op`
 )
 
+// A cell of a duel matrix, team 1 players are the rows and team 2 players are the columns
+type duelMatrixCell struct {
+	Team1PlayerName string             `matrix:"rowHeader"    selector:"div.team > div" parser:"trim"`
+	Team2PlayerName string             `matrix:"columnHeader" selector:"div.team > div" parser:"trim"`
+	Node            *goquery.Selection `matrix:"cell"`
+}
+
 func scrapePlayerDuelStats(
 	tx *gorm.DB,
+	sink sinks.Sink,
 	sc *piper.Scraper,
 	matchMapSchema models.MatchMapSchema,
 	mapPerformanceNode *goquery.Selection,
 	t1Hashmap map[string]int,
 	t2Hashmap map[string]int,
 ) error {
-	duelKillsTableNode := mapPerformanceNode.Find(duelKillsTableSelector)
-	duelFirstKillsTableNode := mapPerformanceNode.Find(duelFirstKillsTableSelector)
-	duelOpKillsTableNode := mapPerformanceNode.Find(duelOpKillsTableSelector)
+	var duelKillsCells, duelFirstKillsCells, duelOpKillsCells []duelMatrixCell
 
-	duelTable := [5][5]models.PlayerDuelStatSchema{}
+	if err := htmlx.ParseMatrix(&duelKillsCells, mapPerformanceNode.Find(duelKillsTableSelector)); err != nil {
+		return fmt.Errorf("Error parsing duel kills matrix: %s", err.Error())
+	}
 
-	if err := tx.Transaction(func(ptx *gorm.DB) error {
-		for i := 2; i <= 6; i++ {
-			for j := 2; j <= 6; j++ {
-				duelKillsNode := duelKillsTableNode.Find(fmt.Sprintf(nodeSelector, i, j))
-				duelFirstKillsNode := duelFirstKillsTableNode.Find(fmt.Sprintf(nodeSelector, i, j))
-				duelOpKillsNode := duelOpKillsTableNode.Find(fmt.Sprintf(nodeSelector, i, j))
+	if err := htmlx.ParseMatrix(&duelFirstKillsCells, mapPerformanceNode.Find(duelFirstKillsTableSelector)); err != nil {
+		return fmt.Errorf("Error parsing duel first kills matrix: %s", err.Error())
+	}
 
-				t1PlayerNode := duelKillsTableNode.Find(fmt.Sprintf(nodeSelector, i, 1))
-				t2PlayerNode := duelKillsTableNode.Find(fmt.Sprintf(nodeSelector, 1, j))
+	if err := htmlx.ParseMatrix(&duelOpKillsCells, mapPerformanceNode.Find(duelOpKillsTableSelector)); err != nil {
+		return fmt.Errorf("Error parsing duel op kills matrix: %s", err.Error())
+	}
 
-				t1PlayerName := strings.TrimSpace(t1PlayerNode.Find(playerNameSelector).Clone().Children().Remove().End().Text())
-				t2PlayerName := strings.TrimSpace(t2PlayerNode.Find(playerNameSelector).Clone().Children().Remove().End().Text())
+	if len(duelFirstKillsCells) != len(duelKillsCells) || len(duelOpKillsCells) != len(duelKillsCells) {
+		return fmt.Errorf(
+			"Duel matrices have different sizes: %d, %d, %d",
+			len(duelKillsCells),
+			len(duelFirstKillsCells),
+			len(duelOpKillsCells),
+		)
+	}
 
-				t1PlayerId, ok := t1Hashmap[t1PlayerName]
-				if !ok {
-					return fmt.Errorf("Player name '%s' doesn't exists in t1 hashmap", t1PlayerName)
-				}
+	var duelTable [][]models.PlayerDuelStatSchema
 
-				t2PlayerId, ok := t2Hashmap[t2PlayerName]
-				if !ok {
-					return fmt.Errorf("Player name '%s' doesn't exists in t2 hashmap", t2PlayerName)
+	if err := sink.Transaction(func(duelsSink sinks.Sink) error {
+		for i, duelKillsCell := range duelKillsCells {
+			t1PlayerName := duelKillsCell.Team1PlayerName
+			t2PlayerName := duelKillsCell.Team2PlayerName
+
+			for _, otherCell := range []duelMatrixCell{duelFirstKillsCells[i], duelOpKillsCells[i]} {
+				if otherCell.Team1PlayerName != t1PlayerName || otherCell.Team2PlayerName != t2PlayerName {
+					return fmt.Errorf("Duel matrices are not in the same player order at cell %d", i)
 				}
+			}
 
-				combined := duelKillsNode.Clone().AddSelection(duelFirstKillsNode).AddSelection(duelOpKillsNode)
+			t1PlayerId, ok := t1Hashmap[t1PlayerName]
+			if !ok {
+				return fmt.Errorf("Player name '%s' doesn't exists in t1 hashmap", t1PlayerName)
+			}
 
-				duelStats := models.PlayerDuelStatSchema{
-					MatchId:       matchMapSchema.MatchId,
-					MapId:         matchMapSchema.MapId,
-					Team1PlayerId: t1PlayerId,
-					Team2PlayerId: t2PlayerId,
-				}
+			t2PlayerId, ok := t2Hashmap[t2PlayerName]
+			if !ok {
+				return fmt.Errorf("Player name '%s' doesn't exists in t2 hashmap", t2PlayerName)
+			}
 
-				ctx := context.WithValue(context.WithValue(context.Background(), "duelStats", &duelStats), "tx", ptx)
+			combined := duelKillsCell.Node.Clone().AddSelection(duelFirstKillsCells[i].Node).AddSelection(duelOpKillsCells[i].Node)
 
-				if err := sc.Pipe("duelStats", ctx, combined); err != nil {
-					return err
-				}
+			duelStats := models.PlayerDuelStatSchema{
+				MatchId:       matchMapSchema.MatchId,
+				MapId:         matchMapSchema.MapId,
+				Team1PlayerId: t1PlayerId,
+				Team2PlayerId: t2PlayerId,
+			}
 
-				duelTable[i-2][j-2] = duelStats
+			ctx := sinks.WithSink(
+				context.WithValue(context.WithValue(context.Background(), "duelStats", &duelStats), "tx", tx),
+				duelsSink,
+			)
+
+			if err := sc.Pipe("duelStats", ctx, combined); err != nil {
+				return err
+			}
+
+			if i == 0 || t1PlayerName != duelKillsCells[i-1].Team1PlayerName {
+				duelTable = append(duelTable, nil)
 			}
+
+			duelTable[len(duelTable)-1] = append(duelTable[len(duelTable)-1], duelStats)
 		}
 
 		return nil
@@ -82,71 +112,42 @@ func scrapePlayerDuelStats(
 		return err
 	}
 
+	if len(duelTable) == 0 {
+		return nil
+	}
+
 	duelKillsTable := table.NewWriter()
 	duelFirstKillsTable := table.NewWriter()
 	duelOpKillsTable := table.NewWriter()
-	duelKillsTable.SetOutputMirror(os.Stdout)
-	duelFirstKillsTable.SetOutputMirror(os.Stdout)
-	duelOpKillsTable.SetOutputMirror(os.Stdout)
-
-	duelKillsTable.AppendHeader(table.Row{
-		"",
-		duelTable[0][0].Team2PlayerId,
-		duelTable[0][1].Team2PlayerId,
-		duelTable[0][2].Team2PlayerId,
-		duelTable[0][3].Team2PlayerId,
-		duelTable[0][4].Team2PlayerId,
-	})
-	duelFirstKillsTable.AppendHeader(table.Row{
-		"",
-		duelTable[0][0].Team2PlayerId,
-		duelTable[0][1].Team2PlayerId,
-		duelTable[0][2].Team2PlayerId,
-		duelTable[0][3].Team2PlayerId,
-		duelTable[0][4].Team2PlayerId,
-	})
-	duelOpKillsTable.AppendHeader(table.Row{
-		"",
-		duelTable[0][0].Team2PlayerId,
-		duelTable[0][1].Team2PlayerId,
-		duelTable[0][2].Team2PlayerId,
-		duelTable[0][3].Team2PlayerId,
-		duelTable[0][4].Team2PlayerId,
-	})
-
-	for i := range 5 {
-		duelKillsTable.AppendRow(table.Row{
-			duelTable[i][0].Team1PlayerId,
-			fmt.Sprintf("%d-%d", duelTable[i][0].Team1PlayerKillsVsTeam2Player, duelTable[i][0].Team2PlayerKillsVsTeam1Player),
-			fmt.Sprintf("%d-%d", duelTable[i][1].Team1PlayerKillsVsTeam2Player, duelTable[i][1].Team2PlayerKillsVsTeam1Player),
-			fmt.Sprintf("%d-%d", duelTable[i][2].Team1PlayerKillsVsTeam2Player, duelTable[i][2].Team2PlayerKillsVsTeam1Player),
-			fmt.Sprintf("%d-%d", duelTable[i][3].Team1PlayerKillsVsTeam2Player, duelTable[i][3].Team2PlayerKillsVsTeam1Player),
-			fmt.Sprintf("%d-%d", duelTable[i][4].Team1PlayerKillsVsTeam2Player, duelTable[i][4].Team2PlayerKillsVsTeam1Player),
-		})
-		duelFirstKillsTable.AppendRow(table.Row{
-			duelTable[i][0].Team1PlayerId,
-			fmt.Sprintf("%d-%d", duelTable[i][0].Team1PlayerFirstKillsVsTeam2Player, duelTable[i][0].Team2PlayerFirstKillsVsTeam1Player),
-			fmt.Sprintf("%d-%d", duelTable[i][1].Team1PlayerFirstKillsVsTeam2Player, duelTable[i][1].Team2PlayerFirstKillsVsTeam1Player),
-			fmt.Sprintf("%d-%d", duelTable[i][2].Team1PlayerFirstKillsVsTeam2Player, duelTable[i][2].Team2PlayerFirstKillsVsTeam1Player),
-			fmt.Sprintf("%d-%d", duelTable[i][3].Team1PlayerFirstKillsVsTeam2Player, duelTable[i][3].Team2PlayerFirstKillsVsTeam1Player),
-			fmt.Sprintf("%d-%d", duelTable[i][4].Team1PlayerFirstKillsVsTeam2Player, duelTable[i][4].Team2PlayerFirstKillsVsTeam1Player),
-		})
-		duelOpKillsTable.AppendRow(table.Row{
-			duelTable[i][0].Team1PlayerId,
-			fmt.Sprintf("%d-%d", duelTable[i][0].Team1PlayerOpKillsVsTeam2Player, duelTable[i][0].Team2PlayerOpKillsVsTeam1Player),
-			fmt.Sprintf("%d-%d", duelTable[i][1].Team1PlayerOpKillsVsTeam2Player, duelTable[i][1].Team2PlayerOpKillsVsTeam1Player),
-			fmt.Sprintf("%d-%d", duelTable[i][2].Team1PlayerOpKillsVsTeam2Player, duelTable[i][2].Team2PlayerOpKillsVsTeam1Player),
-			fmt.Sprintf("%d-%d", duelTable[i][3].Team1PlayerOpKillsVsTeam2Player, duelTable[i][3].Team2PlayerOpKillsVsTeam1Player),
-			fmt.Sprintf("%d-%d", duelTable[i][4].Team1PlayerOpKillsVsTeam2Player, duelTable[i][4].Team2PlayerOpKillsVsTeam1Player),
-		})
+
+	header := table.Row{""}
+	for _, duelStats := range duelTable[0] {
+		header = append(header, duelStats.Team2PlayerId)
+	}
+
+	duelKillsTable.AppendHeader(header)
+	duelFirstKillsTable.AppendHeader(header)
+	duelOpKillsTable.AppendHeader(header)
+
+	for _, duelRow := range duelTable {
+		duelKillsRow := table.Row{duelRow[0].Team1PlayerId}
+		duelFirstKillsRow := table.Row{duelRow[0].Team1PlayerId}
+		duelOpKillsRow := table.Row{duelRow[0].Team1PlayerId}
+
+		for _, duelStats := range duelRow {
+			duelKillsRow = append(duelKillsRow, fmt.Sprintf("%d-%d", duelStats.Team1PlayerKillsVsTeam2Player, duelStats.Team2PlayerKillsVsTeam1Player))
+			duelFirstKillsRow = append(duelFirstKillsRow, fmt.Sprintf("%d-%d", duelStats.Team1PlayerFirstKillsVsTeam2Player, duelStats.Team2PlayerFirstKillsVsTeam1Player))
+			duelOpKillsRow = append(duelOpKillsRow, fmt.Sprintf("%d-%d", duelStats.Team1PlayerOpKillsVsTeam2Player, duelStats.Team2PlayerOpKillsVsTeam1Player))
+		}
+
+		duelKillsTable.AppendRow(duelKillsRow)
+		duelFirstKillsTable.AppendRow(duelFirstKillsRow)
+		duelOpKillsTable.AppendRow(duelOpKillsRow)
 	}
 
-	fmt.Println("== DUEL KILLS TABLE ==")
-	duelKillsTable.Render()
-	fmt.Println("== DUEL FIRST KILLS TABLE ==")
-	duelFirstKillsTable.Render()
-	fmt.Println("== DUEL OP KILLS TABLE ==")
-	duelOpKillsTable.Render()
+	logrus.Tracef("Duel kills table:\n%s", duelKillsTable.Render())
+	logrus.Tracef("Duel first kills table:\n%s", duelFirstKillsTable.Render())
+	logrus.Tracef("Duel op kills table:\n%s", duelOpKillsTable.Render())
 
 	return nil
 }
@@ -3,46 +3,54 @@ package matchmaps
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/jedib0t/go-pretty/table"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/playerhighlights"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 const (
-	playerHighlightRowsSelector = "div:nth-child(2) > table > tbody > tr:not(tr:first-child)"
+	playerHighlightTableSelector = "div:nth-child(2) > table"
 )
 
+// A row of the highlights table, every highlight cell hold one entry per round the highlight happened
+type playerHighlightRow struct {
+	PlayerName string             `selector:"td:first-child div.team > div" parser:"trim"`
+	P2k        *goquery.Selection `column:"2K"  selector:"td > div > div > div"`
+	P3k        *goquery.Selection `column:"3K"  selector:"td > div > div > div"`
+	P4k        *goquery.Selection `column:"4K"  selector:"td > div > div > div"`
+	P5k        *goquery.Selection `column:"5K"  selector:"td > div > div > div"`
+	P1v1       *goquery.Selection `column:"1v1" selector:"td > div > div > div"`
+	P1v2       *goquery.Selection `column:"1v2" selector:"td > div > div > div"`
+	P1v3       *goquery.Selection `column:"1v3" selector:"td > div > div > div"`
+	P1v4       *goquery.Selection `column:"1v4" selector:"td > div > div > div"`
+	P1v5       *goquery.Selection `column:"1v5" selector:"td > div > div > div"`
+}
+
 func scrapePlayersHighlights(
 	tx *gorm.DB,
+	sink sinks.Sink,
 	sc *piper.Scraper,
 	matchMapSchema models.MatchMapSchema,
 	mapPerformanceNode *goquery.Selection,
 	t1Hashmap,
 	t2Hashmap map[string]int,
 ) error {
-	playerHighlightRows := mapPerformanceNode.Find(playerHighlightRowsSelector)
-
-	if err := tx.Transaction(func(ptx *gorm.DB) error {
-		for i := range playerHighlightRows.Length() {
-			playerHighlightRow := playerHighlightRows.Eq(i)
-
-			pNameNode := playerHighlightRow.Find(`td:nth-child(1)`)
-			p2kNode := playerHighlightRow.Find(`td:nth-child(3) > div > div > div`)
-			p3kNode := playerHighlightRow.Find(`td:nth-child(4) > div > div > div`)
-			p4kNode := playerHighlightRow.Find(`td:nth-child(5) > div > div > div`)
-			p5kNode := playerHighlightRow.Find(`td:nth-child(6) > div > div > div`)
-			p1v1Node := playerHighlightRow.Find(`td:nth-child(7) > div > div > div`)
-			p1v2Node := playerHighlightRow.Find(`td:nth-child(8) > div > div > div`)
-			p1v3Node := playerHighlightRow.Find(`td:nth-child(9) > div > div > div`)
-			p1v4Node := playerHighlightRow.Find(`td:nth-child(10) > div > div > div`)
-			p1v5Node := playerHighlightRow.Find(`td:nth-child(11) > div > div > div`)
+	var playerHighlightRows []playerHighlightRow
+
+	if err := htmlx.ParseTable(&playerHighlightRows, mapPerformanceNode.Find(playerHighlightTableSelector)); err != nil {
+		return fmt.Errorf("Error parsing player highlights table: %s", err.Error())
+	}
 
+	if err := sink.Transaction(func(highlightsSink sinks.Sink) error {
+		for i, playerHighlightRow := range playerHighlightRows {
 			var teamId int
 			teamHashmap := map[string]int{}
 			otherTeamHashmap := map[string]int{}
@@ -58,26 +66,35 @@ func scrapePlayersHighlights(
 				otherTeamHashmap = t1Hashmap
 			}
 
-			playerName := strings.TrimSpace(pNameNode.Find(playerNameSelector).Clone().Children().Remove().End().Text())
+			playerName := playerHighlightRow.PlayerName
 
 			playerId, ok := teamHashmap[playerName]
 			if !ok {
 				return fmt.Errorf("Player name '%s' doesn't exists in hashmap", playerName)
 			}
 
+			highlightNodes := []struct {
+				node          *goquery.Selection
+				highlightType models.HighlightType
+			}{
+				{playerHighlightRow.P2k, models.P2k},
+				{playerHighlightRow.P3k, models.P3k},
+				{playerHighlightRow.P4k, models.P4k},
+				{playerHighlightRow.P5k, models.P5k},
+				{playerHighlightRow.P1v1, models.P1v1},
+				{playerHighlightRow.P1v2, models.P1v2},
+				{playerHighlightRow.P1v3, models.P1v3},
+				{playerHighlightRow.P1v4, models.P1v4},
+				{playerHighlightRow.P1v5, models.P1v5},
+			}
+
 			errChan := make(chan error)
 			doneChan := make(chan bool)
 
 			go func() {
-				errChan <- scrapeHighlight(ptx, sc, matchMapSchema, p2kNode, models.P2k, teamId, playerId, otherTeamHashmap)
-				errChan <- scrapeHighlight(ptx, sc, matchMapSchema, p3kNode, models.P3k, teamId, playerId, otherTeamHashmap)
-				errChan <- scrapeHighlight(ptx, sc, matchMapSchema, p4kNode, models.P4k, teamId, playerId, otherTeamHashmap)
-				errChan <- scrapeHighlight(ptx, sc, matchMapSchema, p5kNode, models.P5k, teamId, playerId, otherTeamHashmap)
-				errChan <- scrapeHighlight(ptx, sc, matchMapSchema, p1v1Node, models.P1v1, teamId, playerId, otherTeamHashmap)
-				errChan <- scrapeHighlight(ptx, sc, matchMapSchema, p1v2Node, models.P1v2, teamId, playerId, otherTeamHashmap)
-				errChan <- scrapeHighlight(ptx, sc, matchMapSchema, p1v3Node, models.P1v3, teamId, playerId, otherTeamHashmap)
-				errChan <- scrapeHighlight(ptx, sc, matchMapSchema, p1v4Node, models.P1v4, teamId, playerId, otherTeamHashmap)
-				errChan <- scrapeHighlight(ptx, sc, matchMapSchema, p1v5Node, models.P1v5, teamId, playerId, otherTeamHashmap)
+				for _, highlightNode := range highlightNodes {
+					errChan <- scrapeHighlight(tx, highlightsSink, sc, matchMapSchema, highlightNode.node, highlightNode.highlightType, teamId, playerId, otherTeamHashmap)
+				}
 
 				doneChan <- true
 			}()
@@ -107,6 +124,7 @@ func scrapePlayersHighlights(
 
 func scrapeHighlight(
 	tx *gorm.DB,
+	sink sinks.Sink,
 	sc *piper.Scraper,
 	matchMapSchema models.MatchMapSchema,
 	highlightNode *goquery.Selection,
@@ -125,11 +143,10 @@ func scrapeHighlight(
 			OtherTeamHashMap: otherTeamHashmap,
 		}
 
-		ctx := context.WithValue(context.WithValue(context.Background(), "data", &data), "tx", tx)
+		ctx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), "data", &data), "tx", tx), sink)
 
 		if err := sc.Pipe("highlights", ctx, highlightNode.Children().Eq(i)); err != nil {
-			fmt.Printf("player id: %d\n", playerId)
-			fmt.Printf("other team hash map: %v\n", otherTeamHashmap)
+			logrus.Tracef("Player id: %d, other team hash map: %v", playerId, otherTeamHashmap)
 			return err
 		}
 
@@ -145,7 +162,6 @@ func scrapeHighlight(
 
 func prettyPrintHighlight(highlightLog []models.PlayerHighlightSchema) {
 	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
 
 	var row table.Row
 
@@ -163,5 +179,5 @@ func prettyPrintHighlight(highlightLog []models.PlayerHighlightSchema) {
 	t.AppendHeader(table.Row{"ROUND", "TYPE", "PLAYER ID", "PLAYERS AGAINST ID"})
 	t.AppendRow(row)
 
-	t.Render()
+	logrus.Tracef("Highlight:\n%s", t.Render())
 }
@@ -3,13 +3,14 @@ package matchmaps
 import (
 	"context"
 	"fmt"
-	"os"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/jedib0t/go-pretty/table"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/scrapers/playerstats"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
@@ -20,6 +21,7 @@ const (
 
 func scrapePlayersStats(
 	tx *gorm.DB,
+	sink sinks.Sink,
 	sc *piper.Scraper,
 	matchMapSchema models.MatchMapSchema,
 	mapOverviewNode *goquery.Selection,
@@ -37,7 +39,6 @@ func scrapePlayersStats(
 	t2Hashmap := map[string]int{}
 
 	pStatsTable := table.NewWriter()
-	pStatsTable.SetOutputMirror(os.Stdout)
 	pStatsTable.AppendHeader(table.Row{"Rating", "Acs", "K", "D", "A", "KAST", "ADR", "HS", "FK", "FD"})
 
 	for i := range t1PlayerStatsNodes.Length() {
@@ -61,7 +62,7 @@ func scrapePlayersStats(
 			TeamAtkRounds: t1AtkRounds,
 		}
 
-		ctx := context.WithValue(context.WithValue(context.Background(), "data", &data), "tx", tx)
+		ctx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), "data", &data), "tx", tx), sink)
 
 		if err := sc.Pipe("playerStats", ctx, t1PlayerStatsNodes.Eq(i)); err != nil {
 			return nil, nil, err
@@ -105,7 +106,7 @@ func scrapePlayersStats(
 			TeamAtkRounds: t2AtkRounds,
 		}
 
-		ctx := context.WithValue(context.WithValue(context.Background(), "data", &data), "tx", tx)
+		ctx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), "data", &data), "tx", tx), sink)
 
 		if err := sc.Pipe("playerStats", ctx, t2PlayerStatsNodes.Eq(i)); err != nil {
 			return nil, nil, err
@@ -126,7 +127,7 @@ func scrapePlayersStats(
 		})
 	}
 
-	pStatsTable.Render()
+	logrus.Tracef("Players stats table:\n%s", pStatsTable.Render())
 
 	return t1Hashmap, t2Hashmap, nil
 }
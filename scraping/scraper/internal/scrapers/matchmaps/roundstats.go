@@ -3,17 +3,19 @@ package matchmaps
 import (
 	"context"
 	"fmt"
-	"os"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/jedib0t/go-pretty/table"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 func scrapeRoundsStats(
 	tx *gorm.DB,
+	sink sinks.Sink,
 	sc *piper.Scraper,
 	matchMapSchema models.MatchMapSchema,
 	mapOverviewNode *goquery.Selection,
@@ -29,12 +31,11 @@ func scrapeRoundsStats(
 	var wonMethods table.Row
 
 	roundsTable := table.NewWriter()
-	roundsTable.SetOutputMirror(os.Stdout)
 
 	roundsOverviewNodes := mapOverviewNode.Find(roundOverviewSelector)
 	roundsEconomyNodes := mapEconomyNode.Find(roundEconomySelector)
 
-	if err := tx.Transaction(func(rtx *gorm.DB) error {
+	if err := sink.Transaction(func(roundsSink sinks.Sink) error {
 
 		for i := range roundsOverviewNodes.Length() {
 			roundOverviewNode := roundsOverviewNodes.Eq(i)
@@ -49,7 +50,10 @@ func scrapeRoundsStats(
 				Team2Id: matchMapSchema.Team2Id,
 			}
 
-			roundCtx := context.WithValue(context.WithValue(context.Background(), "roundStat", &roundStat), "tx", rtx)
+			roundCtx := sinks.WithSink(
+				context.WithValue(context.WithValue(context.Background(), "roundStat", &roundStat), "tx", tx),
+				roundsSink,
+			)
 
 			if err := sc.Pipe("roundStat", roundCtx, combined); err != nil {
 				return err
@@ -88,10 +92,9 @@ func scrapeRoundsStats(
 		roundsTable.AppendHeader(append(table.Row{"WON"}, teamsWon[start:end]...))
 		roundsTable.AppendHeader(append(table.Row{"METHOD"}, wonMethods[start:end]...))
 
-		roundsTable.Render()
+		logrus.Tracef("Rounds table:\n%s", roundsTable.Render())
 
 		roundsTable = table.NewWriter()
-		roundsTable.SetOutputMirror(os.Stdout)
 	}
 
 	return nil
@@ -8,23 +8,19 @@ import (
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"github.com/sirupsen/logrus"
-	"gorm.io/gorm"
 )
 
-var parsers = map[string]htmlx.Parser{
-	"duelParser": htmlx.IfNullParser(0, htmlx.IntParser),
-}
-
 func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection) error {
 	duelStats, ok := ctx.Value("duelStats").(*models.PlayerDuelStatSchema)
 	if !ok {
 		return fmt.Errorf("Unable to find player duel stats")
 	}
 
-	tx, ok := ctx.Value("tx").(*gorm.DB)
-	if !ok {
-		return fmt.Errorf("Unable to find gorm transaction")
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
 	}
 
 	duelKillsNode := selection.Eq(0)
@@ -36,17 +32,17 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 	var duelOpKills models.DuelOpKills
 
 	logrus.Debug("Parsing player duel kills information from html onto match schema")
-	if err := htmlx.ParseFromSelection(&duelKills, duelKillsNode, htmlx.SetParsers(parsers)); err != nil {
+	if err := htmlx.ParseFromSelection(&duelKills, duelKillsNode); err != nil {
 		return err
 	}
 
 	logrus.Debug("Parsing player duel first kills information from html onto match schema")
-	if err := htmlx.ParseFromSelection(&duelFirstKills, duelFirstKillsNode, htmlx.SetParsers(parsers)); err != nil {
+	if err := htmlx.ParseFromSelection(&duelFirstKills, duelFirstKillsNode); err != nil {
 		return err
 	}
 
 	logrus.Debug("Parsing player duel op kills information from html onto match schema")
-	if err := htmlx.ParseFromSelection(&duelOpKills, duelOpKillsNode, htmlx.SetParsers(parsers)); err != nil {
+	if err := htmlx.ParseFromSelection(&duelOpKills, duelOpKillsNode); err != nil {
 		return err
 	}
 
@@ -54,8 +50,8 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 	duelStats.DuelFirstKills = duelFirstKills
 	duelStats.DuelOpKills = duelOpKills
 
-	logrus.Debug("Saving player duel stats to db")
-	if err := tx.Table("players_duel_stats").Create(duelStats).Error; err != nil {
+	logrus.Debug("Saving player duel stats")
+	if err := sink.SaveDuel(duelStats); err != nil {
 		return err
 	}
 
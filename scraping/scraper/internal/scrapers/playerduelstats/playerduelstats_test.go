@@ -11,6 +11,7 @@ import (
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/helpers"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -114,7 +115,7 @@ func TestPlayerDuelStat(t *testing.T) {
 		var duelStats models.PlayerDuelStatSchema
 
 		ctx := context.WithValue(context.Background(), "duelStats", &duelStats)
-		ctx2 := context.WithValue(ctx, "tx", tx)
+		ctx2 := sinks.WithSink(context.WithValue(ctx, "tx", tx), sinks.NewSQLite(tx))
 
 		if err := sc.Pipe("duelStats", ctx2, duelNodes); err != nil {
 			t.Fatal(err)
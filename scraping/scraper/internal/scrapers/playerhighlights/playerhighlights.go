@@ -9,6 +9,7 @@ import (
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -40,9 +41,9 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 		return fmt.Errorf("Unable to find data for player highlight")
 	}
 
-	tx, ok := ctx.Value("tx").(*gorm.DB)
-	if !ok {
-		return fmt.Errorf("Unable to find gorm transaction")
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
 	}
 
 	logrus.Debug("Getting player highlight round no")
@@ -78,9 +79,9 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 		})
 	}
 
-	logrus.Debug("Saving highlights to db")
+	logrus.Debug("Saving highlights")
 	for _, highlight := range data.HighlightLog {
-		if err := tx.Table("player_highlights").Create(&highlight).Error; err != nil {
+		if err := sink.SaveHighlight(&highlight); err != nil {
 			return err
 		}
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -174,7 +175,7 @@ func TestPlayerHightlights(t *testing.T) {
 			}
 
 			ctx := context.WithValue(context.Background(), "data", &data)
-			ctx2 := context.WithValue(ctx, "tx", tx)
+			ctx2 := sinks.WithSink(context.WithValue(ctx, "tx", tx), sinks.NewSQLite(tx))
 
 			if err := sc.Pipe("highlights", ctx2, highlightNode); err != nil {
 				t.Fatal(err)
@@ -2,7 +2,6 @@ package players
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -11,6 +10,7 @@ import (
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/utils/geographyinfo"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -99,6 +99,11 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 		return fmt.Errorf("Unable to find the transaction")
 	}
 
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
 	logrus.Debug("Scraping player information")
 	if err := htmlx.ParseFromSelection(p, selection, htmlx.SetParsers(map[string]htmlx.Parser{
 		"countryIdParser": countryIdParser(tx),
@@ -106,15 +111,8 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 		return err
 	}
 
-	jsonDat, err := json.MarshalIndent(*p, "", "	")
-	if err != nil {
-		return err
-	}
-
-	fmt.Println(string(jsonDat))
-
-	logrus.Debug("Saving player to db")
-	if err := tx.Table("players").Create(p).Error; err != nil {
+	logrus.Debug("Saving player")
+	if err := sink.SavePlayer(p); err != nil {
 		return err
 	}
 
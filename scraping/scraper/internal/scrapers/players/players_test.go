@@ -14,6 +14,7 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -80,7 +81,7 @@ func TestPlayerFullInfo(t *testing.T) {
 		}
 
 		ctx := context.WithValue(context.Background(), "player", &p)
-		ctx2 := context.WithValue(ctx, "tx", tx)
+		ctx2 := sinks.WithSink(context.WithValue(ctx, "tx", tx), sinks.NewSQLite(tx))
 
 		if err := sc.Get(playerUrl, ctx2, nil); err != nil {
 			t.Fatal(err)
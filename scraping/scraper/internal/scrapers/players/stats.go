@@ -0,0 +1,187 @@
+package players
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/customparsers"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	playerStatsUrl = "https://www.vlr.gg/player/%d/?timespan=%s"
+
+	teamsLabelSelector = "h2.wf-label"
+	teamItemSelector   = "a.wf-module-item"
+)
+
+var monthYearRegex = regexp.MustCompile(`(January|February|March|April|May|June|July|August|September|October|November|December) ([0-9]{4})`)
+
+// Return the url of the player page listing the agent stats over the timespan, see [StatsHandler]
+func StatsUrl(playerId int, timespan models.StatsTimespan) string {
+	return fmt.Sprintf(playerStatsUrl, playerId, timespan)
+}
+
+// Return true if the timespan is one of the timespans of the player page
+func IsTimespan(timespan models.StatsTimespan) bool {
+	switch timespan {
+	case models.Timespan30Days, models.Timespan60Days, models.Timespan90Days, models.TimespanAll:
+		return true
+	default:
+		return false
+	}
+}
+
+// Return the agents table of the player page, the table with an ACS column
+func agentStatsTable(selection *goquery.Selection) *goquery.Selection {
+	return selection.Find("table").FilterFunction(func(_ int, table *goquery.Selection) bool {
+		return htmlx.ReadTableHeader(table).Index("ACS") >= 0
+	}).First()
+}
+
+// Return the agent stats of the player page, snapshot hold the player, the snapshot date and the timespan of the page
+func parseAgentStats(selection *goquery.Selection, snapshot models.PlayerAgentStatSchema, tx *gorm.DB) ([]models.PlayerAgentStatSchema, error) {
+	table := agentStatsTable(selection)
+	if table.Length() == 0 {
+		return nil, nil
+	}
+
+	var stats []models.PlayerAgentStatSchema
+	if err := htmlx.ParseTable(&stats, table, htmlx.SetParsers(map[string]htmlx.Parser{
+		"agentParser": customparsers.AgentIdParser(tx),
+	})); err != nil {
+		return nil, err
+	}
+
+	for i := range stats {
+		stats[i].PlayerId, stats[i].SnapshotDate, stats[i].Timespan = snapshot.PlayerId, snapshot.SnapshotDate, snapshot.Timespan
+	}
+
+	return stats, nil
+}
+
+// Return the first day of the months written in the period, e.g "March 2021 – February 2023"
+func periodDates(period string) []time.Time {
+	var dates []time.Time
+
+	for _, match := range monthYearRegex.FindAllString(period, -1) {
+		date, err := time.Parse("January 2006", match)
+		if err != nil {
+			continue
+		}
+
+		dates = append(dates, date)
+	}
+
+	return dates
+}
+
+// Return the teams of the current and past teams of the player page. The period under a current team is the month
+// the player joined it, e.g "joined in March 2023", and under a past team the months the player joined and left it
+func parsePlayerTeams(selection *goquery.Selection, playerId int, snapshotDate time.Time) ([]models.PlayerTeamSchema, error) {
+	var playerTeams []models.PlayerTeamSchema
+
+	for _, label := range selection.Find(teamsLabelSelector).EachIter() {
+		labelText := strings.ToLower(label.Text())
+
+		current := strings.Contains(labelText, "current teams")
+		if !current && !strings.Contains(labelText, "past teams") {
+			continue
+		}
+
+		for _, item := range label.Next().Find(teamItemSelector).EachIter() {
+			teamId, err := customparsers.IdParser(item.AttrOr("href", ""))
+			if err != nil {
+				return nil, fmt.Errorf("Invalid team of player %d: %s", playerId, err.Error())
+			}
+
+			playerTeam := models.PlayerTeamSchema{PlayerId: playerId, SnapshotDate: snapshotDate, TeamId: teamId.(int), Current: current}
+
+			period := item.Find("div.ge-text-light").Text()
+			dates := periodDates(period)
+
+			switch {
+			case len(dates) >= 2:
+				playerTeam.JoinedAt, playerTeam.LeftAt = &dates[0], &dates[len(dates)-1]
+			case len(dates) == 1 && !current && strings.Contains(strings.ToLower(period), "left"):
+				playerTeam.LeftAt = &dates[0]
+			case len(dates) == 1:
+				playerTeam.JoinedAt = &dates[0]
+			}
+
+			playerTeams = append(playerTeams, playerTeam)
+		}
+	}
+
+	return playerTeams, nil
+}
+
+// StatsHandler save the agent stats and the team history of the player page of a timespan, the player is scraped if
+// it isn't saved. The teams of the history are not scraped
+func StatsHandler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection) error {
+	snapshot, ok := ctx.Value("playerAgentStat").(*models.PlayerAgentStatSchema)
+	if !ok {
+		return fmt.Errorf("Unable to find player agent stat schema")
+	}
+
+	tx, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("Unable to find the transaction")
+	}
+
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	exists, err := sink.Exists(sinks.Players, snapshot.PlayerId)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		logrus.Debugf("Player %d doesn't exists, start scraping player", snapshot.PlayerId)
+		p := models.PlayerSchema{Id: snapshot.PlayerId, Url: fmt.Sprintf("https://www.vlr.gg/player/%d/", snapshot.PlayerId)}
+
+		playerCtx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), "player", &p), "tx", tx), sink)
+
+		if err := sc.Get(p.Url, playerCtx, nil); err != nil {
+			return err
+		}
+	}
+
+	stats, err := parseAgentStats(selection, *snapshot, tx)
+	if err != nil {
+		return err
+	}
+
+	logrus.Debugf("Saving %d agent stats of player %d", len(stats), snapshot.PlayerId)
+	for _, stat := range stats {
+		if err := sink.SavePlayerAgentStat(&stat); err != nil {
+			return err
+		}
+	}
+
+	playerTeams, err := parsePlayerTeams(selection, snapshot.PlayerId, snapshot.SnapshotDate)
+	if err != nil {
+		return err
+	}
+
+	logrus.Debugf("Saving %d teams of player %d", len(playerTeams), snapshot.PlayerId)
+	for _, playerTeam := range playerTeams {
+		if err := sink.SavePlayerTeam(&playerTeam); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package players
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const playerStatsHtml = `<div class="player-summary-container-1">
+	<div class="wf-card">
+		<table class="wf-table">
+			<thead><tr>
+				<th></th><th title="Usage">Use</th><th title="Rounds Played">RND</th><th title="Rating 2.0">Rating2.0</th>
+				<th title="Average Combat Score">ACS</th><th title="Kills / Deaths">K:D</th><th title="Average Damage per Round">ADR</th>
+				<th title="Kill, Assist, Trade, Survive %">KAST</th><th title="Kills Per Round">KPR</th><th title="Assists Per Round">APR</th>
+				<th title="First Kills Per Round">FKPR</th><th title="First Deaths Per Round">FDPR</th>
+			</tr></thead>
+			<tbody>
+				<tr>
+					<td><img src="/img/vlr/game/agents/jett.png" alt="jett" title="Jett"></td>
+					<td><span>(52) 67%</span></td><td>1,104</td><td>1.14</td><td>241.3</td><td>1.21</td><td>151.2</td>
+					<td>72%</td><td>0.83</td><td>0.17</td><td>0.16</td><td>0.11</td>
+				</tr>
+				<tr>
+					<td><img src="/img/vlr/game/agents/raze.png" alt="raze" title="Raze"></td>
+					<td><span>(3) 4%</span></td><td>57</td><td></td><td>198.0</td><td>0.95</td><td>130.4</td>
+					<td>65%</td><td>0.70</td><td>0.28</td><td>0.09</td><td>0.12</td>
+				</tr>
+			</tbody>
+		</table>
+	</div>
+</div>
+<div class="player-summary-container-2">
+	<h2 class="wf-label mod-large">Current Teams</h2>
+	<div class="wf-card">
+		<a href="/team/624/paper-rex" class="wf-module-item mod-first">
+			<div style="flex: 1;">
+				<div style="font-weight: 500;" class="text-of">Paper Rex</div>
+				<div class="ge-text-light">joined in March 2023</div>
+			</div>
+		</a>
+	</div>
+	<h2 class="wf-label mod-large">Past Teams</h2>
+	<div class="wf-card">
+		<a href="/team/2/sentinels" class="wf-module-item mod-first">
+			<div style="flex: 1;">
+				<div style="font-weight: 500;" class="text-of">Sentinels</div>
+				<div class="ge-text-light">June 2021 – February 2023</div>
+			</div>
+		</a>
+		<a href="/team/5/old-team" class="wf-module-item">
+			<div style="flex: 1;">
+				<div style="font-weight: 500;" class="text-of">Old Team</div>
+				<div class="ge-text-light">inactive</div>
+			</div>
+		</a>
+	</div>
+</div>`
+
+func openAgentsDb(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.Table("agents").AutoMigrate(&models.AgentSchema{}); err != nil {
+		t.Fatal(err)
+	}
+
+	agents := []models.AgentSchema{
+		{Name: "Jett", AgentType: models.Duelist, ReleaseDate: "2020-04-07"},
+		{Name: "Raze", AgentType: models.Duelist, ReleaseDate: "2020-04-07"},
+	}
+
+	if err = db.Table("agents").Create(&agents).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestStatsHandler(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(playerStatsHtml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The player is saved so that it is not scraped
+	memory := sinks.NewMemory()
+	if err = memory.SavePlayer(&models.PlayerSchema{Id: 9801}); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotDate := time.Date(2025, 10, 19, 0, 0, 0, 0, time.UTC)
+	snapshot := models.PlayerAgentStatSchema{PlayerId: 9801, SnapshotDate: snapshotDate, Timespan: models.Timespan90Days}
+
+	ctx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), "playerAgentStat", &snapshot), "tx", openAgentsDb(t)), memory)
+	if err = StatsHandler(nil, ctx, doc.Selection); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(memory.PlayerAgentStats) != 2 {
+		t.Fatalf("Want 2 agent stats, get %+v", memory.PlayerAgentStats)
+	}
+
+	jett, raze := memory.PlayerAgentStats[0], memory.PlayerAgentStats[1]
+
+	if jett.PlayerId != 9801 || !jett.SnapshotDate.Equal(snapshotDate) || jett.Timespan != models.Timespan90Days || jett.AgentId != 1 ||
+		jett.RoundsPlayed == nil || *jett.RoundsPlayed != 1104 || jett.Rating == nil || *jett.Rating != 1.14 || *jett.Acs != 241.3 ||
+		*jett.KillDeathRatio != 1.21 || *jett.Adr != 151.2 || *jett.Kast != 72 || *jett.Kpr != 0.83 || *jett.Apr != 0.17 ||
+		*jett.Fkpr != 0.16 || *jett.Fdpr != 0.11 || jett.Hs != nil {
+		t.Errorf("Wrong Jett stats %+v", jett)
+	}
+
+	if raze.AgentId != 2 || raze.RoundsPlayed == nil || *raze.RoundsPlayed != 57 || raze.Rating != nil {
+		t.Errorf("Wrong Raze stats %+v", raze)
+	}
+
+	if len(memory.PlayerTeams) != 3 {
+		t.Fatalf("Want 3 teams, get %+v", memory.PlayerTeams)
+	}
+
+	month := func(year int, month time.Month) time.Time {
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	paperRex, sentinels, oldTeam := memory.PlayerTeams[0], memory.PlayerTeams[1], memory.PlayerTeams[2]
+
+	if paperRex.TeamId != 624 || !paperRex.Current || paperRex.JoinedAt == nil || !paperRex.JoinedAt.Equal(month(2023, time.March)) || paperRex.LeftAt != nil {
+		t.Errorf("Wrong current team %+v", paperRex)
+	}
+
+	if sentinels.TeamId != 2 || sentinels.Current || sentinels.JoinedAt == nil || !sentinels.JoinedAt.Equal(month(2021, time.June)) ||
+		sentinels.LeftAt == nil || !sentinels.LeftAt.Equal(month(2023, time.February)) {
+		t.Errorf("Wrong past team %+v", sentinels)
+	}
+
+	if oldTeam.TeamId != 5 || oldTeam.Current || oldTeam.JoinedAt != nil || oldTeam.LeftAt != nil || !oldTeam.SnapshotDate.Equal(snapshotDate) {
+		t.Errorf("Wrong past team without period %+v", oldTeam)
+	}
+}
@@ -3,7 +3,6 @@ package playerstats
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
@@ -11,6 +10,7 @@ import (
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/customparsers"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/helpers"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -24,20 +24,6 @@ type Data struct {
 	PlayerName    string `selector:"td.mod-player > div > a > div:nth-child(1)"`
 }
 
-func agentParser(tx *gorm.DB) htmlx.Parser {
-	return func(rawVal string) (any, error) {
-		agentName := strings.TrimSpace(rawVal)
-		var agent models.AgentSchema
-
-		rs := tx.Table("agents").Where("name = ?", agentName).First(&agent)
-		if rs.Error != nil {
-			return nil, rs.Error
-		}
-
-		return agent.Id, nil
-	}
-}
-
 func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection) error {
 	var err error
 
@@ -51,6 +37,11 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 		return fmt.Errorf("Unable to find both the transaction")
 	}
 
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
 	defStatNode := selection.Clone()
 	defStatNode.Find("span.mod-t, span.mod-both").Remove()
 
@@ -63,27 +54,29 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 	// WARNING: Player name will be extracted from match map scraper, not here anymore
 
 	parsers := map[string]htmlx.Parser{
-		"agentParser":    agentParser(tx),
-		"playerIdParser": customparsers.IdParser,
+		"agentParser": customparsers.AgentIdParser(tx),
 	}
 
+	// The side nodes are detached clones of the row, so the header is read from the original one
+	header := htmlx.SetTableHeader(htmlx.ReadTableHeader(selection))
+
 	logrus.Debug("Parsing player name")
 	if err := htmlx.ParseFromSelection(data, selection, htmlx.SetNoPassThroughStruct(true)); err != nil {
 		return err
 	}
 
 	logrus.Debug("Parsing player def stats")
-	if err := htmlx.ParseFromSelection(&data.DefStat, defStatNode, htmlx.SetParsers(parsers)); err != nil {
+	if err := htmlx.ParseFromSelection(&data.DefStat, defStatNode, htmlx.SetParsers(parsers), header); err != nil {
 		return err
 	}
 
 	logrus.Debug("Parsing player atk stats")
-	if err := htmlx.ParseFromSelection(&data.AtkStat, atkStatNode, htmlx.SetParsers(parsers)); err != nil {
+	if err := htmlx.ParseFromSelection(&data.AtkStat, atkStatNode, htmlx.SetParsers(parsers), header); err != nil {
 		return err
 	}
 
 	logrus.Debug("Parsing player both side stats")
-	if err := htmlx.ParseFromSelection(&data.BothSideStat, bothSideStatNode, htmlx.SetParsers(parsers)); err != nil {
+	if err := htmlx.ParseFromSelection(&data.BothSideStat, bothSideStatNode, htmlx.SetParsers(parsers), header); err != nil {
 		return err
 	}
 
@@ -138,18 +131,18 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 	}
 
 	logrus.Debug("Saving player def stat to db")
-	if err := tx.Table("player_overview_stats").Create(&data.DefStat).Error; err != nil {
+	if err := sink.SavePlayerStat(&data.DefStat); err != nil {
 		return err
 	}
 
 	logrus.Debug("Saving player atk stat to db")
-	if err := tx.Table("player_overview_stats").Create(&data.AtkStat).Error; err != nil {
+	if err := sink.SavePlayerStat(&data.AtkStat); err != nil {
 		return err
 	}
 
 	logrus.Debug("Check if player already exists")
-	var exists bool
-	if err := tx.Table("players").Select("count(*) > 0").Where("id = ?", data.DefStat.PlayerId).Find(&exists).Error; err != nil {
+	exists, err := sink.Exists(sinks.Players, data.DefStat.PlayerId)
+	if err != nil {
 		return err
 	}
 
@@ -160,7 +153,7 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 			Url: fmt.Sprintf("https://www.vlr.gg/player/%d/", data.DefStat.PlayerId),
 		}
 
-		ctx := context.WithValue(context.WithValue(context.Background(), "player", &p), "tx", tx)
+		ctx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), "player", &p), "tx", tx), sink)
 
 		if err := sc.Get(p.Url, ctx, nil); err != nil {
 			return err
@@ -10,6 +10,7 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -89,7 +90,7 @@ func TestPlayerStat(t *testing.T) {
 				TeamAtkRounds: 12,
 			}
 			ctx := context.WithValue(context.Background(), "data", &data)
-			ctx2 := context.WithValue(ctx, "tx", tx)
+			ctx2 := sinks.WithSink(context.WithValue(ctx, "tx", tx), sinks.NewSQLite(tx))
 
 			if err := sc.Pipe("playerStats", ctx2, doc.Selection.Find(selector)); err != nil {
 				t.Fatal(err)
@@ -110,7 +111,7 @@ func TestPlayerStat(t *testing.T) {
 				TeamAtkRounds: 4,
 			}
 			ctx := context.WithValue(context.Background(), "data", &data)
-			ctx2 := context.WithValue(ctx, "tx", tx)
+			ctx2 := sinks.WithSink(context.WithValue(ctx, "tx", tx), sinks.NewSQLite(tx))
 
 			if err := sc.Pipe("playerStats", ctx2, doc.Selection.Find(selector)); err != nil {
 				t.Fatal(err)
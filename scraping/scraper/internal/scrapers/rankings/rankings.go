@@ -0,0 +1,176 @@
+// Package rankings scrape the snapshots of the team rankings of vlr.gg, see [models.TeamRankingSchema]
+package rankings
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
+	_ "github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/customparsers" // Register idParser
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	RankingsUrl = "https://www.vlr.gg/rankings"
+
+	rankItemSelector   = "div.rank-item"
+	regionLinkSelector = `a[href^="/rankings/"]`
+)
+
+var (
+	regionSlugRegex = regexp.MustCompile(`^[a-z-]+$`)
+	streakRegex     = regexp.MustCompile(`^([0-9]+)([WL])$`)
+)
+
+// Return true if the region is the slug of a rankings page, e.g "north-america"
+func IsRegionSlug(region string) bool {
+	return regionSlugRegex.MatchString(region)
+}
+
+// Return the url of the rankings page of the region
+func RegionUrl(region string) string {
+	return RankingsUrl + "/" + region
+}
+
+// Parse the streak of the rank item, e.g "5W" or "2L", nil if the team has no streak
+func streakParser(rawVal string) (any, error) {
+	streakStr := strings.ToUpper(strings.Join(strings.Fields(rawVal), ""))
+	if streakStr == "" {
+		return nil, nil
+	}
+
+	matches := streakRegex.FindStringSubmatch(streakStr)
+	if matches == nil {
+		return nil, fmt.Errorf("Invalid streak '%s'", streakStr)
+	}
+
+	streak, _ := strconv.Atoi(matches[1])
+	if matches[2] == "L" {
+		streak = -streak
+	}
+
+	return streak, nil
+}
+
+// Return the regions linked from the global rankings page, in the order of the page
+func regionSlugs(selection *goquery.Selection) []string {
+	var regions []string
+	seen := map[string]bool{}
+
+	for _, link := range selection.Find(regionLinkSelector).EachIter() {
+		region := strings.Trim(strings.TrimPrefix(link.AttrOr("href", ""), "/rankings/"), "/")
+		if !IsRegionSlug(region) || seen[region] {
+			continue
+		}
+
+		seen[region] = true
+		regions = append(regions, region)
+	}
+
+	return regions
+}
+
+// Return the ranks of the rankings page of a region, snapshot hold the snapshot date and the region of the page
+func parseRankings(selection *goquery.Selection, snapshot models.TeamRankingSchema) ([]models.TeamRankingSchema, error) {
+	var rankings []models.TeamRankingSchema
+
+	for _, item := range selection.Find(rankItemSelector).EachIter() {
+		ranking := snapshot
+
+		if err := htmlx.ParseFromSelection(&ranking, item, htmlx.SetParsers(map[string]htmlx.Parser{
+			"streakParser": streakParser,
+		})); err != nil {
+			return nil, err
+		}
+
+		rankings = append(rankings, ranking)
+	}
+
+	return rankings, nil
+}
+
+// Handler scrape the rankings page of every region linked from the global rankings page. The global page is not
+// saved, it only list the top teams of every region with their rank in the region, which the region pages already have
+func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection) error {
+	snapshot, ok := ctx.Value("teamRanking").(*models.TeamRankingSchema)
+	if !ok {
+		return fmt.Errorf("Unable to find the team ranking schema")
+	}
+
+	regions := regionSlugs(selection)
+	if len(regions) == 0 {
+		return fmt.Errorf("Unable to find the regions of the rankings page")
+	}
+
+	for _, region := range regions {
+		regionSnapshot := *snapshot
+		regionSnapshot.Region = region
+
+		logrus.Debugf("Scraping rankings of %s", region)
+		if err := sc.Get(RegionUrl(region), context.WithValue(ctx, "teamRanking", &regionSnapshot), nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RegionHandler save the ranks of the rankings page of a region, the teams which are not saved are scraped
+func RegionHandler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection) error {
+	snapshot, ok := ctx.Value("teamRanking").(*models.TeamRankingSchema)
+	if !ok {
+		return fmt.Errorf("Unable to find the team ranking schema")
+	}
+
+	tx, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("Unable to find gorm transaction")
+	}
+
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	rankings, err := parseRankings(selection, *snapshot)
+	if err != nil {
+		return err
+	}
+
+	if len(rankings) == 0 {
+		return fmt.Errorf("No team found on the rankings page of %s", snapshot.Region)
+	}
+
+	for _, ranking := range rankings {
+		exists, err := sink.Exists(sinks.Teams, ranking.TeamId)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			logrus.Debugf("Team %d doesn't exists, start scraping team", ranking.TeamId)
+			teamSchema := models.TeamSchema{Id: ranking.TeamId, Url: fmt.Sprintf("https://www.vlr.gg/team/%d/", ranking.TeamId)}
+
+			teamCtx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), "teamSchema", &teamSchema), "tx", tx), sink)
+
+			if err := sc.Get(teamSchema.Url, teamCtx, nil); err != nil {
+				return err
+			}
+		}
+
+		if err := sink.SaveTeamRanking(&ranking); err != nil {
+			return err
+		}
+	}
+
+	logrus.Infof("%d teams ranked in %s", len(rankings), snapshot.Region)
+	return nil
+}
@@ -0,0 +1,121 @@
+package rankings
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"gorm.io/gorm"
+)
+
+const globalRankingsHtml = `<div class="wf-nav">
+	<a href="/rankings" class="wf-nav-item mod-active">World</a>
+	<a href="/rankings/europe" class="wf-nav-item">Europe</a>
+	<a href="/rankings/north-america" class="wf-nav-item">North America</a>
+</div>
+<div class="world-rankings-col">
+	<a href="/rankings/europe" class="wf-module-item">view all</a>
+</div>`
+
+func rankItem(rank, teamHref, rating, record, streak string) string {
+	item := `<div class="rank-item wf-card fc-flex">
+		<div class="rank-item-rank"><a href="` + teamHref + `" class="rank-item-rank-num">` + rank + `</a></div>
+		<div class="rank-item-team fc-flex"><a href="` + teamHref + `" class="fc-flex">
+			<div class="ge-text">Team <span class="ge-text-light">#1</span></div>
+			<div class="rank-item-team-country">Europe</div>
+		</a></div>
+		<div class="rank-item-rating"><a href="` + teamHref + `">` + rating + `</a></div>`
+	if streak != "" {
+		item += `<div class="rank-item-streak mod-right"><a href="` + teamHref + `"><span>` + streak + `</span></a></div>`
+	}
+
+	return item + `<div class="rank-item-record"><a href="` + teamHref + `">` + record + `</a></div></div>`
+}
+
+func TestRegionSlugs(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(globalRankingsHtml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if regions := regionSlugs(doc.Selection); !slices.Equal(regions, []string{"europe", "north-america"}) {
+		t.Errorf("Want the regions europe and north-america, get %v", regions)
+	}
+}
+
+func TestStreakParser(t *testing.T) {
+	for streakStr, want := range map[string]int{"5W": 5, " 2 L ": -2, "12w": 12} {
+		streak, err := streakParser(streakStr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if streak != want {
+			t.Errorf("%q: want streak %d, get %v", streakStr, want, streak)
+		}
+	}
+
+	if streak, err := streakParser(""); streak != nil || err != nil {
+		t.Errorf("Want no streak without value, get %v, %v", streak, err)
+	}
+
+	if _, err := streakParser("W5"); err == nil {
+		t.Error("Want an error for an invalid streak")
+	}
+}
+
+func TestRegionHandler(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<div class="mod-scroll">` +
+		rankItem("\n\t1\n", "/team/474/team-heretics", "1977", "46–20", "3W") +
+		rankItem("2", "/team/1001/team-liquid", "1905", "35–23", "1L") +
+		rankItem("3", "/team/2059/fnatic", "", "", "") +
+		`</div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The teams are saved so that they are not scraped
+	memory := sinks.NewMemory()
+	for _, teamId := range []int{474, 1001, 2059} {
+		if err := memory.SaveTeam(&models.TeamSchema{Id: teamId}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshotDate := time.Date(2025, 10, 19, 0, 0, 0, 0, time.UTC)
+	snapshot := models.TeamRankingSchema{SnapshotDate: snapshotDate, Region: "europe"}
+
+	ctx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), "teamRanking", &snapshot), "tx", (*gorm.DB)(nil)), memory)
+	if err = RegionHandler(nil, ctx, doc.Selection); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(memory.TeamRankings) != 3 {
+		t.Fatalf("Want 3 ranks, get %+v", memory.TeamRankings)
+	}
+
+	intPtr := func(val int) *int { return &val }
+
+	wants := []models.TeamRankingSchema{
+		{SnapshotDate: snapshotDate, Region: "europe", TeamId: 474, Rank: 1, Rating: intPtr(1977), Wins: intPtr(46), Losses: intPtr(20), Streak: intPtr(3)},
+		{SnapshotDate: snapshotDate, Region: "europe", TeamId: 1001, Rank: 2, Rating: intPtr(1905), Wins: intPtr(35), Losses: intPtr(23), Streak: intPtr(-1)},
+		{SnapshotDate: snapshotDate, Region: "europe", TeamId: 2059, Rank: 3},
+	}
+
+	equalInt := func(a, b *int) bool {
+		return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+	}
+
+	for i, ranking := range memory.TeamRankings {
+		want := wants[i]
+		if !ranking.SnapshotDate.Equal(want.SnapshotDate) || ranking.Region != want.Region || ranking.TeamId != want.TeamId || ranking.Rank != want.Rank ||
+			!equalInt(ranking.Rating, want.Rating) || !equalInt(ranking.Wins, want.Wins) || !equalInt(ranking.Losses, want.Losses) || !equalInt(ranking.Streak, want.Streak) {
+			t.Errorf("Want rank %+v, get %+v", want, ranking)
+		}
+	}
+}
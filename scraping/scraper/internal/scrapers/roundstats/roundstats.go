@@ -9,8 +9,8 @@ import (
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"github.com/sirupsen/logrus"
-	"gorm.io/gorm"
 )
 
 func teamWonParser(t1Id, t2Id int) htmlx.Parser {
@@ -84,26 +84,15 @@ func buyTypeParser(roundNo int) htmlx.Parser {
 		}
 	}
 }
-func balanceParser(rawVal string) (any, error) {
-	balanceVal, err := htmlx.FloatParser(rawVal)
-	if err != nil {
-		return nil, err
-	}
-
-	floatBalance, _ := balanceVal.(float64)
-	balance := int(floatBalance * 1000)
-
-	return balance, nil
-}
 func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection) error {
 	roundStats, ok := ctx.Value("roundStat").(*models.RoundStatSchema)
 	if !ok {
 		return fmt.Errorf("Unable to find round stats")
 	}
 
-	tx, ok := ctx.Value("tx").(*gorm.DB)
-	if !ok {
-		return fmt.Errorf("Unable to find the transaction")
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
 	}
 
 	logrus.Debug("Scraping round overview info")
@@ -127,7 +116,6 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 	if err := htmlx.ParseFromSelection(&roundEconomySchema, selection.Eq(1), htmlx.SetParsers(
 		map[string]htmlx.Parser{
 			"buyTypeParser": buyTypeParser(roundStats.RoundNo),
-			"balanceParser": balanceParser,
 		},
 	)); err != nil {
 		return err
@@ -135,8 +123,8 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 
 	roundStats.RoundEconomySchema = roundEconomySchema
 
-	logrus.Debug("Saving round stats to db")
-	if err := tx.Table("round_stats").Create(roundStats).Error; err != nil {
+	logrus.Debug("Saving round stats")
+	if err := sink.SaveRoundStat(roundStats); err != nil {
 		return err
 	}
 
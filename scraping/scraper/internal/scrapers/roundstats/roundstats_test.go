@@ -11,6 +11,7 @@ import (
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/helpers"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 )
 
 func TestRoundStats(t *testing.T) {
@@ -161,7 +162,7 @@ func TestRoundStats(t *testing.T) {
 			Team2Id: testRound.Team2Id,
 		}
 
-		ctx := context.WithValue(context.Background(), "roundStat", &roundStat)
+		ctx := sinks.WithSink(context.WithValue(context.Background(), "roundStat", &roundStat), sinks.NewMemory())
 
 		if err := sc.Pipe("roundStat", ctx, combined); err != nil {
 			t.Fatal(err)
@@ -0,0 +1,169 @@
+package teams
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
+	_ "github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/customparsers" // Register idParser
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	rosterItemSelector = "div.team-roster-item"
+	// The label before the players and the staff of the roster
+	rosterLabelSelector = "div.wf-module-label"
+)
+
+// Return the role of the roster item from the nearest label before it, the members are players if there is no label
+func rosterRole(item *goquery.Selection) models.RosterRole {
+	for node := item; node.Length() > 0 && !node.Is("body"); node = node.Parent() {
+		label := node.PrevAll().Filter(rosterLabelSelector).First()
+		if label.Length() == 0 {
+			continue
+		}
+
+		if strings.Contains(strings.ToLower(label.Text()), "staff") {
+			return models.RosterStaff
+		}
+
+		return models.RosterPlayer
+	}
+
+	return models.RosterPlayer
+}
+
+// Return the members on the roster of the team page, seen at seenAt
+func parseRoster(selection *goquery.Selection, teamId int, seenAt time.Time) ([]models.TeamRosterSchema, error) {
+	var roster []models.TeamRosterSchema
+
+	for _, item := range selection.Find(rosterItemSelector).EachIter() {
+		member := models.TeamRosterSchema{
+			TeamId:    teamId,
+			Role:      rosterRole(item),
+			Status:    models.ActiveMember,
+			FirstSeen: seenAt,
+			LastSeen:  seenAt,
+		}
+
+		if err := htmlx.ParseFromSelection(&member, item); err != nil {
+			return nil, err
+		}
+
+		if member.Tag != nil {
+			tag := strings.TrimSpace(*member.Tag)
+			member.Tag = &tag
+
+			if tag == "" {
+				member.Tag = nil
+			} else if strings.EqualFold(tag, string(models.InactiveMember)) {
+				member.Status = models.InactiveMember
+			}
+		}
+
+		roster = append(roster, member)
+	}
+
+	return roster, nil
+}
+
+// Return the members to save from the saved and the scraped roster. The saved members keep their first seen date and
+// the saved members who are not on the scraped roster become former members. The changes of the roster are logged
+func updateRoster(saved, scraped []models.TeamRosterSchema) []models.TeamRosterSchema {
+	var members []models.TeamRosterSchema
+
+	for _, member := range scraped {
+		var previous *models.TeamRosterSchema
+		for i := range saved {
+			if saved[i].PlayerId == member.PlayerId && saved[i].Role == member.Role {
+				previous = &saved[i]
+				break
+			}
+		}
+
+		switch {
+		case previous == nil:
+			logrus.Infof("Team %d: %s %d joined the roster as %s", member.TeamId, member.Role, member.PlayerId, member.Status)
+		case previous.Status == models.FormerMember:
+			logrus.Infof("Team %d: %s %d rejoined the roster as %s", member.TeamId, member.Role, member.PlayerId, member.Status)
+			member.FirstSeen = previous.FirstSeen
+		default:
+			if previous.Status != member.Status {
+				logrus.Infof("Team %d: %s %d changed from %s to %s", member.TeamId, member.Role, member.PlayerId, previous.Status, member.Status)
+			}
+			member.FirstSeen = previous.FirstSeen
+		}
+
+		members = append(members, member)
+	}
+
+	for _, member := range saved {
+		if member.Status == models.FormerMember {
+			continue
+		}
+
+		stillOnRoster := false
+		for _, scrapedMember := range scraped {
+			if scrapedMember.PlayerId == member.PlayerId && scrapedMember.Role == member.Role {
+				stillOnRoster = true
+				break
+			}
+		}
+
+		if !stillOnRoster {
+			logrus.Infof("Team %d: %s %d left the roster", member.TeamId, member.Role, member.PlayerId)
+			member.Status = models.FormerMember
+			members = append(members, member)
+		}
+	}
+
+	return members
+}
+
+// Scrape the roster of the team page and save the changes since the last scrape. The members who are not saved as
+// players are scraped
+func scrapeRoster(sc *piper.Scraper, tx *gorm.DB, sink sinks.Sink, selection *goquery.Selection, teamId int, seenAt time.Time) error {
+	scraped, err := parseRoster(selection, teamId, seenAt)
+	if err != nil {
+		return err
+	}
+
+	saved, err := sink.Roster(teamId)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range updateRoster(saved, scraped) {
+		exists, err := sink.Exists(sinks.Players, member.PlayerId)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			logrus.Debugf("Player %d doesn't exists, start scraping player", member.PlayerId)
+			p := models.PlayerSchema{
+				Id:  member.PlayerId,
+				Url: fmt.Sprintf("https://www.vlr.gg/player/%d/", member.PlayerId),
+			}
+
+			ctx := sinks.WithSink(context.WithValue(context.WithValue(context.Background(), "player", &p), "tx", tx), sink)
+
+			if err := sc.Get(p.Url, ctx, nil); err != nil {
+				return err
+			}
+		}
+
+		if err := sink.SaveRosterMember(&member); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
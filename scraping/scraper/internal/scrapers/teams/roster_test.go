@@ -0,0 +1,116 @@
+package teams
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+)
+
+const rosterHtml = `<div class="wf-card">
+	<div>
+		<div class="wf-module-label">players</div>
+		%s
+	</div>
+	<div>
+		<div class="wf-module-label">staff</div>
+		<div class="team-roster-item">
+			<a href="/player/3247/alecks">
+				<div class="team-roster-item-name">
+					<div class="team-roster-item-name-alias">alecks</div>
+					<div class="wf-tag mod-light team-roster-item-name-role">head coach</div>
+				</div>
+			</a>
+		</div>
+	</div>
+</div>`
+
+func rosterItem(href, alias, tag string) string {
+	item := `<div class="team-roster-item"><a href="` + href + `"><div class="team-roster-item-name">` +
+		`<div class="team-roster-item-name-alias">` + alias + `</div>`
+	if tag != "" {
+		item += `<div class="wf-tag mod-light team-roster-item-name-role">` + tag + `</div>`
+	}
+
+	return item + `</div></a></div>`
+}
+
+func scrapeTestRoster(t *testing.T, sink sinks.Sink, seenAt time.Time, players ...string) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(strings.Replace(rosterHtml, "%s", strings.Join(players, "\n"), 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = scrapeRoster(nil, nil, sink, doc.Selection, 624, seenAt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScrapeRoster(t *testing.T) {
+	// The members are saved as players so that they are not scraped
+	memory := sinks.NewMemory()
+	for _, playerId := range []int{3247, 9, 17086, 4004} {
+		if err := memory.SavePlayer(&models.PlayerSchema{Id: playerId}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	firstScrape := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	secondScrape := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	scrapeTestRoster(t, memory, firstScrape,
+		rosterItem("/player/9/something", "something", ""),
+		rosterItem("/player/17086/d4v41", "d4v41", ""),
+	)
+
+	// d4v41 is benched and f0rsakeN join as a stand-in
+	scrapeTestRoster(t, memory, secondScrape,
+		rosterItem("/player/17086/d4v41", "d4v41", "Inactive"),
+		rosterItem("/player/4004/f0rsaken", "f0rsakeN", "stand-in"),
+	)
+
+	roster, err := memory.Roster(624)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		role      models.RosterRole
+		status    models.RosterStatus
+		tag       string
+		firstSeen time.Time
+		lastSeen  time.Time
+	}
+
+	wants := map[int]want{
+		3247:  {models.RosterStaff, models.ActiveMember, "head coach", firstScrape, secondScrape},
+		9:     {models.RosterPlayer, models.FormerMember, "", firstScrape, firstScrape},
+		17086: {models.RosterPlayer, models.InactiveMember, "Inactive", firstScrape, secondScrape},
+		4004:  {models.RosterPlayer, models.ActiveMember, "stand-in", secondScrape, secondScrape},
+	}
+
+	if len(roster) != len(wants) {
+		t.Fatalf("Want %d roster members, get %+v", len(wants), roster)
+	}
+
+	for _, member := range roster {
+		w, ok := wants[member.PlayerId]
+		if !ok {
+			t.Errorf("Unexpected roster member %+v", member)
+			continue
+		}
+
+		tag := ""
+		if member.Tag != nil {
+			tag = *member.Tag
+		}
+
+		if member.TeamId != 624 || member.Role != w.role || member.Status != w.status || tag != w.tag ||
+			!member.FirstSeen.Equal(w.firstSeen) || !member.LastSeen.Equal(w.lastSeen) {
+			t.Errorf("Player %d: want %+v, get %+v", member.PlayerId, w, member)
+		}
+	}
+}
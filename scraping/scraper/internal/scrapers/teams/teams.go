@@ -4,22 +4,23 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
-	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/helpers"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/repos/countryrepo"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/repos/regionrepo"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/utils/geographyinfo"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
-const (
-	teamLocSel = "#wrapper > div.col-container > div > div.wf-card.mod-header.mod-full > div.team-header > div.team-header-desc > div > div.team-header-country"
-)
+type teamLocation struct {
+	Location string `selector:"#wrapper > div.col-container > div > div.wf-card.mod-header.mod-full > div.team-header > div.team-header-desc > div > div.team-header-country" source:"text=0"`
+}
 
 func getRegionInfo(tx *gorm.DB, teamLoc string) (*int, error) {
 	geoInfo, err := geographyinfo.GetInfoFromRegionName(teamLoc)
@@ -96,6 +97,11 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 		return fmt.Errorf("Unable to find the transaction")
 	}
 
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
 	if err := htmlx.ParseFromSelection(teamSchema, selection); err != nil {
 		return err
 	}
@@ -106,22 +112,27 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 		teamSchema.ShorthandName = &teamSchema.Name
 	}
 
-	teamLoc := strings.TrimSpace(selection.Find(teamLocSel).Clone().Children().Remove().End().Text())
+	var teamLoc teamLocation
+
+	if err := htmlx.ParseFromSelection(&teamLoc, selection); err != nil {
+		return err
+	}
 
-	if teamSchema.CountryId, err = getCountryInfo(tx, teamLoc); err != nil && err != geographyinfo.ErrNotFound {
+	if teamSchema.CountryId, err = getCountryInfo(tx, teamLoc.Location); err != nil && err != geographyinfo.ErrNotFound {
 		return err
 	}
 
-	if teamSchema.RegionId, err = getRegionInfo(tx, teamLoc); err != nil && err != geographyinfo.ErrNotFound {
+	if teamSchema.RegionId, err = getRegionInfo(tx, teamLoc.Location); err != nil && err != geographyinfo.ErrNotFound {
 		return err
 	}
 
-	if err := helpers.PrettyPrintStruct(teamSchema); err != nil {
+	logrus.Debug("Saving team info")
+	if err := sink.SaveTeam(teamSchema); err != nil {
 		return err
 	}
 
-	logrus.Debug("Saving team info to db")
-	if err := tx.Table("teams").Create(teamSchema).Error; err != nil {
+	logrus.Debug("Scraping team roster")
+	if err := scrapeRoster(sc, tx, sink, selection, teamSchema.Id, time.Now().UTC()); err != nil {
 		return err
 	}
 
@@ -11,6 +11,7 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -65,7 +66,7 @@ func TestTeamScraperWithCountryName(t *testing.T) {
 		teamSchema := models.TeamSchema{Url: teamUrl}
 
 		ctx := context.WithValue(context.Background(), "teamSchema", &teamSchema)
-		ctx2 := context.WithValue(ctx, "tx", tx)
+		ctx2 := sinks.WithSink(context.WithValue(ctx, "tx", tx), sinks.NewSQLite(tx))
 
 		if err := sc.Get(teamUrl, ctx2, nil); err != nil {
 			t.Fatal(err)
@@ -0,0 +1,134 @@
+package tournaments
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// The event page, the query only tell it apart from the event page scraped by [Handler]
+	eventBracketUrl = "https://www.vlr.gg/event/%d/?bracket"
+
+	activeStageSelector      = "a.wf-subnav-item.mod-active div.wf-subnav-item-title"
+	bracketContainerSelector = "div.bracket-container"
+	bracketColLabelSelector  = "div.bracket-col-label"
+)
+
+// The bracket of a stage read from the event page, e.g the playoffs bracket
+type eventBracket struct {
+	// The side of the rounds of the bracket, nil for the rounds of a single elimination bracket
+	rounds map[string]*models.BracketSide
+	// The bracket has a lower side
+	doubleElimination bool
+}
+
+// Return the url of the event page the bracket of the current stage of the tournament is scraped from, see
+// [BracketHandler]
+func EventBracketUrl(tournamentId int) string {
+	return fmt.Sprintf(eventBracketUrl, tournamentId)
+}
+
+// Return the brackets of the event page by stage name. The page show the bracket of the stage selected in its stage
+// navigation, an event without stages has no navigation and its bracket is returned for the empty name. A stage
+// played in groups has no bracket
+func parseBrackets(selection *goquery.Selection) map[string]eventBracket {
+	containers := selection.Find(bracketContainerSelector)
+	if containers.Length() == 0 {
+		return nil
+	}
+
+	bracket := eventBracket{rounds: map[string]*models.BracketSide{}}
+
+	for _, container := range containers.EachIter() {
+		var side *models.BracketSide
+
+		switch {
+		case container.HasClass("mod-upper"):
+			upper := models.UpperBracket
+			side = &upper
+		case container.HasClass("mod-lower"):
+			lower := models.LowerBracket
+			side = &lower
+			bracket.doubleElimination = true
+		}
+
+		for _, label := range container.Find(bracketColLabelSelector).EachIter() {
+			if roundName := normalizeSpaces(label.Text()); roundName != "" {
+				bracket.rounds[roundName] = side
+			}
+		}
+	}
+
+	if !bracket.doubleElimination {
+		for roundName := range bracket.rounds {
+			bracket.rounds[roundName] = nil
+		}
+	}
+
+	return map[string]eventBracket{normalizeSpaces(selection.Find(activeStageSelector).First().Text()): bracket}
+}
+
+// Replace the format, the sides and the eliminations guessed from the names with the ones of the brackets, the stages
+// and the rounds read from a bracket are no longer inferred. The bracket of the empty name is the bracket of the only
+// stage of the tournament
+func applyBrackets(stages []models.TournamentStageSchema, rounds []models.TournamentRoundSchema, brackets map[string]eventBracket) {
+	if bracket, ok := brackets[""]; ok && len(stages) == 1 {
+		brackets = map[string]eventBracket{stages[0].Name: bracket}
+	}
+
+	for i := range stages {
+		if _, ok := brackets[stages[i].Name]; !ok {
+			continue
+		}
+
+		format := models.BracketFormat
+		stages[i].Format = &format
+		stages[i].Inferred = false
+	}
+
+	for i := range rounds {
+		bracket, ok := brackets[rounds[i].StageName]
+		if !ok {
+			continue
+		}
+
+		side, ok := bracket.rounds[rounds[i].Name]
+		if !ok {
+			logrus.Warnf("Round '%s: %s' isn't in the bracket, its bracket is guessed from its name", rounds[i].StageName, rounds[i].Name)
+			continue
+		}
+
+		format := models.BracketFormat
+		rounds[i].Bracket = side
+		rounds[i].Elimination = (side != nil && *side == models.LowerBracket) ||
+			isElimination(rounds[i].Name, &format, bracket.doubleElimination)
+		rounds[i].Inferred = false
+	}
+}
+
+// Read the brackets of the event page and scrape the stages of the tournament from the event matches page with them,
+// see [MatchesHandler]
+func scrapeStages(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection, tournamentId int) error {
+	brackets := parseBrackets(selection)
+	if len(brackets) == 0 {
+		logrus.Debugf("Event page of tournament %d has no bracket, the stages are guessed from the names", tournamentId)
+	}
+
+	return sc.Get(EventMatchesUrl(tournamentId), context.WithValue(ctx, "eventBrackets", brackets), nil)
+}
+
+// BracketHandler scrape the stages of the tournament again with the bracket of the event page, e.g once the playoffs
+// are drawn
+func BracketHandler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection) error {
+	tournamentSchema, ok := ctx.Value("tournamentSchema").(*models.TournamentSchema)
+	if !ok {
+		return fmt.Errorf("Unable to find the tournament schema")
+	}
+
+	return scrapeStages(sc, ctx, selection, tournamentSchema.Id)
+}
@@ -0,0 +1,282 @@
+package tournaments
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	eventMatchesUrl = "https://www.vlr.gg/event/matches/%d/?series_id=all"
+
+	eventDateLabelSelector = "div.wf-label.mod-large"
+	eventMatchSelector     = "a.match-item"
+	eventSelector          = "div.match-item-event"
+	eventSeriesSelector    = "div.match-item-event-series"
+
+	eventMatchDateLayout = "Mon, January 2, 2006"
+	eventDatesLayout     = "Jan 2, 2006"
+)
+
+// The dates of the event header, e.g "Sep 12, 2025 - Oct 5, 2025"
+type eventDates struct {
+	Dates string `selector:"#wrapper > div.col-container > div > div.wf-card.mod-event.mod-header.mod-full > div.event-header > div.event-desc > div > div.event-desc-items > div:nth-child(1) > div.event-desc-item-value"`
+}
+
+// A match of the event matches page, e.g a match of the "Upper Round 1" round of the "Playoffs" stage
+type eventMatch struct {
+	stage string
+	round string
+	date  time.Time
+}
+
+// Return the url of the event matches page the stages of the tournament are scraped from, see [MatchesHandler]
+func EventMatchesUrl(tournamentId int) string {
+	return fmt.Sprintf(eventMatchesUrl, tournamentId)
+}
+
+func normalizeSpaces(str string) string {
+	return strings.Join(strings.Fields(str), " ")
+}
+
+// Parse the dates of the event header, the year of the start date can be omitted, e.g "Sep 12 - Oct 5, 2025".
+// The dates are nil if they are not announced
+func parseEventDates(datesStr string) (*time.Time, *time.Time) {
+	startStr, endStr, found := strings.Cut(normalizeSpaces(datesStr), " - ")
+	if !found {
+		endStr = startStr
+	}
+
+	endDate, err := time.Parse(eventDatesLayout, endStr)
+	if err != nil {
+		return nil, nil
+	}
+
+	startDate, err := time.Parse(eventDatesLayout, startStr)
+	if err != nil {
+		if startDate, err = time.Parse("Jan 2", startStr); err != nil {
+			return nil, &endDate
+		}
+
+		startDate = startDate.AddDate(endDate.Year(), 0, 0)
+		if startDate.After(endDate) {
+			startDate = startDate.AddDate(-1, 0, 0)
+		}
+	}
+
+	return &startDate, &endDate
+}
+
+// Return the matches of the event matches page with their stage, round and date
+func parseEventMatches(selection *goquery.Selection) ([]eventMatch, error) {
+	var matches []eventMatch
+
+	for _, dateLabel := range selection.Find(eventDateLabelSelector).EachIter() {
+		// The label of the current and next days end with "Today" or "Tomorrow" in a child node
+		dateStr := strings.TrimSpace(dateLabel.Clone().Children().Remove().End().Text())
+		date, err := time.Parse(eventMatchDateLayout, dateStr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid date of the event matches '%s': %s", dateStr, err.Error())
+		}
+
+		for _, matchNode := range dateLabel.Next().Find(eventMatchSelector).EachIter() {
+			event := matchNode.Find(eventSelector)
+
+			match := eventMatch{
+				stage: normalizeSpaces(event.Clone().Children().Remove().End().Text()),
+				round: normalizeSpaces(event.Find(eventSeriesSelector).Text()),
+				date:  date,
+			}
+
+			if match.stage == "" {
+				logrus.Warnf("Match %s has no stage, skipping it", matchNode.AttrOr("href", ""))
+				continue
+			}
+
+			matches = append(matches, match)
+		}
+	}
+
+	return matches, nil
+}
+
+func containsAny(names []string, substrs ...string) bool {
+	for _, name := range names {
+		for _, substr := range substrs {
+			if strings.Contains(strings.ToLower(name), substr) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Return the format of the stage guessed from its name and the names of its rounds, nil if it can't be told.
+// A stage named without any of the keywords, e.g "Main Event", has no format even if it is played as a bracket
+func stageFormat(stageName string, roundNames []string) *models.StageFormat {
+	names := append([]string{stageName}, roundNames...)
+
+	var format models.StageFormat
+
+	switch {
+	case containsAny(names, "swiss"):
+		format = models.SwissFormat
+	case containsAny(names, "group"):
+		format = models.GroupsFormat
+	case containsAny(names, "upper", "lower", "final", "playoff"):
+		format = models.BracketFormat
+	default:
+		return nil
+	}
+
+	return &format
+}
+
+func roundBracket(roundName string) *models.BracketSide {
+	var side models.BracketSide
+
+	switch {
+	case containsAny([]string{roundName}, "upper"):
+		side = models.UpperBracket
+	case containsAny([]string{roundName}, "lower"):
+		side = models.LowerBracket
+	default:
+		return nil
+	}
+
+	return &side
+}
+
+// Return true if losing a match of the round eliminate the team: the lower bracket, the deciders and elimination
+// matches of the groups, the grand final and every round of a single elimination bracket
+func isElimination(roundName string, format *models.StageFormat, doubleElimination bool) bool {
+	if containsAny([]string{roundName}, "lower", "decider", "elimination", "grand final") {
+		return true
+	}
+
+	return format != nil && *format == models.BracketFormat && !doubleElimination
+}
+
+func extendDates(start, end **time.Time, date time.Time) {
+	if *start == nil || date.Before(**start) {
+		*start = &date
+	}
+
+	if *end == nil || date.After(**end) {
+		*end = &date
+	}
+}
+
+// Return the stages and the rounds of the tournament, ordered by their first match. The format, the bracket and the
+// elimination are inferred from the names, see [models.TournamentStageSchema.Inferred]
+func buildStages(tournamentId int, matches []eventMatch) ([]models.TournamentStageSchema, []models.TournamentRoundSchema) {
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].date.Before(matches[j].date) })
+
+	var stages []models.TournamentStageSchema
+	var rounds []models.TournamentRoundSchema
+	stageIndexes := map[string]int{}
+	roundIndexes := map[[2]string]int{}
+	roundNames := map[string][]string{}
+
+	for _, match := range matches {
+		stageIndex, ok := stageIndexes[match.stage]
+		if !ok {
+			stageIndex = len(stages)
+			stageIndexes[match.stage] = stageIndex
+			stages = append(stages, models.TournamentStageSchema{
+				TournamentId: tournamentId,
+				Name:         match.stage,
+				StageOrder:   stageIndex + 1,
+				Inferred:     true,
+			})
+		}
+
+		extendDates(&stages[stageIndex].StartDate, &stages[stageIndex].EndDate, match.date)
+
+		if match.round == "" {
+			continue
+		}
+
+		roundKey := [2]string{match.stage, match.round}
+		roundIndex, ok := roundIndexes[roundKey]
+		if !ok {
+			roundIndex = len(rounds)
+			roundIndexes[roundKey] = roundIndex
+			roundNames[match.stage] = append(roundNames[match.stage], match.round)
+			rounds = append(rounds, models.TournamentRoundSchema{
+				TournamentId: tournamentId,
+				StageName:    match.stage,
+				Name:         match.round,
+				RoundOrder:   len(roundNames[match.stage]),
+				Bracket:      roundBracket(match.round),
+				Inferred:     true,
+			})
+		}
+
+		extendDates(&rounds[roundIndex].StartDate, &rounds[roundIndex].EndDate, match.date)
+	}
+
+	for i := range stages {
+		stages[i].Format = stageFormat(stages[i].Name, roundNames[stages[i].Name])
+	}
+
+	for i := range rounds {
+		stage := stages[stageIndexes[rounds[i].StageName]]
+		doubleElimination := containsAny(roundNames[stage.Name], "upper", "lower")
+		rounds[i].Elimination = isElimination(rounds[i].Name, stage.Format, doubleElimination)
+	}
+
+	return stages, rounds
+}
+
+// MatchesHandler scrape the stages and the rounds of the tournament from the event matches page. The page only list
+// the names of the stages and the rounds, so the format, the side of the bracket and the eliminations are read from the
+// brackets of the event page given by the context, see [BracketHandler]. The ones of the stages without bracket are
+// guessed from the names and marked as inferred. A round is a double elimination round only if its stage has upper and
+// lower rounds, and the eliminations of a group stage are only known from "decider" and "elimination" rounds
+func MatchesHandler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection) error {
+	tournamentSchema, ok := ctx.Value("tournamentSchema").(*models.TournamentSchema)
+	if !ok {
+		return fmt.Errorf("Unable to find the tournament schema")
+	}
+
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	matches, err := parseEventMatches(selection)
+	if err != nil {
+		return err
+	}
+
+	stages, rounds := buildStages(tournamentSchema.Id, matches)
+
+	if brackets, ok := ctx.Value("eventBrackets").(map[string]eventBracket); ok {
+		applyBrackets(stages, rounds, brackets)
+	}
+
+	logrus.Debugf("Saving %d stages and %d rounds of tournament %d", len(stages), len(rounds), tournamentSchema.Id)
+	for _, stage := range stages {
+		if err := sink.SaveTournamentStage(&stage); err != nil {
+			return err
+		}
+	}
+
+	for _, round := range rounds {
+		if err := sink.SaveTournamentRound(&round); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package tournaments
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+)
+
+func eventMatchItem(stage, round string) string {
+	return `<a href="/1/match" class="wf-module-item match-item"><div class="match-item-vs">A vs B</div>` +
+		`<div class="match-item-event text-of"><div class="match-item-event-series text-of">` + round + `</div>
+			` + stage + `
+		</div></a>`
+}
+
+var eventMatchesHtml = `<div class="col mod-1">
+	<div class="wf-label mod-large">Sat, September 13, 2025</div>
+	<div class="wf-card">` + eventMatchItem("Group Stage", "Opening (A)") + eventMatchItem("Group Stage", "Opening (B)") + `</div>
+	<div class="wf-label mod-large">Tue, September 16, 2025</div>
+	<div class="wf-card">` + eventMatchItem("Group Stage", "Decider (A)") + `</div>
+	<div class="wf-label mod-large">Fri, September 19, 2025</div>
+	<div class="wf-card">` + eventMatchItem("Playoffs", "Upper Round 1") + eventMatchItem("Playoffs", "Lower Round 1") + `</div>
+	<div class="wf-label mod-large">Sun, October 5, 2025 <span class="wf-tag">Today</span></div>
+	<div class="wf-card">` + eventMatchItem("Playoffs", "Grand Final") + `</div>
+</div>`
+
+func TestParseEventDates(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	for datesStr, want := range map[string][]time.Time{
+		"Sep 12, 2025 - Oct 5, 2025": {date(2025, 9, 12), date(2025, 10, 5)},
+		"\n\tSep 12 - Oct 5, 2025\n": {date(2025, 9, 12), date(2025, 10, 5)},
+		"Dec 28 - Jan 4, 2026":       {date(2025, 12, 28), date(2026, 1, 4)},
+		"Oct 5, 2025":                {date(2025, 10, 5), date(2025, 10, 5)},
+		"TBD":                        nil,
+	} {
+		start, end := parseEventDates(datesStr)
+
+		if want == nil {
+			if start != nil || end != nil {
+				t.Errorf("%q: want no dates, get %v and %v", datesStr, start, end)
+			}
+			continue
+		}
+
+		if start == nil || end == nil || !start.Equal(want[0]) || !end.Equal(want[1]) {
+			t.Errorf("%q: want %v, get %v and %v", datesStr, want, start, end)
+		}
+	}
+}
+
+func TestMatchesHandler(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(eventMatchesHtml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	memory := sinks.NewMemory()
+	ctx := sinks.WithSink(context.WithValue(context.Background(), "tournamentSchema", &models.TournamentSchema{Id: 2283}), memory)
+
+	if err = MatchesHandler(nil, ctx, doc.Selection); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(memory.EventStages) != 2 {
+		t.Fatalf("Want 2 stages, get %+v", memory.EventStages)
+	}
+
+	groupStage, playoffs := memory.EventStages[0], memory.EventStages[1]
+
+	if groupStage.Name != "Group Stage" || groupStage.StageOrder != 1 || groupStage.Format == nil || *groupStage.Format != models.GroupsFormat ||
+		!groupStage.StartDate.Equal(time.Date(2025, 9, 13, 0, 0, 0, 0, time.UTC)) || !groupStage.EndDate.Equal(time.Date(2025, 9, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Wrong group stage %+v", groupStage)
+	}
+
+	if playoffs.Name != "Playoffs" || playoffs.StageOrder != 2 || playoffs.Format == nil || *playoffs.Format != models.BracketFormat || !playoffs.Inferred {
+		t.Errorf("Wrong playoffs %+v", playoffs)
+	}
+
+	type wantRound struct {
+		stage       string
+		name        string
+		order       int
+		bracket     models.BracketSide
+		elimination bool
+	}
+
+	wants := []wantRound{
+		{"Group Stage", "Opening (A)", 1, "", false},
+		{"Group Stage", "Opening (B)", 2, "", false},
+		{"Group Stage", "Decider (A)", 3, "", true},
+		{"Playoffs", "Upper Round 1", 1, models.UpperBracket, false},
+		{"Playoffs", "Lower Round 1", 2, models.LowerBracket, true},
+		{"Playoffs", "Grand Final", 3, "", true},
+	}
+
+	if len(memory.EventRounds) != len(wants) {
+		t.Fatalf("Want %d rounds, get %+v", len(wants), memory.EventRounds)
+	}
+
+	for i, round := range memory.EventRounds {
+		var bracket models.BracketSide
+		if round.Bracket != nil {
+			bracket = *round.Bracket
+		}
+
+		got := wantRound{round.StageName, round.Name, round.RoundOrder, bracket, round.Elimination}
+		if round.TournamentId != 2283 || !round.Inferred || got != wants[i] {
+			t.Errorf("Want round %+v, get %+v", wants[i], round)
+		}
+	}
+}
+
+func TestSingleEliminationBracket(t *testing.T) {
+	_, rounds := buildStages(1, []eventMatch{
+		{"Playoffs", "Quarterfinals", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Playoffs", "Semifinals", time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+	})
+
+	for _, round := range rounds {
+		if !round.Elimination || round.Bracket != nil {
+			t.Errorf("Round %s of a single elimination bracket should be an elimination round without side", round.Name)
+		}
+	}
+}
+
+func TestIsLan(t *testing.T) {
+	for location, want := range map[string]string{
+		"Toronto, Canada": "true",
+		"Online":          "false",
+		"Online (EMEA)":   "false",
+		"Pakistan":        "nil",
+	} {
+		lan := "nil"
+		if got := isLan(&location); got != nil {
+			lan = fmt.Sprint(*got)
+		}
+
+		if lan != want {
+			t.Errorf("%q: want %s, get %s", location, want, lan)
+		}
+	}
+
+	if isLan(nil) != nil {
+		t.Error("Want nil without location")
+	}
+}
+
+func bracketContainer(class string, roundNames ...string) string {
+	html := `<div class="bracket-container ` + class + `"><div class="bracket-row">`
+	for _, roundName := range roundNames {
+		html += `<div class="bracket-col"><div class="bracket-col-label">
+			` + roundName + `
+		</div><div class="bracket-item">A vs B</div></div>`
+	}
+
+	return html + `</div></div>`
+}
+
+func TestBracketsOfEventPage(t *testing.T) {
+	eventPage, err := goquery.NewDocumentFromReader(strings.NewReader(`<div class="wf-subnav">
+		<a class="wf-subnav-item" href="/event/2283/champions/group-stage"><div class="wf-subnav-item-title">Group Stage</div></a>
+		<a class="wf-subnav-item mod-active" href="/event/2283/champions/playoffs"><div class="wf-subnav-item-title"> Playoffs </div></a>
+	</div>` + bracketContainer("mod-upper", "Upper Round 1", "Grand Final") + bracketContainer("mod-lower", "Lower Round 1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(eventMatchesHtml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	memory := sinks.NewMemory()
+	ctx := sinks.WithSink(
+		context.WithValue(
+			context.WithValue(context.Background(), "tournamentSchema", &models.TournamentSchema{Id: 2283}),
+			"eventBrackets",
+			parseBrackets(eventPage.Selection),
+		),
+		memory,
+	)
+
+	if err = MatchesHandler(nil, ctx, doc.Selection); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(memory.EventStages) != 2 || !memory.EventStages[0].Inferred || memory.EventStages[1].Inferred {
+		t.Errorf("Want only the playoffs read from the bracket, get %+v", memory.EventStages)
+	}
+
+	for _, round := range memory.EventRounds {
+		if round.Inferred != (round.StageName == "Group Stage") {
+			t.Errorf("Want only the playoffs rounds read from the bracket, get %+v", round)
+		}
+	}
+
+	if grandFinal := memory.EventRounds[5]; grandFinal.Bracket == nil || *grandFinal.Bracket != models.UpperBracket || !grandFinal.Elimination {
+		t.Errorf("Want the grand final on the upper side of the bracket and an elimination round, get %+v", grandFinal)
+	}
+}
+
+func TestSingleStageBracket(t *testing.T) {
+	eventPage, err := goquery.NewDocumentFromReader(strings.NewReader(bracketContainer("mod-upper", "Round 1", "Round 2")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stages, rounds := buildStages(1, []eventMatch{
+		{"Main Event", "Round 1", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Main Event", "Round 2", time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+	})
+
+	if stages[0].Format != nil {
+		t.Fatalf("The format of 'Main Event' can't be guessed from the names, get %s", *stages[0].Format)
+	}
+
+	applyBrackets(stages, rounds, parseBrackets(eventPage.Selection))
+
+	if stages[0].Format == nil || *stages[0].Format != models.BracketFormat || stages[0].Inferred {
+		t.Errorf("Want the stage read from the bracket, get %+v", stages[0])
+	}
+
+	for _, round := range rounds {
+		if !round.Elimination || round.Bracket != nil || round.Inferred {
+			t.Errorf("Round %s of a single elimination bracket should be an elimination round without side", round.Name)
+		}
+	}
+}
@@ -10,8 +10,11 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/htmlx"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
-	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/helpers"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/repos/regionrepo"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/tiers"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/utils/geographyinfo"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -37,6 +40,65 @@ func moneyParser(rawVal string) (any, error) {
 	return prize, nil
 }
 
+// Return true if the event is played on LAN, false if it is online, nil if it can't be told from the location.
+// The location of the LAN events is a city, e.g "Paris, France"
+func isLan(location *string) *bool {
+	if location == nil {
+		return nil
+	}
+
+	var lan bool
+
+	switch {
+	case strings.Contains(strings.ToLower(*location), "online"):
+		lan = false
+	case strings.Contains(*location, ","):
+		lan = true
+	default:
+		return nil
+	}
+
+	return &lan
+}
+
+// Return the id of the region of the location, the country of a city or a region name, nil if it isn't found
+func locationRegionId(tx *gorm.DB, location string) (*int, error) {
+	place := location
+	if i := strings.LastIndex(location, ","); i >= 0 {
+		place = strings.TrimSpace(location[i+1:])
+	}
+
+	if place == "" {
+		return nil, nil
+	}
+
+	geoInfo, err := geographyinfo.GetInfoFromCountryName(place)
+	if err == geographyinfo.ErrNotFound {
+		geoInfo, err = geographyinfo.GetInfoFromRegionName(place)
+	}
+
+	if err == geographyinfo.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	regionRepo := regionrepo.NewRegionRepo(tx)
+
+	regionInfo, err := regionRepo.GetRegionByName(geoInfo.Region)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		if regionInfo, err = regionRepo.InsertRegion(geoInfo.Region); err != nil {
+			return nil, err
+		}
+	}
+
+	return &regionInfo.Id, nil
+}
+
 func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selection) error {
 	tournamentSchema, ok := ctx.Value("tournamentSchema").(*models.TournamentSchema)
 	if !ok {
@@ -45,7 +107,12 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 
 	tx, ok := ctx.Value("tx").(*gorm.DB)
 	if !ok {
-		return fmt.Errorf("Unable to find gorm transaction")
+		return fmt.Errorf("Unable to find the transaction")
+	}
+
+	sink, err := sinks.FromContext(ctx)
+	if err != nil {
+		return err
 	}
 
 	if err := htmlx.ParseFromSelection(tournamentSchema, selection, htmlx.SetParsers(map[string]htmlx.Parser{
@@ -54,17 +121,60 @@ func Handler(sc *piper.Scraper, ctx context.Context, selection *goquery.Selectio
 		return err
 	}
 
-	tournamentGroup, _ := selection.Find(tournamentGroupSelector).Attr("href")
+	var series *models.TournamentSeriesSchema
+
+	if seriesLink := selection.Find(tournamentGroupSelector); strings.TrimSpace(seriesLink.AttrOr("href", "")) != "" {
+		series = &models.TournamentSeriesSchema{
+			Url:  strings.TrimSpace(seriesLink.AttrOr("href", "")),
+			Name: normalizeSpaces(seriesLink.Text()),
+		}
+
+		logrus.Debug("Saving tournament series")
+		if err := sink.SaveTournamentSeries(series); err != nil {
+			return err
+		}
+
+		tournamentSchema.SeriesUrl = &series.Url
+	}
+
+	var dates eventDates
+	if err := htmlx.ParseFromSelection(&dates, selection); err != nil {
+		return err
+	}
+
+	tournamentSchema.StartDate, tournamentSchema.EndDate = parseEventDates(dates.Dates)
+
+	if tournamentSchema.Location != nil {
+		location := normalizeSpaces(*tournamentSchema.Location)
+		tournamentSchema.Location = &location
+
+		if location == "" {
+			tournamentSchema.Location = nil
+		}
+	}
+
+	tournamentSchema.Lan = isLan(tournamentSchema.Location)
+
+	if tournamentSchema.Location != nil && (tournamentSchema.Lan == nil || *tournamentSchema.Lan) {
+		if tournamentSchema.RegionId, err = locationRegionId(tx, *tournamentSchema.Location); err != nil {
+			return err
+		}
+	}
+
+	classifier, err := tiers.Load(tx)
+	if err != nil {
+		return err
+	}
 
-	tournamentSchema.Tier1 = regexp.MustCompile(`vct-20[0-9][0-9]`).MatchString(strings.TrimSpace(tournamentGroup)) ||
-		tournamentSchema.PrizePool >= 500000
+	tournamentSchema.Tier = classifier.Classify(*tournamentSchema, series)
 
-	if err := helpers.PrettyPrintStruct(tournamentSchema); err != nil {
+	logrus.Debug("Saving tournament")
+	if err := sink.SaveTournament(tournamentSchema); err != nil {
 		return err
 	}
 
-	logrus.Debug("Saving tournament to db")
-	if err := tx.Table("tournaments").Create(tournamentSchema).Error; err != nil {
+	logrus.Debug("Scraping tournament stages")
+	if err := scrapeStages(sc, ctx, selection, tournamentSchema.Id); err != nil {
 		return err
 	}
 
@@ -6,47 +6,79 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/joho/godotenv"
 	"github.com/leminhohoho/vlr-prediction/scraping/pkgs/piper"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/helpers"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/migrations"
 	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
+// Open an in memory db with the schema and the reference data of the migrations, which include the tier rules
+func openReferenceDb(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every connection to :memory: open another database
+	sqlDb, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDb.SetMaxOpenConns(1)
+
+	if _, err = migrations.Up(db); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
 func TestTournamentScraper(t *testing.T) {
+	if err := godotenv.Load("../../../.env"); err != nil {
+		t.Fatal(err)
+	}
+
+	db := openReferenceDb(t)
+
 	testTournaments := []models.TournamentSchema{
 		{
 			Id:        2470,
 			Name:      "Challengers 2025: MENA Resilience GCC-Pakistan-Iraq Split 2",
 			Url:       "https://www.vlr.gg/event/2470/challengers-2025-mena-resilience-gcc-pakistan-iraq-split-2",
 			PrizePool: 17500,
-			Tier1:     false,
+			Tier:      "tier_2",
 		},
 		{
 			Id:        2572,
 			Name:      "Valorant Indonesia 2025: Summer Protocol Campus Stage",
 			Url:       "https://www.vlr.gg/event/2572/valorant-indonesia-2025-summer-protocol-campus-stage",
 			PrizePool: 0,
-			Tier1:     false,
+			Tier:      "tier_3",
 		},
 		{
 			Id:        2561,
 			Name:      "EPIC.LAN #45",
 			Url:       "https://www.vlr.gg/event/2561/epic-lan-45",
 			PrizePool: 3017,
-			Tier1:     false,
+			Tier:      "tier_3",
 		},
 		{
 			Id:        2449,
 			Name:      "Esports World Cup 2025",
 			Url:       "https://www.vlr.gg/event/2449/esports-world-cup-2025",
 			PrizePool: 1250000,
-			Tier1:     true,
+			Tier:      "tier_1",
 		},
 		{
 			Id:        2282,
 			Name:      "Valorant Masters Toronto 2025",
 			Url:       "https://www.vlr.gg/event/2282/valorant-masters-toronto-2025",
 			PrizePool: 1000000,
-			Tier1:     true,
+			Tier:      "tier_1",
 		},
 	}
 
@@ -67,14 +99,22 @@ func TestTournamentScraper(t *testing.T) {
 
 	sc := piper.NewScraper(backend, cache)
 	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/event\/[0-9]+\/[a-z0-9\/-]+$`), Handler)
+	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/event\/matches\/[0-9]+\/\?series_id=all$`), MatchesHandler)
+	sc.Handle(regexp.MustCompile(`^https:\/\/www\.vlr\.gg\/event\/[0-9]+\/\?bracket$`), BracketHandler)
 
 	for _, testTournament := range testTournaments {
 		tournamentSchema := models.TournamentSchema{Id: testTournament.Id, Url: testTournament.Url}
 
-		if err := sc.Get(testTournament.Url, context.WithValue(context.Background(), "tournamentSchema", &tournamentSchema), nil); err != nil {
+		ctx := context.WithValue(context.WithValue(context.Background(), "tournamentSchema", &tournamentSchema), "tx", db)
+
+		if err := sc.Get(testTournament.Url, sinks.WithSink(ctx, sinks.NewMemory()), nil); err != nil {
 			t.Fatal(err)
 		}
 
+		// The dates, the location and the series are checked by TestParseEventDates and TestIsLan
+		tournamentSchema.StartDate, tournamentSchema.EndDate, tournamentSchema.Location = nil, nil, nil
+		tournamentSchema.RegionId, tournamentSchema.Lan, tournamentSchema.SeriesUrl = nil, nil, nil
+
 		if err := helpers.CompareStructs(tournamentSchema, testTournament); err != nil {
 			t.Error(err)
 		}
@@ -0,0 +1,145 @@
+package sinks
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+)
+
+type bufferedRow struct {
+	table string
+	id    int
+	row   any
+}
+
+// The sink a buffered transaction is committed to
+type rowsCommitter interface {
+	Exists(table string, id int) (bool, error)
+	Team(id int) (*models.TeamSchema, error)
+	Roster(teamId int) ([]models.TeamRosterSchema, error)
+	TournamentRounds(tournamentId int) ([]models.TournamentRoundSchema, error)
+	LinkScheduledMatch(matchId int) error
+	saveRows(rows []bufferedRow) error
+}
+
+// bufferTx is a transaction of the sinks which can't roll back, the rows are kept until the transaction succeed
+type bufferTx struct {
+	entitySaver
+	mu sync.Mutex
+
+	parent rowsCommitter
+	rows   []bufferedRow
+}
+
+func newBufferTx(parent rowsCommitter) *bufferTx {
+	tx := &bufferTx{parent: parent}
+	tx.entitySaver = entitySaver{tx}
+	return tx
+}
+
+func (tx *bufferTx) run(fc func(sink Sink) error) error {
+	if err := fc(tx); err != nil {
+		return err
+	}
+
+	return tx.parent.saveRows(tx.rows)
+}
+
+func (tx *bufferTx) saveRows(rows []bufferedRow) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	tx.rows = append(tx.rows, rows...)
+	return nil
+}
+
+// The row is copied since it is written after the handler returned
+func (tx *bufferTx) saveRow(table string, id int, row any) error {
+	rowVal := reflect.Indirect(reflect.ValueOf(row))
+	rowCopy := reflect.New(rowVal.Type())
+	rowCopy.Elem().Set(rowVal)
+
+	return tx.saveRows([]bufferedRow{{table, id, rowCopy.Interface()}})
+}
+
+func (tx *bufferTx) Exists(table string, id int) (bool, error) {
+	if err := checkEntityTable(table); err != nil {
+		return false, err
+	}
+
+	tx.mu.Lock()
+	for _, row := range tx.rows {
+		if row.table == table && row.id == id {
+			tx.mu.Unlock()
+			return true, nil
+		}
+	}
+	tx.mu.Unlock()
+
+	return tx.parent.Exists(table, id)
+}
+
+func (tx *bufferTx) Team(id int) (*models.TeamSchema, error) {
+	tx.mu.Lock()
+	for _, row := range tx.rows {
+		if team, ok := row.row.(*models.TeamSchema); ok && row.table == Teams && team.Id == id {
+			tx.mu.Unlock()
+			return team, nil
+		}
+	}
+	tx.mu.Unlock()
+
+	return tx.parent.Team(id)
+}
+
+// Return the roster of the parent with the buffered members
+func (tx *bufferTx) Roster(teamId int) ([]models.TeamRosterSchema, error) {
+	roster, err := tx.parent.Roster(teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	for _, row := range tx.rows {
+		if member, ok := row.row.(*models.TeamRosterSchema); ok && member.TeamId == teamId {
+			roster = mergeBy(roster, sameRosterMember, *member)
+		}
+	}
+
+	return roster, nil
+}
+
+// Return the rounds of the parent with the buffered rounds
+func (tx *bufferTx) TournamentRounds(tournamentId int) ([]models.TournamentRoundSchema, error) {
+	rounds, err := tx.parent.TournamentRounds(tournamentId)
+	if err != nil {
+		return nil, err
+	}
+
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	for _, row := range tx.rows {
+		if round, ok := row.row.(*models.TournamentRoundSchema); ok && round.TournamentId == tournamentId {
+			rounds = mergeBy(rounds, sameRound, *round)
+		}
+	}
+
+	return rounds, nil
+}
+
+func (tx *bufferTx) LinkScheduledMatch(matchId int) error {
+	return tx.parent.LinkScheduledMatch(matchId)
+}
+
+func (tx *bufferTx) Transaction(fc func(sink Sink) error) error {
+	return newBufferTx(tx).run(fc)
+}
+
+// Close does nothing, the rows are written when the transaction succeed
+func (tx *bufferTx) Close() error {
+	return nil
+}
@@ -0,0 +1,519 @@
+package sinks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"gorm.io/gorm/schema"
+)
+
+// fileFormat write the rows of a table to its file and read them back
+type fileFormat interface {
+	extension() string
+	// Write the row, the file is empty if newFile is true
+	writeRow(w io.Writer, newFile bool, columns []string, values []any) error
+	// Read the rows as the values by column, the null values are missing
+	readRows(r io.Reader) ([]map[string]string, error)
+}
+
+// Files save the rows of every table to <dir>/<table>.<extension>, using the column names of the vlr db.
+// Rows are appended, so the same directory can be filled by several runs. A scheduled match or a row of the keyed
+// tables, e.g a roster member, saved again is appended, its last row is the latest
+type Files struct {
+	entitySaver
+	mu sync.Mutex
+
+	dir     string
+	format  fileFormat
+	files   map[string]*os.File
+	ids     map[string]map[int]bool
+	teams   map[int]models.TeamSchema
+	rosters map[int][]models.TeamRosterSchema
+	rounds  map[int][]models.TournamentRoundSchema
+	schemas sync.Map
+}
+
+// Open the sink writing newline delimited JSON files, one JSON object per row
+func NewNDJSON(dir string) (*Files, error) {
+	return openFiles(dir, ndjsonFormat{})
+}
+
+// Open the sink writing CSV files with a header row
+func NewCSV(dir string) (*Files, error) {
+	return openFiles(dir, csvFormat{})
+}
+
+func openFiles(dir string, format fileFormat) (*Files, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	f := &Files{dir: dir, format: format, files: map[string]*os.File{}, ids: map[string]map[int]bool{}, teams: map[int]models.TeamSchema{}, rosters: map[int][]models.TeamRosterSchema{}, rounds: map[int][]models.TournamentRoundSchema{}}
+	f.entitySaver = entitySaver{f}
+
+	for _, table := range entityTables {
+		f.ids[table] = map[int]bool{}
+
+		rows, err := f.readTable(table)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			id, err := strconv.Atoi(row["id"])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid id '%s' in %s", row["id"], f.filePath(table))
+			}
+
+			f.ids[table][id] = true
+
+			if table == Teams {
+				if f.teams[id], err = teamFromRow(row); err != nil {
+					return nil, fmt.Errorf("Invalid team %d in %s: %s", id, f.filePath(table), err.Error())
+				}
+			}
+		}
+	}
+
+	rows, err := f.readTable(TeamRosters)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		member, err := rosterMemberFromRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid roster member in %s: %s", f.filePath(TeamRosters), err.Error())
+		}
+
+		f.rosters[member.TeamId] = mergeBy(f.rosters[member.TeamId], sameRosterMember, member)
+	}
+
+	if rows, err = f.readTable(TournamentRounds); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		round, err := roundFromRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid round in %s: %s", f.filePath(TournamentRounds), err.Error())
+		}
+
+		f.rounds[round.TournamentId] = mergeBy(f.rounds[round.TournamentId], sameRound, round)
+	}
+
+	return f, nil
+}
+
+// Return the rows of the table, none if its file doesn't exist
+func (f *Files) readTable(table string) ([]map[string]string, error) {
+	file, err := os.Open(f.filePath(table))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rows, err := f.format.readRows(file)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading %s: %s", f.filePath(table), err.Error())
+	}
+
+	return rows, nil
+}
+
+func optionalString(row map[string]string, column string) *string {
+	value, ok := row[column]
+	if !ok {
+		return nil
+	}
+
+	return &value
+}
+
+func optionalInt(row map[string]string, column string) (*int, error) {
+	value, ok := row[column]
+	if !ok {
+		return nil, nil
+	}
+
+	num, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s '%s'", column, value)
+	}
+
+	return &num, nil
+}
+
+func teamFromRow(row map[string]string) (team models.TeamSchema, err error) {
+	if team.Id, err = strconv.Atoi(row["id"]); err != nil {
+		return
+	}
+
+	team.Name = row["name"]
+	team.Url = row["url"]
+	team.ShorthandName = optionalString(row, "shorthand_name")
+	team.ImgUrl = optionalString(row, "img_url")
+
+	if team.CountryId, err = optionalInt(row, "country_id"); err != nil {
+		return
+	}
+
+	team.RegionId, err = optionalInt(row, "region_id")
+	return
+}
+
+func rosterMemberFromRow(row map[string]string) (member models.TeamRosterSchema, err error) {
+	if member.TeamId, err = strconv.Atoi(row["team_id"]); err != nil {
+		return
+	}
+
+	if member.PlayerId, err = strconv.Atoi(row["player_id"]); err != nil {
+		return
+	}
+
+	member.Role = models.RosterRole(row["role"])
+	member.Tag = optionalString(row, "tag")
+	member.Status = models.RosterStatus(row["status"])
+
+	if member.FirstSeen, err = time.Parse(time.RFC3339, row["first_seen"]); err != nil {
+		return
+	}
+
+	member.LastSeen, err = time.Parse(time.RFC3339, row["last_seen"])
+	return
+}
+
+func optionalTime(row map[string]string, column string) (*time.Time, error) {
+	value, ok := row[column]
+	if !ok {
+		return nil, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s '%s'", column, value)
+	}
+
+	return &date, nil
+}
+
+func roundFromRow(row map[string]string) (round models.TournamentRoundSchema, err error) {
+	if round.TournamentId, err = strconv.Atoi(row["tournament_id"]); err != nil {
+		return
+	}
+
+	round.StageName = row["stage_name"]
+	round.Name = row["name"]
+
+	if round.RoundOrder, err = strconv.Atoi(row["round_order"]); err != nil {
+		return
+	}
+
+	if bracket := optionalString(row, "bracket"); bracket != nil {
+		side := models.BracketSide(*bracket)
+		round.Bracket = &side
+	}
+
+	if round.Elimination, err = strconv.ParseBool(row["elimination"]); err != nil {
+		return
+	}
+
+	// The files written before the column was added only have inferred rounds
+	round.Inferred = true
+	if inferred, ok := row["inferred"]; ok {
+		if round.Inferred, err = strconv.ParseBool(inferred); err != nil {
+			return
+		}
+	}
+
+	if round.StartDate, err = optionalTime(row, "start_date"); err != nil {
+		return
+	}
+
+	round.EndDate, err = optionalTime(row, "end_date")
+	return
+}
+
+func (f *Files) filePath(table string) string {
+	return path.Join(f.dir, table+"."+f.format.extension())
+}
+
+// Return the columns and the values of the row, nil pointers are nil values
+func (f *Files) rowValues(row any) ([]string, []any, error) {
+	rowSchema, err := schema.Parse(row, &f.schemas, schema.NamingStrategy{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rowVal := reflect.Indirect(reflect.ValueOf(row))
+
+	var columns []string
+	var values []any
+
+	for _, field := range rowSchema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		fieldVal := reflect.ValueOf(field.ReflectValueOf(context.Background(), rowVal).Interface())
+		for fieldVal.Kind() == reflect.Pointer {
+			if fieldVal.IsNil() {
+				break
+			}
+			fieldVal = fieldVal.Elem()
+		}
+
+		columns = append(columns, field.DBName)
+		if fieldVal.Kind() == reflect.Pointer {
+			values = append(values, nil)
+		} else {
+			values = append(values, fieldVal.Interface())
+		}
+	}
+
+	return columns, values, nil
+}
+
+func (f *Files) saveRows(rows []bufferedRow) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, row := range rows {
+		columns, values, err := f.rowValues(row.row)
+		if err != nil {
+			return err
+		}
+
+		file, ok := f.files[row.table]
+		newFile := false
+
+		if !ok {
+			if file, err = os.OpenFile(f.filePath(row.table), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644); err != nil {
+				return err
+			}
+
+			info, err := file.Stat()
+			if err != nil {
+				file.Close()
+				return err
+			}
+
+			f.files[row.table] = file
+			newFile = info.Size() == 0
+		}
+
+		if err = f.format.writeRow(file, newFile, columns, values); err != nil {
+			return fmt.Errorf("Error writing row to %s: %s", f.filePath(row.table), err.Error())
+		}
+
+		if ids, ok := f.ids[row.table]; ok {
+			ids[row.id] = true
+		}
+
+		switch row := row.row.(type) {
+		case *models.TeamSchema:
+			f.teams[row.Id] = *row
+		case *models.TeamRosterSchema:
+			f.rosters[row.TeamId] = mergeBy(f.rosters[row.TeamId], sameRosterMember, *row)
+		case *models.TournamentRoundSchema:
+			f.rounds[row.TournamentId] = mergeBy(f.rounds[row.TournamentId], sameRound, *row)
+		}
+	}
+
+	return nil
+}
+
+func (f *Files) saveRow(table string, id int, row any) error {
+	return f.saveRows([]bufferedRow{{table, id, row}})
+}
+
+func (f *Files) Exists(table string, id int) (bool, error) {
+	if err := checkEntityTable(table); err != nil {
+		return false, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.ids[table][id], nil
+}
+
+func (f *Files) Team(id int) (*models.TeamSchema, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	team, ok := f.teams[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &team, nil
+}
+
+func (f *Files) Roster(teamId int) ([]models.TeamRosterSchema, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.rosters[teamId]), nil
+}
+
+func (f *Files) TournamentRounds(tournamentId int) ([]models.TournamentRoundSchema, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.rounds[tournamentId]), nil
+}
+
+// LinkScheduledMatch does nothing since the rows are only appended, the scheduled matches are linked to their
+// result by id
+func (f *Files) LinkScheduledMatch(matchId int) error {
+	return nil
+}
+
+// Run fc with a sink buffering the rows, they are written to the files if fc return nil
+func (f *Files) Transaction(fc func(sink Sink) error) error {
+	return newBufferTx(f).run(fc)
+}
+
+func (f *Files) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var errs []error
+	for _, file := range f.files {
+		errs = append(errs, file.Close())
+	}
+
+	f.files = map[string]*os.File{}
+
+	return errors.Join(errs...)
+}
+
+type ndjsonFormat struct{}
+
+func (ndjsonFormat) extension() string {
+	return "ndjson"
+}
+
+func (ndjsonFormat) writeRow(w io.Writer, _ bool, columns []string, values []any) error {
+	row := map[string]any{}
+	for i, column := range columns {
+		row[column] = values[i]
+	}
+
+	jsonDat, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(jsonDat, '\n'))
+	return err
+}
+
+func (ndjsonFormat) readRows(r io.Reader) ([]map[string]string, error) {
+	var rows []map[string]string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var jsonRow map[string]any
+
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.UseNumber()
+		if err := decoder.Decode(&jsonRow); err != nil {
+			return nil, err
+		}
+
+		row := map[string]string{}
+		for column, value := range jsonRow {
+			if value != nil {
+				row[column] = fmt.Sprint(value)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, scanner.Err()
+}
+
+type csvFormat struct{}
+
+func (csvFormat) extension() string {
+	return "csv"
+}
+
+func formatCSVValue(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return value.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func (csvFormat) writeRow(w io.Writer, newFile bool, columns []string, values []any) error {
+	csvWriter := csv.NewWriter(w)
+
+	if newFile {
+		if err := csvWriter.Write(columns); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatCSVValue(value)
+	}
+
+	if err := csvWriter.Write(record); err != nil {
+		return err
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func (csvFormat) readRows(r io.Reader) ([]map[string]string, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, record := range records[1:] {
+		row := map[string]string{}
+		for i, column := range records[0] {
+			if record[i] != "" {
+				row[column] = record[i]
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
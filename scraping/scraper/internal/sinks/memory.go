@@ -0,0 +1,254 @@
+package sinks
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+)
+
+// Memory keep the entities in memory, it is used for dry runs and tests.
+// Marshalled to JSON, it is a document of everything scraped
+type Memory struct {
+	entitySaver
+	mu sync.Mutex
+	// The memory of the enclosing transaction
+	parent *Memory
+
+	Matches     []models.MatchSchema
+	Maps        []models.MatchMapSchema
+	Rounds      []models.RoundStatSchema
+	PlayerStats []models.PlayerOverviewStatSchema
+	Duels       []models.PlayerDuelStatSchema
+	Highlights  []models.PlayerHighlightSchema
+	Teams       []models.TeamSchema
+	Tournaments []models.TournamentSchema
+	Players     []models.PlayerSchema
+	BanPickLog  []models.BanPickLogSchema
+	// The last saved version of every scheduled match
+	ScheduledMatches []models.ScheduledMatchSchema
+	// The last saved version of every roster member, series, stage, round, team rank, agent stat and player team
+	TeamRosters      []models.TeamRosterSchema
+	Series           []models.TournamentSeriesSchema
+	EventStages      []models.TournamentStageSchema
+	EventRounds      []models.TournamentRoundSchema
+	TeamRankings     []models.TeamRankingSchema
+	PlayerAgentStats []models.PlayerAgentStatSchema
+	PlayerTeams      []models.PlayerTeamSchema
+}
+
+func NewMemory() *Memory {
+	m := &Memory{}
+	m.entitySaver = entitySaver{m}
+	return m
+}
+
+func (m *Memory) saveRow(table string, _ int, row any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch row := row.(type) {
+	case *models.MatchSchema:
+		m.Matches = append(m.Matches, *row)
+	case *models.MatchMapSchema:
+		m.Maps = append(m.Maps, *row)
+	case *models.RoundStatSchema:
+		m.Rounds = append(m.Rounds, *row)
+	case *models.PlayerOverviewStatSchema:
+		m.PlayerStats = append(m.PlayerStats, *row)
+	case *models.PlayerDuelStatSchema:
+		m.Duels = append(m.Duels, *row)
+	case *models.PlayerHighlightSchema:
+		m.Highlights = append(m.Highlights, *row)
+	case *models.TeamSchema:
+		m.Teams = append(m.Teams, *row)
+	case *models.TournamentSchema:
+		m.Tournaments = append(m.Tournaments, *row)
+	case *models.PlayerSchema:
+		m.Players = append(m.Players, *row)
+	case *models.BanPickLogSchema:
+		m.BanPickLog = append(m.BanPickLog, *row)
+	case *models.ScheduledMatchSchema:
+		m.ScheduledMatches = slices.DeleteFunc(m.ScheduledMatches, func(scheduledMatch models.ScheduledMatchSchema) bool {
+			return scheduledMatch.Id == row.Id
+		})
+		m.ScheduledMatches = append(m.ScheduledMatches, *row)
+	case *models.TeamRosterSchema:
+		m.TeamRosters = mergeBy(m.TeamRosters, sameRosterMember, *row)
+	case *models.TournamentSeriesSchema:
+		m.Series = mergeBy(m.Series, sameSeries, *row)
+	case *models.TournamentStageSchema:
+		m.EventStages = mergeBy(m.EventStages, sameStage, *row)
+	case *models.TournamentRoundSchema:
+		m.EventRounds = mergeBy(m.EventRounds, sameRound, *row)
+	case *models.TeamRankingSchema:
+		m.TeamRankings = mergeBy(m.TeamRankings, sameRanking, *row)
+	case *models.PlayerAgentStatSchema:
+		m.PlayerAgentStats = mergeBy(m.PlayerAgentStats, sameAgentStat, *row)
+	case *models.PlayerTeamSchema:
+		m.PlayerTeams = mergeBy(m.PlayerTeams, samePlayerTeam, *row)
+	default:
+		return fmt.Errorf("Unable to save %T to %s", row, table)
+	}
+
+	return nil
+}
+
+func (m *Memory) Exists(table string, id int) (bool, error) {
+	if err := checkEntityTable(table); err != nil {
+		return false, err
+	}
+
+	if m.contains(table, id) {
+		return true, nil
+	}
+
+	if m.parent != nil {
+		return m.parent.Exists(table, id)
+	}
+
+	return false, nil
+}
+
+func (m *Memory) contains(table string, id int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch table {
+	case Matches:
+		return slices.ContainsFunc(m.Matches, func(match models.MatchSchema) bool { return match.Id == id })
+	case Teams:
+		return slices.ContainsFunc(m.Teams, func(team models.TeamSchema) bool { return team.Id == id })
+	case Tournaments:
+		return slices.ContainsFunc(m.Tournaments, func(tournament models.TournamentSchema) bool { return tournament.Id == id })
+	case ScheduledMatches:
+		return slices.ContainsFunc(m.ScheduledMatches, func(scheduledMatch models.ScheduledMatchSchema) bool {
+			return scheduledMatch.Id == id
+		})
+	default:
+		return slices.ContainsFunc(m.Players, func(player models.PlayerSchema) bool { return player.Id == id })
+	}
+}
+
+func (m *Memory) Team(id int) (*models.TeamSchema, error) {
+	m.mu.Lock()
+	for _, team := range m.Teams {
+		if team.Id == id {
+			m.mu.Unlock()
+			return &team, nil
+		}
+	}
+	m.mu.Unlock()
+
+	if m.parent != nil {
+		return m.parent.Team(id)
+	}
+
+	return nil, nil
+}
+
+// Return the roster of the enclosing transactions with the members saved to the memory
+func (m *Memory) Roster(teamId int) ([]models.TeamRosterSchema, error) {
+	var roster []models.TeamRosterSchema
+
+	if m.parent != nil {
+		var err error
+		if roster, err = m.parent.Roster(teamId); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, member := range m.TeamRosters {
+		if member.TeamId == teamId {
+			roster = mergeBy(roster, sameRosterMember, member)
+		}
+	}
+
+	return roster, nil
+}
+
+// Return the rounds of the enclosing transactions with the rounds saved to the memory
+func (m *Memory) TournamentRounds(tournamentId int) ([]models.TournamentRoundSchema, error) {
+	var rounds []models.TournamentRoundSchema
+
+	if m.parent != nil {
+		var err error
+		if rounds, err = m.parent.TournamentRounds(tournamentId); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, round := range m.EventRounds {
+		if round.TournamentId == tournamentId {
+			rounds = mergeBy(rounds, sameRound, round)
+		}
+	}
+
+	return rounds, nil
+}
+
+// Link the scheduled match of the memory and of the enclosing transactions
+func (m *Memory) LinkScheduledMatch(matchId int) error {
+	m.mu.Lock()
+	for i := range m.ScheduledMatches {
+		if m.ScheduledMatches[i].Id == matchId {
+			m.ScheduledMatches[i].MatchId = &matchId
+		}
+	}
+	m.mu.Unlock()
+
+	if m.parent != nil {
+		return m.parent.LinkScheduledMatch(matchId)
+	}
+
+	return nil
+}
+
+// Run fc with an empty memory whose entities are appended to m if fc return nil
+func (m *Memory) Transaction(fc func(sink Sink) error) error {
+	tx := NewMemory()
+	tx.parent = m
+	if err := fc(tx); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Matches = append(m.Matches, tx.Matches...)
+	m.Maps = append(m.Maps, tx.Maps...)
+	m.Rounds = append(m.Rounds, tx.Rounds...)
+	m.PlayerStats = append(m.PlayerStats, tx.PlayerStats...)
+	m.Duels = append(m.Duels, tx.Duels...)
+	m.Highlights = append(m.Highlights, tx.Highlights...)
+	m.Teams = append(m.Teams, tx.Teams...)
+	m.Tournaments = append(m.Tournaments, tx.Tournaments...)
+	m.Players = append(m.Players, tx.Players...)
+	m.BanPickLog = append(m.BanPickLog, tx.BanPickLog...)
+	for _, scheduledMatch := range tx.ScheduledMatches {
+		m.ScheduledMatches = slices.DeleteFunc(m.ScheduledMatches, func(saved models.ScheduledMatchSchema) bool {
+			return saved.Id == scheduledMatch.Id
+		})
+		m.ScheduledMatches = append(m.ScheduledMatches, scheduledMatch)
+	}
+	m.TeamRosters = mergeBy(m.TeamRosters, sameRosterMember, tx.TeamRosters...)
+	m.Series = mergeBy(m.Series, sameSeries, tx.Series...)
+	m.EventStages = mergeBy(m.EventStages, sameStage, tx.EventStages...)
+	m.EventRounds = mergeBy(m.EventRounds, sameRound, tx.EventRounds...)
+	m.TeamRankings = mergeBy(m.TeamRankings, sameRanking, tx.TeamRankings...)
+	m.PlayerAgentStats = mergeBy(m.PlayerAgentStats, sameAgentStat, tx.PlayerAgentStats...)
+	m.PlayerTeams = mergeBy(m.PlayerTeams, samePlayerTeam, tx.PlayerTeams...)
+
+	return nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,209 @@
+package sinks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"gorm.io/gorm"
+)
+
+// The tables of the rows of a match, the child tables first. The key columns identify a row among the rows of the
+// match, they are used to pair the old and new rows when the match is scraped again
+var matchTables = []struct {
+	table string
+	keys  []string
+}{
+	{MatchMaps, []string{"map_id"}},
+	{RoundStats, []string{"map_id", "round_no"}},
+	{PlayerOverviewStat, []string{"map_id", "player_id", "side"}},
+	{PlayersDuelStats, []string{"map_id", "team_1_player_id", "team_2_player_id"}},
+	{PlayerHighlights, []string{"map_id", "round_no", "player_id", "highlight_type", "player_against_id"}},
+	{BanPickLog, []string{"veto_order"}},
+	{Matches, []string{"id"}},
+}
+
+// Rescraper is implemented by the sinks which can replace the rows of a saved match
+type Rescraper interface {
+	// Delete the match and its child rows and run fc to save them again, in a single transaction.
+	// Return the changes between the old and new rows, they are saved to the match_changes table
+	Rescrape(matchId int, fc func(sink Sink) error) ([]models.MatchChangeSchema, error)
+}
+
+func matchIdColumn(table string) string {
+	if table == Matches {
+		return "id"
+	}
+
+	return "match_id"
+}
+
+// Return the rows of the match in every table of matchTables, by table
+func loadMatchRows(tx *gorm.DB, matchId int) (map[string][]map[string]any, error) {
+	rows := map[string][]map[string]any{}
+
+	for _, matchTable := range matchTables {
+		var tableRows []map[string]any
+
+		if err := tx.Table(matchTable.table).Where(matchIdColumn(matchTable.table)+" = ?", matchId).Find(&tableRows).Error; err != nil {
+			return nil, fmt.Errorf("Error loading %s of match %d: %s", matchTable.table, matchId, err.Error())
+		}
+
+		rows[matchTable.table] = tableRows
+	}
+
+	return rows, nil
+}
+
+func formatValue(value any) *string {
+	if value == nil {
+		return nil
+	}
+
+	var valueStr string
+
+	switch value := value.(type) {
+	case []byte:
+		valueStr = string(value)
+	case time.Time:
+		valueStr = value.Format(time.RFC3339)
+	default:
+		valueStr = fmt.Sprint(value)
+	}
+
+	return &valueStr
+}
+
+// Return the keys of the rows, a row with the same key columns as a previous row get its index appended
+func rowKeys(rows []map[string]any, keys []string) []string {
+	var rowKeys []string
+	seen := map[string]int{}
+
+	for _, row := range rows {
+		var keyParts []string
+		for _, key := range keys {
+			value := formatValue(row[key])
+			if value == nil {
+				keyParts = append(keyParts, key+"=null")
+			} else {
+				keyParts = append(keyParts, key+"="+*value)
+			}
+		}
+
+		rowKey := strings.Join(keyParts, ",")
+		if n := seen[rowKey]; n > 0 {
+			seen[rowKey]++
+			rowKey = fmt.Sprintf("%s#%d", rowKey, n)
+		} else {
+			seen[rowKey] = 1
+		}
+
+		rowKeys = append(rowKeys, rowKey)
+	}
+
+	return rowKeys
+}
+
+// Return the changes between the old and new rows of a table of the match
+func diffRows(matchId int, table string, keys []string, oldRows, newRows []map[string]any, changedAt time.Time) []models.MatchChangeSchema {
+	var changes []models.MatchChangeSchema
+
+	newChange := func(rowKey string, kind models.ChangeKind) models.MatchChangeSchema {
+		return models.MatchChangeSchema{MatchId: matchId, TableName: table, RowKey: rowKey, Kind: kind, ChangedAt: changedAt}
+	}
+
+	newByKey := map[string]map[string]any{}
+	newKeys := rowKeys(newRows, keys)
+	for i, rowKey := range newKeys {
+		newByKey[rowKey] = newRows[i]
+	}
+
+	oldByKey := map[string]bool{}
+
+	for i, rowKey := range rowKeys(oldRows, keys) {
+		oldByKey[rowKey] = true
+		oldRow := oldRows[i]
+
+		newRow, ok := newByKey[rowKey]
+		if !ok {
+			changes = append(changes, newChange(rowKey, models.RowRemoved))
+			continue
+		}
+
+		var fields []string
+		for field := range oldRow {
+			fields = append(fields, field)
+		}
+		for field := range newRow {
+			if _, ok := oldRow[field]; !ok {
+				fields = append(fields, field)
+			}
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			oldValue, newValue := formatValue(oldRow[field]), formatValue(newRow[field])
+			if oldValue == nil && newValue == nil || oldValue != nil && newValue != nil && *oldValue == *newValue {
+				continue
+			}
+
+			change := newChange(rowKey, models.RowUpdated)
+			change.Field = &field
+			change.OldValue = oldValue
+			change.NewValue = newValue
+
+			changes = append(changes, change)
+		}
+	}
+
+	for _, rowKey := range newKeys {
+		if !oldByKey[rowKey] {
+			changes = append(changes, newChange(rowKey, models.RowAdded))
+		}
+	}
+
+	return changes
+}
+
+func (s *SQLite) Rescrape(matchId int, fc func(sink Sink) error) ([]models.MatchChangeSchema, error) {
+	var changes []models.MatchChangeSchema
+
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		oldRows, err := loadMatchRows(tx, matchId)
+		if err != nil {
+			return err
+		}
+
+		for _, matchTable := range matchTables {
+			if err = tx.Table(matchTable.table).Where(matchIdColumn(matchTable.table)+" = ?", matchId).Delete(map[string]any{}).Error; err != nil {
+				return fmt.Errorf("Error deleting %s of match %d: %s", matchTable.table, matchId, err.Error())
+			}
+		}
+
+		if err = fc(NewSQLite(tx)); err != nil {
+			return err
+		}
+
+		newRows, err := loadMatchRows(tx, matchId)
+		if err != nil {
+			return err
+		}
+
+		changedAt := time.Now().UTC()
+		for _, matchTable := range matchTables {
+			changes = append(changes, diffRows(matchId, matchTable.table, matchTable.keys, oldRows[matchTable.table], newRows[matchTable.table], changedAt)...)
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+
+		return tx.Table(MatchChanges).Create(&changes).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
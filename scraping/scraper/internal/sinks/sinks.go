@@ -0,0 +1,235 @@
+// Package sinks define where the scraped entities are saved, see [Sink]
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+)
+
+// Tables of the scraped entities, the file sinks use them as file names
+const (
+	Matches            = "matches"
+	MatchMaps          = "match_maps"
+	RoundStats         = "round_stats"
+	PlayerOverviewStat = "player_overview_stats"
+	PlayersDuelStats   = "players_duel_stats"
+	PlayerHighlights   = "player_highlights"
+	BanPickLog         = "ban_pick_log"
+	Teams              = "teams"
+	Tournaments        = "tournaments"
+	Players            = "players"
+	ScheduledMatches   = "scheduled_matches"
+	TeamRosters        = "team_rosters"
+	TournamentStages   = "tournament_stages"
+	TournamentRounds   = "tournament_rounds"
+	TournamentSeries   = "tournament_series"
+	TeamRankings       = "team_rankings"
+	PlayerAgentStats   = "player_agent_stats"
+	PlayerTeams        = "player_teams"
+	// The changes of the rows of the matches scraped again, see [Rescraper]
+	MatchChanges = "match_changes"
+)
+
+// The tables whose rows have an id which can be checked with [Sink.Exists]
+var entityTables = []string{Matches, Teams, Tournaments, Players, ScheduledMatches}
+
+// The tables whose rows are identified by their primary key columns, a row saved again replace the previous one
+var keyedTables = []string{TeamRosters, TournamentStages, TournamentRounds, TournamentSeries, TeamRankings, PlayerAgentStats, PlayerTeams}
+
+// Sink save the scraped entities.
+// The reference data (agents, maps, countries and regions) is not part of the sink and is still read from the vlr db
+type Sink interface {
+	SaveMatch(match *models.MatchSchema) error
+	SaveMatchMap(matchMap *models.MatchMapSchema) error
+	SaveRoundStat(roundStat *models.RoundStatSchema) error
+	SavePlayerStat(playerStat *models.PlayerOverviewStatSchema) error
+	SaveDuel(duel *models.PlayerDuelStatSchema) error
+	SaveHighlight(highlight *models.PlayerHighlightSchema) error
+	SaveTeam(team *models.TeamSchema) error
+	SaveTournament(tournament *models.TournamentSchema) error
+	SavePlayer(player *models.PlayerSchema) error
+	SaveBanPickLog(turn *models.BanPickLogSchema) error
+	// Save the match which isn't played yet, a scheduled match saved again replace the previous one
+	SaveScheduledMatch(scheduledMatch *models.ScheduledMatchSchema) error
+	// Link the scheduled match to the scraped result of the match, nothing is done if the match wasn't scheduled
+	LinkScheduledMatch(matchId int) error
+	// Save the series of a tournament, a series saved again with the same url replace the previous one
+	SaveTournamentSeries(series *models.TournamentSeriesSchema) error
+	// Save the stage of a tournament, a stage saved again with the same tournament and name replace the previous one
+	SaveTournamentStage(stage *models.TournamentStageSchema) error
+	// Save the round of a stage, a round saved again with the same tournament, stage and name replace the previous one
+	SaveTournamentRound(round *models.TournamentRoundSchema) error
+	// Save the member of a team roster, a member saved again with the same team, player and role replace the previous one
+	SaveRosterMember(member *models.TeamRosterSchema) error
+	// Save the rank of a team, a rank saved again with the same snapshot date, region and team replace the previous one
+	SaveTeamRanking(ranking *models.TeamRankingSchema) error
+	// Save the stats of a player on an agent, stats saved again with the same player, snapshot date, timespan and agent
+	// replace the previous ones
+	SavePlayerAgentStat(stat *models.PlayerAgentStatSchema) error
+	// Save a team of the team history of a player, a team saved again with the same player, snapshot date and team
+	// replace the previous one
+	SavePlayerTeam(playerTeam *models.PlayerTeamSchema) error
+	// Return the saved team with the id, nil if it isn't saved
+	Team(id int) (*models.TeamSchema, error)
+	// Return the saved rounds of the stages of the tournament
+	TournamentRounds(tournamentId int) ([]models.TournamentRoundSchema, error)
+	// Return the saved roster members of the team, including the former members
+	Roster(teamId int) ([]models.TeamRosterSchema, error)
+	// Return true if the match, team, tournament, player or scheduled match with the id is saved, table is one of
+	// [Matches], [Teams], [Tournaments], [Players] or [ScheduledMatches]
+	Exists(table string, id int) (bool, error)
+	// Run fc with a sink whose entities are only saved if fc return nil, e.g every row of a match or none
+	Transaction(fc func(sink Sink) error) error
+	Close() error
+}
+
+type sinkKey struct{}
+
+// Return a copy of ctx carrying the sink
+func WithSink(ctx context.Context, sink Sink) context.Context {
+	return context.WithValue(ctx, sinkKey{}, sink)
+}
+
+// Return the sink carried by ctx
+func FromContext(ctx context.Context) (Sink, error) {
+	sink, ok := ctx.Value(sinkKey{}).(Sink)
+	if !ok {
+		return nil, fmt.Errorf("Unable to find sink")
+	}
+
+	return sink, nil
+}
+
+// rowSaver is implemented by the sinks which save every entity the same way
+type rowSaver interface {
+	saveRow(table string, id int, row any) error
+}
+
+// entitySaver implement the Save methods of [Sink] over a rowSaver
+type entitySaver struct {
+	rowSaver
+}
+
+func (s entitySaver) SaveMatch(match *models.MatchSchema) error {
+	return s.saveRow(Matches, match.Id, match)
+}
+
+func (s entitySaver) SaveMatchMap(matchMap *models.MatchMapSchema) error {
+	return s.saveRow(MatchMaps, 0, matchMap)
+}
+
+func (s entitySaver) SaveRoundStat(roundStat *models.RoundStatSchema) error {
+	return s.saveRow(RoundStats, 0, roundStat)
+}
+
+func (s entitySaver) SavePlayerStat(playerStat *models.PlayerOverviewStatSchema) error {
+	return s.saveRow(PlayerOverviewStat, 0, playerStat)
+}
+
+func (s entitySaver) SaveDuel(duel *models.PlayerDuelStatSchema) error {
+	return s.saveRow(PlayersDuelStats, 0, duel)
+}
+
+func (s entitySaver) SaveHighlight(highlight *models.PlayerHighlightSchema) error {
+	return s.saveRow(PlayerHighlights, 0, highlight)
+}
+
+func (s entitySaver) SaveTeam(team *models.TeamSchema) error {
+	return s.saveRow(Teams, team.Id, team)
+}
+
+func (s entitySaver) SaveTournament(tournament *models.TournamentSchema) error {
+	return s.saveRow(Tournaments, tournament.Id, tournament)
+}
+
+func (s entitySaver) SavePlayer(player *models.PlayerSchema) error {
+	return s.saveRow(Players, player.Id, player)
+}
+
+func (s entitySaver) SaveBanPickLog(turn *models.BanPickLogSchema) error {
+	return s.saveRow(BanPickLog, 0, turn)
+}
+
+func (s entitySaver) SaveScheduledMatch(scheduledMatch *models.ScheduledMatchSchema) error {
+	return s.saveRow(ScheduledMatches, scheduledMatch.Id, scheduledMatch)
+}
+
+func (s entitySaver) SaveTournamentSeries(series *models.TournamentSeriesSchema) error {
+	return s.saveRow(TournamentSeries, 0, series)
+}
+
+func (s entitySaver) SaveTournamentStage(stage *models.TournamentStageSchema) error {
+	return s.saveRow(TournamentStages, 0, stage)
+}
+
+func (s entitySaver) SaveTournamentRound(round *models.TournamentRoundSchema) error {
+	return s.saveRow(TournamentRounds, 0, round)
+}
+
+func (s entitySaver) SaveRosterMember(member *models.TeamRosterSchema) error {
+	return s.saveRow(TeamRosters, 0, member)
+}
+
+func (s entitySaver) SaveTeamRanking(ranking *models.TeamRankingSchema) error {
+	return s.saveRow(TeamRankings, 0, ranking)
+}
+
+func (s entitySaver) SavePlayerAgentStat(stat *models.PlayerAgentStatSchema) error {
+	return s.saveRow(PlayerAgentStats, 0, stat)
+}
+
+func (s entitySaver) SavePlayerTeam(playerTeam *models.PlayerTeamSchema) error {
+	return s.saveRow(PlayerTeams, 0, playerTeam)
+}
+
+// Return the list with the rows replaced or added by the newer rows, same tell if two rows have the same key
+func mergeBy[T any](list []T, same func(a, b T) bool, newer ...T) []T {
+	for _, row := range newer {
+		list = slices.DeleteFunc(list, func(saved T) bool { return same(saved, row) })
+		list = append(list, row)
+	}
+
+	return list
+}
+
+// Return true if the members are the same team, player and role
+func sameRosterMember(a, b models.TeamRosterSchema) bool {
+	return a.TeamId == b.TeamId && a.PlayerId == b.PlayerId && a.Role == b.Role
+}
+
+func sameSeries(a, b models.TournamentSeriesSchema) bool {
+	return a.Url == b.Url
+}
+
+func sameStage(a, b models.TournamentStageSchema) bool {
+	return a.TournamentId == b.TournamentId && a.Name == b.Name
+}
+
+func sameRound(a, b models.TournamentRoundSchema) bool {
+	return a.TournamentId == b.TournamentId && a.StageName == b.StageName && a.Name == b.Name
+}
+
+func sameRanking(a, b models.TeamRankingSchema) bool {
+	return a.SnapshotDate.Equal(b.SnapshotDate) && a.Region == b.Region && a.TeamId == b.TeamId
+}
+
+func sameAgentStat(a, b models.PlayerAgentStatSchema) bool {
+	return a.PlayerId == b.PlayerId && a.SnapshotDate.Equal(b.SnapshotDate) && a.Timespan == b.Timespan && a.AgentId == b.AgentId
+}
+
+func samePlayerTeam(a, b models.PlayerTeamSchema) bool {
+	return a.PlayerId == b.PlayerId && a.SnapshotDate.Equal(b.SnapshotDate) && a.TeamId == b.TeamId
+}
+
+func checkEntityTable(table string) error {
+	for _, entityTable := range entityTables {
+		if entityTable == table {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Table '%s' has no id", table)
+}
@@ -0,0 +1,378 @@
+package sinks
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var errRollback = fmt.Errorf("Rollback")
+
+// Save team 1 in a committed transaction and team 2 in a rolled back one
+func saveTeams(t *testing.T, sink Sink) {
+	shorthand := "PRX"
+
+	if err := sink.Transaction(func(sink Sink) error {
+		return sink.SaveTeam(&models.TeamSchema{Id: 1, Name: "Paper Rex", ShorthandName: &shorthand, Url: "https://www.vlr.gg/team/624/"})
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sink.Transaction(func(sink Sink) error {
+		if err := sink.SaveTeam(&models.TeamSchema{Id: 2, Name: "Sentinels"}); err != nil {
+			return err
+		}
+
+		exists, err := sink.Exists(Teams, 2)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			t.Errorf("Team 2 should exist inside the transaction")
+		}
+
+		return errRollback
+	}); err != errRollback {
+		t.Fatalf("Want rollback error, get %v", err)
+	}
+}
+
+func checkTeams(t *testing.T, sink Sink) {
+	for id, want := range map[int]bool{1: true, 2: false} {
+		exists, err := sink.Exists(Teams, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exists != want {
+			t.Errorf("Team %d exists should be %t", id, want)
+		}
+	}
+
+	team, err := sink.Team(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if team == nil || team.ShorthandName == nil || *team.ShorthandName != "PRX" || team.Url != "https://www.vlr.gg/team/624/" || team.ImgUrl != nil {
+		t.Errorf("Wrong team 1: %+v", team)
+	}
+
+	if team, err = sink.Team(2); err != nil || team != nil {
+		t.Errorf("Team 2 should be nil, get %+v, %v", team, err)
+	}
+
+	if _, err := sink.Exists(RoundStats, 1); err == nil {
+		t.Errorf("Exists on %s should return error", RoundStats)
+	}
+}
+
+// Save player 1 to the roster of team 1 twice, the second time as a former member
+func saveRoster(t *testing.T, sink Sink) {
+	seenAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, status := range []models.RosterStatus{models.ActiveMember, models.FormerMember} {
+		member := models.TeamRosterSchema{TeamId: 1, PlayerId: 1, Role: models.RosterPlayer, Status: status, FirstSeen: seenAt, LastSeen: seenAt}
+		if err := sink.SaveRosterMember(&member); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func checkRoster(t *testing.T, sink Sink) {
+	roster, err := sink.Roster(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seenAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if len(roster) != 1 || roster[0].Status != models.FormerMember || roster[0].Tag != nil || !roster[0].FirstSeen.Equal(seenAt) {
+		t.Errorf("Want 1 former member, get %+v", roster)
+	}
+}
+
+// Save the grand final of tournament 1 twice, the second time as an elimination round
+func saveRounds(t *testing.T, sink Sink) {
+	endDate := time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)
+
+	for _, elimination := range []bool{false, true} {
+		round := models.TournamentRoundSchema{TournamentId: 1, StageName: "Playoffs", Name: "Grand Final", RoundOrder: 1, Elimination: elimination, EndDate: &endDate}
+		if err := sink.SaveTournamentRound(&round); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func checkRounds(t *testing.T, sink Sink) {
+	rounds, err := sink.TournamentRounds(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rounds) != 1 || !rounds[0].Elimination || rounds[0].StartDate != nil || rounds[0].EndDate == nil || rounds[0].Bracket != nil {
+		t.Errorf("Want 1 elimination round, get %+v", rounds)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	memory := NewMemory()
+
+	saveTeams(t, memory)
+	checkTeams(t, memory)
+	saveRoster(t, memory)
+	checkRoster(t, memory)
+	saveRounds(t, memory)
+	checkRounds(t, memory)
+
+	if err := memory.Transaction(func(sink Sink) error {
+		exists, err := sink.Exists(Teams, 1)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			t.Errorf("Team 1 of the parent should exist inside the transaction")
+		}
+
+		return sink.SaveRoundStat(&models.RoundStatSchema{MatchId: 1})
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(memory.Teams) != 1 || len(memory.Rounds) != 1 {
+		t.Errorf("Want 1 team and 1 round, get %d and %d", len(memory.Teams), len(memory.Rounds))
+	}
+}
+
+func TestFiles(t *testing.T) {
+	for name, open := range map[string]func(dir string) (*Files, error){"ndjson": NewNDJSON, "csv": NewCSV} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			files, err := open(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			saveTeams(t, files)
+			checkTeams(t, files)
+			saveRoster(t, files)
+			checkRoster(t, files)
+			saveRounds(t, files)
+			checkRounds(t, files)
+
+			if err = files.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			// The ids are loaded back from the files
+			if files, err = open(dir); err != nil {
+				t.Fatal(err)
+			}
+			defer files.Close()
+
+			checkTeams(t, files)
+			checkRoster(t, files)
+			checkRounds(t, files)
+
+			if err = files.SaveTeam(&models.TeamSchema{Id: 3, Name: "Fnatic"}); err != nil {
+				t.Fatal(err)
+			}
+
+			teamsDat, err := os.ReadFile(path.Join(dir, Teams+"."+name))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(string(teamsDat)), "\n")
+
+			switch name {
+			case "csv":
+				if len(lines) != 3 || lines[0] != "id,name,shorthand_name,url,img_url,country_id,region_id" {
+					t.Errorf("Wrong csv file:\n%s", teamsDat)
+				}
+
+				if lines[1] != "1,Paper Rex,PRX,https://www.vlr.gg/team/624/,,," {
+					t.Errorf("Wrong csv row: %s", lines[1])
+				}
+			case "ndjson":
+				if len(lines) != 2 || !strings.Contains(lines[0], `"shorthand_name":"PRX"`) || !strings.Contains(lines[0], `"img_url":null`) {
+					t.Errorf("Wrong ndjson file:\n%s", teamsDat)
+				}
+			}
+		})
+	}
+}
+
+// Open an in memory db with the tables of the scraped entities
+func openTestDb(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sqlDb, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDb.SetMaxOpenConns(1)
+
+	for table, schema := range map[string]any{
+		Matches:            &models.MatchSchema{},
+		MatchMaps:          &models.MatchMapSchema{},
+		RoundStats:         &models.RoundStatSchema{},
+		PlayerOverviewStat: &models.PlayerOverviewStatSchema{},
+		PlayersDuelStats:   &models.PlayerDuelStatSchema{},
+		PlayerHighlights:   &models.PlayerHighlightSchema{},
+		BanPickLog:         &models.BanPickLogSchema{},
+		Teams:              &models.TeamSchema{},
+		ScheduledMatches:   &models.ScheduledMatchSchema{},
+		TeamRosters:        &models.TeamRosterSchema{},
+		TournamentRounds:   &models.TournamentRoundSchema{},
+		TeamRankings:       &models.TeamRankingSchema{},
+		MatchChanges:       &models.MatchChangeSchema{},
+	} {
+		if err = db.Table(table).AutoMigrate(schema); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return db
+}
+
+func TestSQLite(t *testing.T) {
+	db := openTestDb(t)
+	sink := NewSQLite(db)
+
+	saveTeams(t, sink)
+	checkTeams(t, sink)
+	saveRoster(t, sink)
+	checkRoster(t, sink)
+	saveRounds(t, sink)
+	checkRounds(t, sink)
+
+	// Saving a team again update it
+	if err := sink.SaveTeam(&models.TeamSchema{Id: 1, Name: "PRX"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	if err := db.Table(Teams).Pluck("name", &names).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if len(names) != 1 || names[0] != "PRX" {
+		t.Errorf("Want team 1 to be updated, get %v", names)
+	}
+
+	// Scheduled matches are replaced and linked to their result
+	for _, bestOf := range []int{3, 5} {
+		if err := sink.SaveScheduledMatch(&models.ScheduledMatchSchema{Id: 1, Url: "https://www.vlr.gg/1/", BestOf: &bestOf}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := sink.LinkScheduledMatch(1); err != nil {
+		t.Fatal(err)
+	}
+
+	var scheduledMatches []models.ScheduledMatchSchema
+	if err := db.Table(ScheduledMatches).Find(&scheduledMatches).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if len(scheduledMatches) != 1 || *scheduledMatches[0].BestOf != 5 || scheduledMatches[0].MatchId == nil || *scheduledMatches[0].MatchId != 1 {
+		t.Errorf("Want 1 linked best of 5 scheduled match, get %+v", scheduledMatches)
+	}
+
+	// The rank of a team saved again on the same snapshot replace the previous one
+	snapshotDate := time.Date(2025, 10, 19, 0, 0, 0, 0, time.UTC)
+	for _, rank := range []int{2, 1} {
+		if err := sink.SaveTeamRanking(&models.TeamRankingSchema{SnapshotDate: snapshotDate, Region: "europe", TeamId: 1, Rank: rank}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var rankings []models.TeamRankingSchema
+	if err := db.Table(TeamRankings).Find(&rankings).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rankings) != 1 || rankings[0].Rank != 1 {
+		t.Errorf("Want team 1 to be ranked 1st, get %+v", rankings)
+	}
+}
+
+func TestRescrape(t *testing.T) {
+	db := openTestDb(t)
+	sink := NewSQLite(db)
+
+	saveMatch := func(sink Sink, team1Score int, rounds ...int) error {
+		if err := sink.SaveMatch(&models.MatchSchema{Id: 1, Url: "https://www.vlr.gg/1/", Team1Score: team1Score}); err != nil {
+			return err
+		}
+
+		for _, roundNo := range rounds {
+			if err := sink.SaveRoundStat(&models.RoundStatSchema{MatchId: 1, MapId: 1, RoundOverviewSchema: models.RoundOverviewSchema{RoundNo: roundNo}}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := saveMatch(sink, 1, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failing scrape leave the match as is
+	if _, err := sink.Rescrape(1, func(sink Sink) error { return errRollback }); err != errRollback {
+		t.Fatalf("Want rollback error, get %v", err)
+	}
+
+	changes, err := sink.Rescrape(1, func(sink Sink) error { return saveMatch(sink, 2, 1, 3) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, change := range changes {
+		desc := fmt.Sprintf("%s %s %s", change.TableName, change.RowKey, change.Kind)
+		if change.Field != nil {
+			desc += fmt.Sprintf(" %s %s->%s", *change.Field, *change.OldValue, *change.NewValue)
+		}
+
+		got = append(got, desc)
+	}
+
+	want := []string{
+		"round_stats map_id=1,round_no=2 removed",
+		"round_stats map_id=1,round_no=3 added",
+		"matches id=1 updated team_1_score 1->2",
+	}
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Wrong changes\nwant %v\nget  %v", want, got)
+	}
+
+	var rounds, saved int64
+	if err = db.Table(RoundStats).Where("match_id = 1").Count(&rounds).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.Table(MatchChanges).Count(&saved).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if rounds != 2 || saved != int64(len(want)) {
+		t.Errorf("Want 2 rounds and %d saved changes, get %d and %d", len(want), rounds, saved)
+	}
+}
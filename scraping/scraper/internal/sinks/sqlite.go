@@ -0,0 +1,98 @@
+package sinks
+
+import (
+	"slices"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLite save the entities to the tables of the vlr db
+type SQLite struct {
+	entitySaver
+	db *gorm.DB
+}
+
+func NewSQLite(db *gorm.DB) *SQLite {
+	s := &SQLite{db: db}
+	s.entitySaver = entitySaver{s}
+	return s
+}
+
+// Return the db the entities are saved to, inside a transaction it is the transaction
+func (s *SQLite) DB() *gorm.DB {
+	return s.db
+}
+
+// The entities with an id and the rows of the keyed tables are upserted so saving them again update them
+func (s *SQLite) saveRow(table string, _ int, row any) error {
+	if checkEntityTable(table) == nil || slices.Contains(keyedTables, table) {
+		return s.db.Table(table).Clauses(clause.OnConflict{UpdateAll: true}).Create(row).Error
+	}
+
+	return s.db.Table(table).Create(row).Error
+}
+
+func (s *SQLite) Exists(table string, id int) (bool, error) {
+	if err := checkEntityTable(table); err != nil {
+		return false, err
+	}
+
+	var exists bool
+
+	if err := s.db.Table(table).Select("count(*) > 0").Where("id = ?", id).Find(&exists).Error; err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (s *SQLite) Team(id int) (*models.TeamSchema, error) {
+	var teams []models.TeamSchema
+
+	if err := s.db.Table(Teams).Where("id = ?", id).Limit(1).Find(&teams).Error; err != nil {
+		return nil, err
+	}
+
+	if len(teams) == 0 {
+		return nil, nil
+	}
+
+	return &teams[0], nil
+}
+
+func (s *SQLite) Roster(teamId int) ([]models.TeamRosterSchema, error) {
+	var roster []models.TeamRosterSchema
+
+	if err := s.db.Table(TeamRosters).Where("team_id = ?", teamId).Find(&roster).Error; err != nil {
+		return nil, err
+	}
+
+	return roster, nil
+}
+
+func (s *SQLite) TournamentRounds(tournamentId int) ([]models.TournamentRoundSchema, error) {
+	var rounds []models.TournamentRoundSchema
+
+	if err := s.db.Table(TournamentRounds).Where("tournament_id = ?", tournamentId).Find(&rounds).Error; err != nil {
+		return nil, err
+	}
+
+	return rounds, nil
+}
+
+func (s *SQLite) LinkScheduledMatch(matchId int) error {
+	return s.db.Table(ScheduledMatches).Where("id = ?", matchId).Update("match_id", matchId).Error
+}
+
+func (s *SQLite) Transaction(fc func(sink Sink) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return fc(NewSQLite(tx))
+	})
+}
+
+// Close does nothing, the db is owned by the caller
+func (s *SQLite) Close() error {
+	return nil
+}
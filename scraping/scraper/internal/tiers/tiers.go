@@ -0,0 +1,146 @@
+// Package tiers classify the tournaments with the tier rules of the vlr db, see [models.TierRuleSchema]
+package tiers
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"gorm.io/gorm"
+)
+
+const tierRulesTable = "tier_rules"
+
+type rule struct {
+	models.TierRuleSchema
+	name   *regexp.Regexp
+	series *regexp.Regexp
+}
+
+// Classifier give the tournaments the tier of the first matching rule
+type Classifier struct {
+	rules []rule
+}
+
+func compilePattern(rule models.TierRuleSchema, pattern *string) (*regexp.Regexp, error) {
+	if pattern == nil {
+		return nil, nil
+	}
+
+	regex, err := regexp.Compile("(?i)" + *pattern)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid pattern '%s' of tier rule %d: %s", *pattern, rule.Id, err.Error())
+	}
+
+	return regex, nil
+}
+
+// Create the classifier from the rules, they are sorted by priority then id
+func NewClassifier(rules []models.TierRuleSchema) (*Classifier, error) {
+	c := &Classifier{}
+
+	rules = slices.Clone(rules)
+	slices.SortStableFunc(rules, func(a, b models.TierRuleSchema) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.Id, b.Id))
+	})
+
+	for _, tierRule := range rules {
+		name, err := compilePattern(tierRule, tierRule.NamePattern)
+		if err != nil {
+			return nil, err
+		}
+
+		series, err := compilePattern(tierRule, tierRule.SeriesPattern)
+		if err != nil {
+			return nil, err
+		}
+
+		c.rules = append(c.rules, rule{tierRule, name, series})
+	}
+
+	return c, nil
+}
+
+// Load the classifier from the tier rules of the db
+func Load(tx *gorm.DB) (*Classifier, error) {
+	var rules []models.TierRuleSchema
+
+	if err := tx.Table(tierRulesTable).Order("priority, id").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("Error loading tier rules: %s", err.Error())
+	}
+
+	return NewClassifier(rules)
+}
+
+func (r rule) match(tournament models.TournamentSchema, series *models.TournamentSeriesSchema) bool {
+	if r.name != nil && !r.name.MatchString(tournament.Name) {
+		return false
+	}
+
+	if r.series != nil && (series == nil || !r.series.MatchString(series.Name) && !r.series.MatchString(series.Url)) {
+		return false
+	}
+
+	return r.MinPrizePool == nil || tournament.PrizePool >= *r.MinPrizePool
+}
+
+// Return the tier of the tournament, series is nil if the tournament isn't part of a series
+func (c *Classifier) Classify(tournament models.TournamentSchema, series *models.TournamentSeriesSchema) models.Tier {
+	for _, rule := range c.rules {
+		if rule.match(tournament, series) {
+			return rule.Tier
+		}
+	}
+
+	return models.UnclassifiedTier
+}
+
+// A tournament whose tier changed
+type Reclassification struct {
+	TournamentId int
+	OldTier      models.Tier
+	NewTier      models.Tier
+}
+
+// Classify the saved tournaments of the db again and update the tiers which changed
+func (c *Classifier) Reclassify(tx *gorm.DB) ([]Reclassification, error) {
+	var tournaments []models.TournamentSchema
+	if err := tx.Table(sinks.Tournaments).Find(&tournaments).Error; err != nil {
+		return nil, fmt.Errorf("Error loading tournaments: %s", err.Error())
+	}
+
+	var seriesList []models.TournamentSeriesSchema
+	if err := tx.Table(sinks.TournamentSeries).Find(&seriesList).Error; err != nil {
+		return nil, fmt.Errorf("Error loading tournament series: %s", err.Error())
+	}
+
+	seriesByUrl := map[string]*models.TournamentSeriesSchema{}
+	for i := range seriesList {
+		seriesByUrl[seriesList[i].Url] = &seriesList[i]
+	}
+
+	var reclassifications []Reclassification
+
+	for _, tournament := range tournaments {
+		var series *models.TournamentSeriesSchema
+		if tournament.SeriesUrl != nil {
+			series = seriesByUrl[*tournament.SeriesUrl]
+		}
+
+		tier := c.Classify(tournament, series)
+		if tier == tournament.Tier {
+			continue
+		}
+
+		if err := tx.Table(sinks.Tournaments).Where("id = ?", tournament.Id).Update("tier", tier).Error; err != nil {
+			return nil, fmt.Errorf("Error updating tier of tournament %d: %s", tournament.Id, err.Error())
+		}
+
+		reclassifications = append(reclassifications, Reclassification{tournament.Id, tournament.Tier, tier})
+	}
+
+	return reclassifications, nil
+}
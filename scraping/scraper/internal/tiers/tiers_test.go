@@ -0,0 +1,136 @@
+package tiers
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/models"
+	"github.com/leminhohoho/vlr-prediction/scraping/scraper/internal/sinks"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func pattern(pattern string) *string {
+	return &pattern
+}
+
+func testRules() []models.TierRuleSchema {
+	minPrizePool := 500000
+
+	return []models.TierRuleSchema{
+		{Id: 1, Priority: 1, NamePattern: pattern(`game changers`), Tier: "game_changers"},
+		{Id: 2, Priority: 2, NamePattern: pattern(`challengers|ascension`), Tier: "tier_2"},
+		{Id: 3, Priority: 3, SeriesPattern: pattern(`vct-20[0-9]{2}`), Tier: "tier_1"},
+		{Id: 4, Priority: 4, MinPrizePool: &minPrizePool, Tier: "tier_1"},
+		{Id: 5, Priority: 5, Tier: "tier_3"},
+	}
+}
+
+func TestClassify(t *testing.T) {
+	classifier, err := NewClassifier(testRules())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vct := &models.TournamentSeriesSchema{Url: "/event-group/74/vct-2025", Name: "VCT 2025"}
+
+	for _, test := range []struct {
+		tournament models.TournamentSchema
+		series     *models.TournamentSeriesSchema
+		want       models.Tier
+	}{
+		{models.TournamentSchema{Name: "Game Changers 2025: EMEA Stage 1", PrizePool: 600000}, vct, "game_changers"},
+		{models.TournamentSchema{Name: "Challengers 2025: MENA Resilience"}, vct, "tier_2"},
+		{models.TournamentSchema{Name: "Valorant Masters Toronto 2025"}, vct, "tier_1"},
+		{models.TournamentSchema{Name: "Esports World Cup 2025", PrizePool: 1250000}, nil, "tier_1"},
+		{models.TournamentSchema{Name: "EPIC.LAN #45", PrizePool: 3017}, nil, "tier_3"},
+	} {
+		if tier := classifier.Classify(test.tournament, test.series); tier != test.want {
+			t.Errorf("%s: want tier %s, get %s", test.tournament.Name, test.want, tier)
+		}
+	}
+
+	empty, err := NewClassifier(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tier := empty.Classify(models.TournamentSchema{Name: "EPIC.LAN #45"}, nil); tier != models.UnclassifiedTier {
+		t.Errorf("Want tier %s without rules, get %s", models.UnclassifiedTier, tier)
+	}
+
+	rules := testRules()
+	slices.Reverse(rules)
+
+	if reversed, err := NewClassifier(rules); err != nil {
+		t.Fatal(err)
+	} else if tier := reversed.Classify(models.TournamentSchema{Name: "Game Changers 2025: EMEA Stage 1"}, vct); tier != "game_changers" {
+		t.Errorf("The rules should be sorted by priority, get tier %s", tier)
+	}
+
+	if _, err = NewClassifier([]models.TierRuleSchema{{Id: 1, NamePattern: pattern(`(`)}}); err == nil {
+		t.Error("Want an error for an invalid pattern")
+	}
+}
+
+func TestReclassify(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for table, schema := range map[string]any{
+		tierRulesTable:         &models.TierRuleSchema{},
+		sinks.Tournaments:      &models.TournamentSchema{},
+		sinks.TournamentSeries: &models.TournamentSeriesSchema{},
+	} {
+		if err = db.Table(table).AutoMigrate(schema); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The rules are loaded by priority, not by id
+	rules := testRules()
+	rules[0].Priority, rules[4].Priority = 5, 1
+
+	if err = db.Table(tierRulesTable).Create(&rules).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.Table(sinks.TournamentSeries).Create(&models.TournamentSeriesSchema{Url: "/event-group/74/vct-2025", Name: "VCT 2025"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	seriesUrl := "/event-group/74/vct-2025"
+	tournaments := []models.TournamentSchema{
+		{Id: 1, Name: "Valorant Masters Toronto 2025", Url: "/event/1", Tier: "tier_1", SeriesUrl: &seriesUrl},
+		{Id: 2, Name: "EPIC.LAN #45", Url: "/event/2", Tier: models.UnclassifiedTier},
+	}
+
+	if err = db.Table(sinks.Tournaments).Create(&tournaments).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	classifier, err := Load(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := classifier.Reclassify(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes) != 2 || changes[0] != (Reclassification{1, "tier_1", "tier_3"}) || changes[1] != (Reclassification{2, models.UnclassifiedTier, "tier_3"}) {
+		t.Fatalf("Wrong reclassifications %+v", changes)
+	}
+
+	var tier models.Tier
+	if err = db.Table(sinks.Tournaments).Select("tier").Where("id = ?", 1).Scan(&tier).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if tier != "tier_3" {
+		t.Errorf("Want the saved tier to be updated to tier_3, get %s", tier)
+	}
+}